@@ -17,6 +17,130 @@ const docTemplate = `{
     "basePath": "{{.BasePath}}",
     "paths": {
         "/addresses/": {
+            "get": {
+                "description": "Lists the address book of the logged-in user. The default address is listed first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "User can view all saved addresses",
+                "operationId": "list-addresses",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add address",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "User can add address",
+                "operationId": "add-address",
+                "parameters": [
+                    {
+                        "description": "User address",
+                        "name": "user_address",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AddressInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/addresses/{id}": {
+            "get": {
+                "description": "Fetch a saved address of the logged-in user using address id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "User can view a saved address",
+                "operationId": "find-address-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the address",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
             "put": {
                 "description": "Update address",
                 "consumes": [
@@ -28,9 +152,16 @@ const docTemplate = `{
                 "tags": [
                     "Users"
                 ],
-                "summary": "User can update existing address",
+                "summary": "User can update one of their saved addresses",
                 "operationId": "update-address",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the address to be updated",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "description": "User address",
                         "name": "user_address",
@@ -62,8 +193,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
-                "description": "Add address",
+            "delete": {
+                "description": "Delete a saved address. If the default address is deleted, the oldest remaining address becomes the default.",
                 "consumes": [
                     "application/json"
                 ],
@@ -73,22 +204,65 @@ const docTemplate = `{
                 "tags": [
                     "Users"
                 ],
-                "summary": "User can add address",
-                "operationId": "add-address",
+                "summary": "User can delete a saved address",
+                "operationId": "delete-address",
                 "parameters": [
                     {
-                        "description": "User address",
-                        "name": "user_address",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "ID of the address",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.AddressInput"
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
                         }
                     }
+                }
+            }
+        },
+        "/addresses/{id}/default": {
+            "put": {
+                "description": "The default address is used for orders placed without a shipping address",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "User can mark a saved address as default",
+                "operationId": "set-default-address",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the address",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -453,9 +627,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/categories/": {
+        "/admin/campaigns": {
             "get": {
-                "description": "Admin, users and unregistered users can see all the available categories",
+                "description": "Lists the campaigns with how many of their codes were redeemed and the discount they cost. Codes given back by cancelled orders are not counted.",
                 "consumes": [
                     "application/json"
                 ],
@@ -463,10 +637,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product Category"
+                    "Campaign"
                 ],
-                "summary": "View all available categories",
-                "operationId": "view-all-categories",
+                "summary": "Admin can see the coupon campaigns",
+                "operationId": "view-campaigns",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -482,53 +656,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "Admin can update category details",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Product Category"
-                ],
-                "summary": "Admin can update category details",
-                "operationId": "update-category",
-                "parameters": [
-                    {
-                        "description": "category info",
-                        "name": "category_details",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.ProductCategory"
-                        }
-                    }
-                ],
-                "responses": {
-                    "202": {
-                        "description": "Accepted",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
-                    },
-                    "422": {
-                        "description": "Unprocessable Entity",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
-                    }
-                }
-            },
             "post": {
-                "description": "Admin can create new category from admin panel",
+                "description": "Generates the given number of single use coupons from the rules, each with a random code. A code is the prefix followed by code length characters from the alphabet.",
                 "consumes": [
                     "application/json"
                 ],
@@ -536,18 +665,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product Category"
+                    "Campaign"
                 ],
-                "summary": "Create new product category",
-                "operationId": "create-category",
+                "summary": "Admin can create a coupon campaign",
+                "operationId": "create-campaign",
                 "parameters": [
                     {
-                        "description": "New category name",
-                        "name": "category_name",
+                        "description": "campaign and the rules of its codes",
+                        "name": "campaign",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.NewCategory"
+                            "$ref": "#/definitions/model.CreateCouponCampaign"
                         }
                     }
                 ],
@@ -573,25 +702,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/categories/{id}": {
+        "/admin/campaigns/{id}/codes": {
             "get": {
-                "description": "Users and admins can fetch details of a specific category using id",
+                "description": "Downloads the codes of the campaign in .csv format, with the order that redeemed each code",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "text/csv"
                 ],
                 "tags": [
-                    "Product Category"
+                    "Campaign"
                 ],
-                "summary": "Fetch details of a specific category using category id",
-                "operationId": "find-category-by-id",
+                "summary": "Admin can download the codes of a campaign",
+                "operationId": "export-campaign-codes",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "category id",
-                        "name": "category_id",
+                        "description": "campaign id",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -599,6 +728,12 @@ const docTemplate = `{
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -618,9 +753,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/coupons/": {
+        "/admin/carriers": {
             "get": {
-                "description": "Admins and users can see all available coupons",
+                "description": "Lists the courier services orders can be shipped with",
                 "consumes": [
                     "application/json"
                 ],
@@ -628,10 +763,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Coupon"
+                    "Shipment"
                 ],
-                "summary": "Admins and users can see all available coupons",
-                "operationId": "view-coupons",
+                "summary": "Admin can list the carriers",
+                "operationId": "list-carriers",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -647,8 +782,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "Admin can update existing coupon",
+            "post": {
+                "description": "Adds a courier service. Tracking url is optional and should contain {tracking_number} in place of the tracking number.",
                 "consumes": [
                     "application/json"
                 ],
@@ -656,24 +791,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Coupon"
+                    "Shipment"
                 ],
-                "summary": "Admin can update existing coupon",
-                "operationId": "update-coupon",
+                "summary": "Admin can add a carrier orders are shipped with",
+                "operationId": "create-carrier",
                 "parameters": [
                     {
-                        "description": "details of coupon to be updated",
-                        "name": "coupon_details",
+                        "description": "Carrier details",
+                        "name": "carrier",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.UpdateCoupon"
+                            "$ref": "#/definitions/model.CreateCarrier"
                         }
                     }
                 ],
                 "responses": {
-                    "202": {
-                        "description": "Accepted",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -691,9 +826,84 @@ const docTemplate = `{
                         }
                     }
                 }
+            }
+        },
+        "/admin/categories/": {
+            "get": {
+                "description": "Admin, users and unregistered users can see all the available categories",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Category"
+                ],
+                "summary": "View all available categories",
+                "operationId": "view-all-categories",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Admin can update category details",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Category"
+                ],
+                "summary": "Admin can update category details",
+                "operationId": "update-category",
+                "parameters": [
+                    {
+                        "description": "category info",
+                        "name": "category_details",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProductCategory"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
             },
             "post": {
-                "description": "Admin can create new coupons",
+                "description": "Admin can create new category from admin panel",
                 "consumes": [
                     "application/json"
                 ],
@@ -701,18 +911,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Coupon"
+                    "Product Category"
                 ],
-                "summary": "Admin can create new coupon",
-                "operationId": "create-coupon",
+                "summary": "Create new product category",
+                "operationId": "create-category",
                 "parameters": [
                     {
-                        "description": "details of new coupon to be created",
-                        "name": "new_coupon_details",
+                        "description": "New category name",
+                        "name": "category_name",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.CreateCoupon"
+                            "$ref": "#/definitions/model.NewCategory"
                         }
                     }
                 ],
@@ -738,9 +948,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/coupons/{coupon_id}": {
+        "/admin/categories/{id}": {
             "get": {
-                "description": "Admins and users can see coupon with id",
+                "description": "Users and admins can fetch details of a specific category using id",
                 "consumes": [
                     "application/json"
                 ],
@@ -748,15 +958,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Coupon"
+                    "Product Category"
                 ],
-                "summary": "Admins and users can see coupon with coupon id",
-                "operationId": "view-coupon-by-id",
+                "summary": "Fetch details of a specific category using category id",
+                "operationId": "find-category-by-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "coupon_id",
-                        "name": "coupon_id",
+                        "description": "category id",
+                        "name": "category_id",
                         "in": "path",
                         "required": true
                     }
@@ -768,6 +978,53 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/cod/pincodes": {
+            "post": {
+                "description": "Adds a pincode to the list of pincodes cash on delivery is available in",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "COD"
+                ],
+                "summary": "Admin can allow cash on delivery in a pincode",
+                "operationId": "add-cod-pincode",
+                "parameters": [
+                    {
+                        "description": "Pincode",
+                        "name": "pincode",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CODPincode"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
@@ -781,9 +1038,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/cod/pincodes/{pincode}": {
             "delete": {
-                "description": "Admin can delete existing coupon",
+                "description": "Removes a pincode from the list of pincodes cash on delivery is available in",
                 "consumes": [
                     "application/json"
                 ],
@@ -791,22 +1050,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Coupon"
+                    "COD"
                 ],
-                "summary": "Admin can delete existing coupon",
-                "operationId": "delete-coupon",
+                "summary": "Admin can remove a pincode from the cash on delivery allowlist",
+                "operationId": "remove-cod-pincode",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "details of coupon to be updated",
-                        "name": "coupon_id",
+                        "description": "Pincode",
+                        "name": "pincode",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "202": {
-                        "description": "Accepted",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -816,19 +1075,13 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
-                    },
-                    "422": {
-                        "description": "Unprocessable Entity",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
                     }
                 }
             }
         },
-        "/admin/dashboard": {
+        "/admin/cod/rules": {
             "get": {
-                "description": "Admin can access dashboard and view details regarding orders, users, products, etc.",
+                "description": "Shows the limits for cash on delivery orders and the pincodes it is available in. Cash on delivery is available in every pincode while the list is empty.",
                 "consumes": [
                     "application/json"
                 ],
@@ -836,10 +1089,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Admin"
+                    "COD"
                 ],
-                "summary": "Admin Dashboard",
-                "operationId": "admin-dashboard",
+                "summary": "Admin can view the cash on delivery rules",
+                "operationId": "view-cod-rules",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -854,11 +1107,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/admin/login": {
-            "post": {
-                "description": "Admin login",
+            },
+            "put": {
+                "description": "Sets the maximum order value and the number of refused deliveries after which a user cannot order cash on delivery. Zero removes a limit.",
                 "consumes": [
                     "application/json"
                 ],
@@ -866,18 +1117,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Admin"
+                    "COD"
                 ],
-                "summary": "Admin Login",
-                "operationId": "admin-login",
+                "summary": "Admin can update the cash on delivery limits",
+                "operationId": "update-cod-rules",
                 "parameters": [
                     {
-                        "description": "Admin login credentials",
-                        "name": "admin_credentials",
+                        "description": "COD limits",
+                        "name": "rules",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.AdminLogin"
+                            "$ref": "#/definitions/model.UpdateCODRules"
                         }
                     }
                 ],
@@ -903,9 +1154,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/logout": {
+        "/admin/coupons/": {
             "get": {
-                "description": "Logs out a logged-in admin from the E-commerce web api admin panel",
+                "description": "Admins and users can see all available coupons",
                 "consumes": [
                     "application/json"
                 ],
@@ -913,26 +1164,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Admin"
+                    "Coupon"
                 ],
-                "summary": "Admin Logout",
-                "operationId": "admin-logout",
+                "summary": "Admins and users can see all available coupons",
+                "operationId": "view-coupons",
                 "responses": {
                     "200": {
-                        "description": "OK"
-                    },
-                    "400": {
-                        "description": "Bad Request"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
-            }
-        },
-        "/admin/orders": {
+            },
             "put": {
-                "description": "Endpoint for updating order status",
+                "description": "Admin can update existing coupon. The targets and users given replace the ones of the coupon.",
                 "consumes": [
                     "application/json"
                 ],
@@ -940,24 +1192,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Order"
+                    "Coupon"
                 ],
-                "summary": "Admin can update order status of any order using order_id",
-                "operationId": "update-order",
+                "summary": "Admin can update existing coupon",
+                "operationId": "update-coupon",
                 "parameters": [
                     {
-                        "description": "Details of the order to be updated",
-                        "name": "order_info",
+                        "description": "details of coupon to be updated",
+                        "name": "coupon_details",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.UpdateOrder"
+                            "$ref": "#/definitions/model.UpdateCoupon"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -975,11 +1227,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/admin/product-items/": {
-            "put": {
-                "description": "Update an existing product item with new information.",
+            },
+            "post": {
+                "description": "Admin can create new coupons. A coupon takes a percent, capped at its max amount, or a flat amount off the items it applies to.\nThe scope limits it to the whole cart or to target categories, brands or product items. Usage limits, a first order only flag\nand target users limit who can use it and how many times.",
                 "consumes": [
                     "application/json"
                 ],
@@ -987,24 +1237,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product Item"
+                    "Coupon"
                 ],
-                "summary": "Update a product item",
-                "operationId": "update-product-item",
+                "summary": "Admin can create new coupon",
+                "operationId": "create-coupon",
                 "parameters": [
                     {
-                        "description": "Product item information to update",
-                        "name": "product_item",
+                        "description": "details of new coupon to be created",
+                        "name": "new_coupon_details",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.ProductItem"
+                            "$ref": "#/definitions/model.CreateCoupon"
                         }
                     }
                 ],
                 "responses": {
-                    "202": {
-                        "description": "Accepted",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -1022,8 +1272,622 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/admin/coupons/redemptions": {
+            "get": {
+                "description": "Admin can see the redemptions of each coupon and the discount they cost. Redemptions given back by cancelled orders are counted as released.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Coupon"
+                ],
+                "summary": "Admin can see how much each coupon was redeemed",
+                "operationId": "coupon-redemption-report",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/coupons/{coupon_id}": {
+            "get": {
+                "description": "Admins and users can see coupon with id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Coupon"
+                ],
+                "summary": "Admins and users can see coupon with coupon id",
+                "operationId": "view-coupon-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "coupon_id",
+                        "name": "coupon_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Admin can delete existing coupon",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Coupon"
+                ],
+                "summary": "Admin can delete existing coupon",
+                "operationId": "delete-coupon",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "details of coupon to be updated",
+                        "name": "coupon_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/dashboard": {
+            "get": {
+                "description": "Admin can access dashboard and view details regarding orders, users, products, etc.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Admin Dashboard",
+                "operationId": "admin-dashboard",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/inventory/reconcile": {
+            "get": {
+                "description": "Lists product items whose stock does not match the sum of their inventory ledger entries",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventory"
+                ],
+                "summary": "Admin can check the stock of product items against the inventory ledger",
+                "operationId": "reconcile-stock",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/login": {
+            "post": {
+                "description": "Admin login",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Admin Login",
+                "operationId": "admin-login",
+                "parameters": [
+                    {
+                        "description": "Admin login credentials",
+                        "name": "admin_credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AdminLogin"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/logout": {
+            "get": {
+                "description": "Logs out a logged-in admin from the E-commerce web api admin panel",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Admin Logout",
+                "operationId": "admin-logout",
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/admin/orders": {
+            "put": {
+                "description": "Endpoint for updating order status",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Admin can update order status of any order using order_id",
+                "operationId": "update-order",
+                "parameters": [
+                    {
+                        "description": "Details of the order to be updated",
+                        "name": "order_info",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateOrder"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/orders/{id}/cod-collection": {
+            "post": {
+                "description": "Status should be collected or refused. Collecting the amount due on the order completes its payment.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "COD"
+                ],
+                "summary": "Admin or courier can record the outcome of a cash on delivery attempt",
+                "operationId": "record-cod-collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the order",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Collection details",
+                        "name": "collection",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CODCollection"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/orders/{id}/history": {
+            "get": {
+                "description": "Lists every change to the order and delivery status of an order, oldest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Admin can view the status history of any order",
+                "operationId": "admin-view-order-status-history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/orders/{id}/refunds": {
+            "post": {
+                "description": "Refunds an amount paid for an order. Without an amount, everything not refunded yet is refunded.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Refund"
+                ],
+                "summary": "Admin can refund a part of an order",
+                "operationId": "create-refund",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the order",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Refund details",
+                        "name": "refund",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateRefund"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/orders/{id}/shipments": {
+            "get": {
+                "description": "Lists the shipments of an order with the order lines they carry and their tracking timeline",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shipment"
+                ],
+                "summary": "Admin can view the shipments of an order",
+                "operationId": "view-order-shipments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the order",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Hands over order lines of a pending order to a carrier. Lines are optional, without them everything of the order that is not shipped yet is sent.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shipment"
+                ],
+                "summary": "Admin can ship an order",
+                "operationId": "create-shipment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the order",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Shipment details",
+                        "name": "shipment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateShipment"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/product-items/": {
+            "put": {
+                "description": "Update an existing product item with new information. Stock is not updated here, use /admin/product-items/{id}/stock to adjust stock.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Item"
+                ],
+                "summary": "Update a product item",
+                "operationId": "update-product-item",
+                "parameters": [
+                    {
+                        "description": "Product item information to update",
+                        "name": "product_item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProductItem"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
                 "description": "This endpoint allows an admin user to create a new product item.",
                 "consumes": [
                     "application/json"
@@ -1032,36 +1896,921 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product Item"
+                    "Product Item"
+                ],
+                "summary": "Creates a new product item",
+                "operationId": "create-product-item",
+                "parameters": [
+                    {
+                        "description": "Product item details",
+                        "name": "product_item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProductItem"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully added new product item",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Failed to add new product item",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unable to process the request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/product-items/{id}": {
+            "get": {
+                "description": "Retrieve a product item by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Item"
+                ],
+                "summary": "Retrieve a product item by ID",
+                "operationId": "find-product-item-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a product item from the system",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product Item"
+                ],
+                "summary": "Deletes a product item from the system",
+                "operationId": "delete-product-item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the product item to be deleted",
+                        "name": "product_item_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/product-items/{id}/stock": {
+            "get": {
+                "description": "Lists the inventory ledger entries of a product item, latest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventory"
+                ],
+                "summary": "Admin can view the stock history of a product item",
+                "operationId": "view-stock-movements",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the product item",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Posts a stock movement to the inventory ledger. Reason should be restock or adjustment, quantity is negative to reduce stock.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventory"
+                ],
+                "summary": "Admin can restock or adjust the stock of a product item",
+                "operationId": "adjust-stock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the product item",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Stock adjustment details",
+                        "name": "adjustment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.StockAdjustment"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/": {
+            "put": {
+                "description": "This endpoint allows an admin user to update a product's details.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Admin can update product details",
+                "operationId": "update-product",
+                "parameters": [
+                    {
+                        "description": "Updated product details",
+                        "name": "updated_product_details",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.Product"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Successfully updated product",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Unable to update product",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Failed to read request body",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Admins can create new product listings",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Admin can create new product listings",
+                "operationId": "create-product",
+                "parameters": [
+                    {
+                        "description": "new product details",
+                        "name": "new_product_details",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.Product"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/{product_id}": {
+            "delete": {
+                "description": "This endpoint allows an admin user to delete a product by ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Deletes a product by ID",
+                "operationId": "delete-product",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID to delete",
+                        "name": "product_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Successfully deleted product",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid product ID",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unable to delete product",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/refunds": {
+            "get": {
+                "description": "Lists refunds in the given status, all refunds are listed when no status is given",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Refund"
+                ],
+                "summary": "Admin can list refunds",
+                "operationId": "list-refunds",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "initiated, processed or failed",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/refunds/{id}/retry": {
+            "put": {
+                "description": "Sends a refund the payment gateway failed to process to the gateway again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Refund"
+                ],
+                "summary": "Admin can retry a failed refund",
+                "operationId": "retry-refund",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the refund",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/returns": {
+            "get": {
+                "description": "Lists return requests in the given status, pending requests are listed when no status is given",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Admin can list return requests",
+                "operationId": "list-returns",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "requested, approved, rejected, picked_up or received",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/returns/{id}/approve": {
+            "put": {
+                "description": "Approves a pending return request and initiates a refund of the order payment",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Admin can approve a return request",
+                "operationId": "approve-return",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the return request",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Note for the user",
+                        "name": "decision",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.ReturnDecision"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/returns/{id}/pickup": {
+            "put": {
+                "description": "Marks an approved return as picked up from the user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Admin can record the pickup of returned products",
+                "operationId": "return-picked-up",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the return request",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/returns/{id}/received": {
+            "put": {
+                "description": "Marks a picked up return as received back in the warehouse and puts the products back into stock",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Admin can mark returned products as received",
+                "operationId": "return-received",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the return request",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/returns/{id}/reject": {
+            "put": {
+                "description": "Rejects a pending return request, a note explaining the rejection is required",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
                 ],
-                "summary": "Creates a new product item",
-                "operationId": "create-product-item",
+                "summary": "Admin can reject a return request",
+                "operationId": "reject-return",
                 "parameters": [
                     {
-                        "description": "Product item details",
-                        "name": "product_item",
+                        "type": "string",
+                        "description": "ID of the return request",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason for rejecting the return",
+                        "name": "decision",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.ProductItem"
+                            "$ref": "#/definitions/model.ReturnDecision"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/sales-report/": {
+            "get": {
+                "description": "Admin can download sales report in .csv format",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Admin can download sales report",
+                "operationId": "sales-report",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/shipments/{id}/events": {
+            "post": {
+                "description": "Posts an in_transit, out_for_delivery or delivered event. Occurred at defaults to now. The order is marked delivered once all of it is shipped and delivered.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shipment"
+                ],
+                "summary": "Admin can post a tracking event to a shipment",
+                "operationId": "add-shipment-event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the shipment",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tracking event",
+                        "name": "event",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ShipmentEvent"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users": {
+            "get": {
+                "description": "Admin can list all registered users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Admin can list all registered users",
+                "operationId": "list-all-users",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to retrieve per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search query string",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter criteria for the users",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Filters written as field:operator:value, the operator is one of eq, in, range or ilike",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sorting criteria for the users",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Sorting in descending order",
+                        "name": "sort_desc",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/block": {
+            "put": {
+                "description": "Admin can block a registered user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Admin can block a user",
+                "operationId": "block-user",
+                "parameters": [
+                    {
+                        "description": "ID of the user to be blocked",
+                        "name": "user_id",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.BlockUser"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Successfully added new product item",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "400": {
-                        "description": "Failed to add new product item",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     },
                     "422": {
-                        "description": "Unable to process the request",
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -1069,9 +2818,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/product-items/{id}": {
-            "get": {
-                "description": "Retrieve a product item by its ID",
+        "/admin/users/unblock/{id}": {
+            "put": {
+                "description": "Admin can unblock a blocked user",
                 "consumes": [
                     "application/json"
                 ],
@@ -1079,15 +2828,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product Item"
+                    "Admin"
                 ],
-                "summary": "Retrieve a product item by ID",
-                "operationId": "find-product-item-by-id",
+                "summary": "Admin can unblock a blocked user",
+                "operationId": "unblock-user",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Product item ID",
-                        "name": "id",
+                        "description": "ID of the user to be unblocked",
+                        "name": "user_id",
                         "in": "path",
                         "required": true
                     }
@@ -1099,8 +2848,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -1112,9 +2861,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Deletes a product item from the system",
+            }
+        },
+        "/admin/users/{id}": {
+            "get": {
+                "description": "Admin can fetch a specific user details using user id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1122,34 +2873,34 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product Item"
+                    "Admin"
                 ],
-                "summary": "Deletes a product item from the system",
-                "operationId": "delete-product-item",
+                "summary": "Admin can fetch a specific user details using user id",
+                "operationId": "find-user-by-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID of the product item to be deleted",
-                        "name": "product_item_id",
+                        "description": "ID of the user to be fetched",
+                        "name": "user_id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "202": {
-                        "description": "Accepted",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -1157,9 +2908,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/products/": {
-            "put": {
-                "description": "This endpoint allows an admin user to update a product's details.",
+        "/admin/users/{id}/wallet": {
+            "get": {
+                "description": "Shows the balance of the wallet of a user along with its history, latest first",
                 "consumes": [
                     "application/json"
                 ],
@@ -1167,44 +2918,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product"
+                    "Wallet"
                 ],
-                "summary": "Admin can update product details",
-                "operationId": "update-product",
+                "summary": "Admin can view the wallet of a user",
+                "operationId": "admin-view-wallet",
                 "parameters": [
                     {
-                        "description": "Updated product details",
-                        "name": "updated_product_details",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.Product"
-                        }
+                        "type": "string",
+                        "description": "ID of the user",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "202": {
-                        "description": "Successfully updated product",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "400": {
-                        "description": "Unable to update product",
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "422": {
-                        "description": "Failed to read request body",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/users/{id}/wallet/credit": {
             "post": {
-                "description": "Admins can create new product listings",
+                "description": "Adds money to the wallet of a user. Reason should be goodwill, referral or adjustment, a note is required.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1212,18 +2963,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product"
+                    "Wallet"
                 ],
-                "summary": "Admin can create new product listings",
-                "operationId": "create-product",
+                "summary": "Admin can credit the wallet of a user",
+                "operationId": "credit-wallet",
                 "parameters": [
                     {
-                        "description": "new product details",
-                        "name": "new_product_details",
+                        "type": "string",
+                        "description": "ID of the user",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Credit details",
+                        "name": "adjustment",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.Product"
+                            "$ref": "#/definitions/model.WalletAdjustment"
                         }
                     }
                 ],
@@ -1240,6 +2998,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "422": {
                         "description": "Unprocessable Entity",
                         "schema": {
@@ -1249,9 +3013,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/products/{product_id}": {
-            "delete": {
-                "description": "This endpoint allows an admin user to delete a product by ID.",
+        "/admin/users/{id}/wallet/debit": {
+            "post": {
+                "description": "Takes money out of the wallet of a user. The balance cannot go below zero, a note is required.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1259,58 +3023,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product"
+                    "Wallet"
                 ],
-                "summary": "Deletes a product by ID",
-                "operationId": "delete-product",
+                "summary": "Admin can debit the wallet of a user",
+                "operationId": "debit-wallet",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Product ID to delete",
-                        "name": "product_id",
+                        "type": "string",
+                        "description": "ID of the user",
+                        "name": "id",
                         "in": "path",
                         "required": true
-                    }
-                ],
-                "responses": {
-                    "202": {
-                        "description": "Successfully deleted product",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid product ID",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
                     },
-                    "422": {
-                        "description": "Unable to delete product",
+                    {
+                        "description": "Debit details",
+                        "name": "adjustment",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/model.WalletAdjustment"
                         }
                     }
-                }
-            }
-        },
-        "/admin/sales-report/": {
-            "get": {
-                "description": "Admin can download sales report in .csv format",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Admin"
                 ],
-                "summary": "Admin can download sales report",
-                "operationId": "sales-report",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -1321,8 +3058,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -1330,9 +3073,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/users": {
+        "/cart": {
             "get": {
-                "description": "Admin can list all registered users",
+                "description": "User can view cart and cart items. The cart is priced from the current prices of its items, with the coupon discount and the tax of their categories. A coupon that no longer applies is removed and the reason is returned in coupon_removed. Items that are out of stock, over their purchase limit or whose price changed since they were added are reported with an issue, and the cart cannot be checked out until they are updated.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1340,48 +3083,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Admin"
-                ],
-                "summary": "Admin can list all registered users",
-                "operationId": "list-all-users",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Page number for pagination",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of items to retrieve per page",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Search query string",
-                        "name": "query",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter criteria for the users",
-                        "name": "filter",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Sorting criteria for the users",
-                        "name": "sort_by",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Sorting in descending order",
-                        "name": "sort_desc",
-                        "in": "query"
-                    }
+                    "Cart"
                 ],
+                "summary": "User can view cart items and total",
+                "operationId": "view-cart",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -1389,8 +3094,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -1402,11 +3107,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/admin/users/block": {
-            "put": {
-                "description": "Admin can block a registered user",
+            },
+            "delete": {
+                "description": "User can remove everything from cart at once",
                 "consumes": [
                     "application/json"
                 ],
@@ -1414,21 +3117,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Admin"
-                ],
-                "summary": "Admin can block a user",
-                "operationId": "block-user",
-                "parameters": [
-                    {
-                        "description": "ID of the user to be blocked",
-                        "name": "user_id",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.BlockUser"
-                        }
-                    }
+                    "Cart"
                 ],
+                "summary": "Remove everything from cart",
+                "operationId": "empty-cart",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -1442,12 +3134,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1457,9 +3143,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/users/unblock/{id}": {
-            "put": {
-                "description": "Admin can unblock a blocked user",
+        "/cart/add/{product_item_id}": {
+            "post": {
+                "description": "User can add product item to the cart",
                 "consumes": [
                     "application/json"
                 ],
@@ -1467,22 +3153,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Admin"
+                    "Cart"
                 ],
-                "summary": "Admin can unblock a blocked user",
-                "operationId": "unblock-user",
+                "summary": "User can add a product item to the cart",
+                "operationId": "add-to-cart",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID of the user to be unblocked",
-                        "name": "user_id",
+                        "description": "product_item_id",
+                        "name": "product_item_id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -1493,8 +3179,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -1502,9 +3194,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/users/{id}": {
-            "get": {
-                "description": "Admin can fetch a specific user details using user id",
+        "/cart/coupon/{coupon_id}": {
+            "post": {
+                "description": "User can add coupon to the cart. The coupon is added only if it applies to the cart as it is priced now.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1512,34 +3204,34 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Admin"
+                    "Cart"
                 ],
-                "summary": "Admin can fetch a specific user details using user id",
-                "operationId": "find-user-by-id",
+                "summary": "User can add a coupon to the cart",
+                "operationId": "add-coupon-to-cart",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID of the user to be fetched",
-                        "name": "user_id",
+                        "description": "coupon_id",
+                        "name": "coupon_id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -1547,9 +3239,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/cart": {
+        "/cart/coupons/applicable": {
             "get": {
-                "description": "User can view cart and cart items",
+                "description": "User can see every coupon that applies to the cart, with the discount it gives, the largest discount first. Expired coupons and coupons the user already used are left out.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1559,8 +3251,8 @@ const docTemplate = `{
                 "tags": [
                     "Cart"
                 ],
-                "summary": "User can view cart items and total",
-                "operationId": "view-cart",
+                "summary": "User can see the coupons that apply to the cart",
+                "operationId": "applicable-coupons",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -1581,9 +3273,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "User can remove everything from cart at once",
+            }
+        },
+        "/cart/coupons/apply-best": {
+            "post": {
+                "description": "User can add the coupon that gives the largest discount on the cart, out of the coupons that apply to it. The coupons that apply are listed along with the cart.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1593,23 +3287,23 @@ const docTemplate = `{
                 "tags": [
                     "Cart"
                 ],
-                "summary": "Remove everything from cart",
-                "operationId": "empty-cart",
+                "summary": "User can add the coupon with the largest discount to the cart",
+                "operationId": "apply-best-coupon",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -1617,9 +3311,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/cart/add/{product_item_id}": {
-            "post": {
-                "description": "User can add product item to the cart",
+        "/cart/items/{product_item_id}": {
+            "put": {
+                "description": "User can set the quantity of a product item in the cart, 0 removes the item. The quantity should be in stock and within the purchase limit of the item. Setting the quantity accepts the current price of the item.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1629,8 +3323,8 @@ const docTemplate = `{
                 "tags": [
                     "Cart"
                 ],
-                "summary": "User can add a product item to the cart",
-                "operationId": "add-to-cart",
+                "summary": "Set the quantity of a product item in the cart",
+                "operationId": "update-cart-item",
                 "parameters": [
                     {
                         "type": "string",
@@ -1638,11 +3332,20 @@ const docTemplate = `{
                         "name": "product_item_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Quantity",
+                        "name": "quantity",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateCartItem"
+                        }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -1659,47 +3362,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
-                    }
-                }
-            }
-        },
-        "/cart/coupon/{coupon_id}": {
-            "post": {
-                "description": "User can add coupon to the cart",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Cart"
-                ],
-                "summary": "User can add a coupon to the cart",
-                "operationId": "add-coupon-to-cart",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "coupon_id",
-                        "name": "coupon_id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "202": {
-                        "description": "Accepted",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -1942,6 +3606,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.PlaceOrder"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key get the first response instead of running the request again",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -1963,6 +3633,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "409": {
+                        "description": "Product item out of stock",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "422": {
                         "description": "Unable to process the request",
                         "schema": {
@@ -1974,7 +3650,7 @@ const docTemplate = `{
         },
         "/orders/buy-all": {
             "post": {
-                "description": "This endpoint allows a user to purchase all items in their cart",
+                "description": "This endpoint allows a user to purchase all items in their cart. Items that are out of stock, over their purchase limit or whose price changed since they were added have to be updated in the cart first.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1995,6 +3671,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.PlaceAllOrders"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key get the first response instead of running the request again",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -2016,6 +3698,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "422": {
                         "description": "Unprocessable Entity",
                         "schema": {
@@ -2025,7 +3713,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/orders/cancel/{order_id}": {
+        "/orders/cancel/{id}": {
             "put": {
                 "description": "Endpoint for cancelling an order associated with a user",
                 "consumes": [
@@ -2043,7 +3731,7 @@ const docTemplate = `{
                     {
                         "type": "integer",
                         "description": "ID of the order to be cancelled",
-                        "name": "order_id",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -2066,6 +3754,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
@@ -2111,9 +3805,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/orders/{order_id}": {
+        "/orders/{id}": {
             "get": {
-                "description": "This function handles requests for retrieving the details of a specific order identified by its order ID. The user must be authorized with a valid cookie to view the order details.",
+                "description": "This function handles requests for retrieving the details of a specific order identified by its order ID. The user must be authorized with a valid cookie to view the order details. The response includes the tracking timeline of every shipment of the order.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2129,7 +3823,7 @@ const docTemplate = `{
                     {
                         "type": "integer",
                         "description": "Order ID",
-                        "name": "order_id",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -2162,6 +3856,109 @@ const docTemplate = `{
                 }
             }
         },
+        "/orders/{id}/history": {
+            "get": {
+                "description": "Lists every change to the order and delivery status of an order of the user, oldest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "User can view the status history of an order",
+                "operationId": "view-order-status-history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/razorpay/webhook": {
+            "post": {
+                "description": "Server to server webhook for captured and failed payments and processed refunds. The body is verified against the X-Razorpay-Signature header, and every event is applied only once by its X-Razorpay-Event-Id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment"
+                ],
+                "summary": "Razorpay notifies payment events",
+                "operationId": "razorpay-webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "HMAC-SHA256 of the body with the webhook secret",
+                        "name": "X-Razorpay-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the event",
+                        "name": "X-Razorpay-Event-Id",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
         "/payments/razorpay/{order_id}": {
             "get": {
                 "description": "Users can make payment via Razorpay after placing orders",
@@ -2183,6 +3980,12 @@ const docTemplate = `{
                         "name": "order_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key get the first response instead of running the request again",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -2209,7 +4012,7 @@ const docTemplate = `{
         },
         "/payments/success/": {
             "get": {
-                "description": "Handler for automatically updating payment details upon successful payment",
+                "description": "Handler for updating payment details once the Razorpay checkout succeeds. The checkout signature is verified against the Razorpay order created for the order.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2224,10 +4027,37 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Payment details",
-                        "name": "c",
+                        "description": "Order id",
+                        "name": "order_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Razorpay order id returned by the checkout",
+                        "name": "razorpay_order_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Razorpay payment id returned by the checkout",
+                        "name": "razorpay_payment_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Razorpay signature returned by the checkout",
+                        "name": "razorpay_signature",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key get the first response instead of running the request again",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -2237,11 +4067,23 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "500": {
+                    "400": {
                         "description": "Failed to update payment details",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "401": {
+                        "description": "Checkout signature is not valid",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Failed to read order id",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
@@ -2285,6 +4127,16 @@ const docTemplate = `{
                         "name": "filter",
                         "in": "query"
                     },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Filters written as field:operator:value, the operator is one of eq, in, range or ilike",
+                        "name": "filters",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "Sorting criteria for the product items",
@@ -2305,6 +4157,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2353,6 +4211,16 @@ const docTemplate = `{
                         "name": "filter",
                         "in": "query"
                     },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Filters written as field:operator:value, the operator is one of eq, in, range or ilike",
+                        "name": "filters",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "Sorting criteria for the product items",
@@ -2367,6 +4235,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2547,6 +4421,42 @@ const docTemplate = `{
                 }
             }
         },
+        "/wallet": {
+            "get": {
+                "description": "Shows the balance of the wallet of the user along with its history, latest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "User can view their wallet",
+                "operationId": "view-wallet",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
         "/wishlist/": {
             "get": {
                 "description": "User view product items in wishlist",
@@ -2707,6 +4617,32 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "domain.CouponDiscountType": {
+            "type": "string",
+            "enum": [
+                "percent",
+                "flat"
+            ],
+            "x-enum-varnames": [
+                "CouponPercent",
+                "CouponFlat"
+            ]
+        },
+        "domain.CouponScope": {
+            "type": "string",
+            "enum": [
+                "cart",
+                "category",
+                "brand",
+                "product_item"
+            ],
+            "x-enum-varnames": [
+                "CouponScopeCart",
+                "CouponScopeCategory",
+                "CouponScopeBrand",
+                "CouponScopeProductItem"
+            ]
+        },
         "domain.Product": {
             "type": "object",
             "required": [
@@ -2757,6 +4693,10 @@ const docTemplate = `{
                 },
                 "id": {
                     "type": "integer"
+                },
+                "tax_percent": {
+                    "description": "tax charged on products of the category",
+                    "type": "number"
                 }
             }
         },
@@ -2784,6 +4724,10 @@ const docTemplate = `{
                 "id": {
                     "type": "integer"
                 },
+                "max_per_order": {
+                    "description": "0 means no limit",
+                    "type": "integer"
+                },
                 "model": {
                     "type": "string"
                 },
@@ -2828,6 +4772,12 @@ const docTemplate = `{
                 "house_number": {
                     "type": "string"
                 },
+                "is_default": {
+                    "type": "boolean"
+                },
+                "label": {
+                    "type": "string"
+                },
                 "landmark": {
                     "type": "string"
                 },
@@ -2865,26 +4815,159 @@ const docTemplate = `{
                 }
             }
         },
+        "model.CODCollection": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "collected_by": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CODPincode": {
+            "type": "object",
+            "required": [
+                "pincode"
+            ],
+            "properties": {
+                "pincode": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreateCarrier": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "tracking_url": {
+                    "type": "string"
+                }
+            }
+        },
         "model.CreateCoupon": {
             "type": "object",
             "properties": {
                 "code": {
                     "type": "string"
                 },
+                "discount_amount": {
+                    "type": "number"
+                },
                 "discount_max_amount": {
                     "type": "number"
                 },
                 "discount_percent": {
                     "type": "number"
                 },
+                "discount_type": {
+                    "$ref": "#/definitions/domain.CouponDiscountType"
+                },
+                "first_order_only": {
+                    "type": "boolean"
+                },
                 "min_order_value": {
                     "type": "number"
                 },
+                "per_user_limit": {
+                    "description": "once per user if not given",
+                    "type": "integer"
+                },
+                "scope": {
+                    "$ref": "#/definitions/domain.CouponScope"
+                },
+                "target_ids": {
+                    "description": "categories, brands or product items, depending on the scope",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "usage_limit": {
+                    "type": "integer"
+                },
+                "user_ids": {
+                    "description": "empty for a coupon for everyone",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "valid_from": {
+                    "type": "string"
+                },
                 "valid_till": {
                     "type": "string"
                 }
             }
         },
+        "model.CreateCouponCampaign": {
+            "type": "object",
+            "properties": {
+                "alphabet": {
+                    "description": "letters and digits that are not easily confused if not given",
+                    "type": "string"
+                },
+                "code_count": {
+                    "type": "integer"
+                },
+                "code_length": {
+                    "description": "of the random part of the codes, 8 if not given",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "rules": {
+                    "description": "shared by the codes, the code and usage limits are set for each code",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.CreateCoupon"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.CreateRefund": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreateShipment": {
+            "type": "object",
+            "properties": {
+                "carrier_id": {
+                    "type": "integer"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ShipmentItem"
+                    }
+                },
+                "tracking_number": {
+                    "type": "string"
+                }
+            }
+        },
         "model.NewAdminInfo": {
             "type": "object",
             "required": [
@@ -2931,6 +5014,9 @@ const docTemplate = `{
                 },
                 "shipping_address_id": {
                     "type": "integer"
+                },
+                "use_wallet": {
+                    "type": "boolean"
                 }
             }
         },
@@ -2946,8 +5032,22 @@ const docTemplate = `{
                 "product_item_id": {
                     "type": "integer"
                 },
+                "quantity": {
+                    "type": "integer"
+                },
                 "shipping_address_id": {
                     "type": "integer"
+                },
+                "use_wallet": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.ReturnDecision": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string"
                 }
             }
         },
@@ -2962,24 +5062,121 @@ const docTemplate = `{
                 }
             }
         },
+        "model.ShipmentEvent": {
+            "type": "object",
+            "properties": {
+                "location": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "occurred_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ShipmentItem": {
+            "type": "object",
+            "properties": {
+                "order_line_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.StockAdjustment": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.UpdateCODRules": {
+            "type": "object",
+            "properties": {
+                "max_order_value": {
+                    "type": "number"
+                },
+                "max_refused_deliveries": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.UpdateCartItem": {
+            "type": "object",
+            "properties": {
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
         "model.UpdateCoupon": {
             "type": "object",
             "properties": {
                 "code": {
                     "type": "string"
                 },
+                "discount_amount": {
+                    "type": "number"
+                },
                 "discount_max_amount": {
                     "type": "number"
                 },
                 "discount_percent": {
                     "type": "number"
                 },
+                "discount_type": {
+                    "$ref": "#/definitions/domain.CouponDiscountType"
+                },
+                "first_order_only": {
+                    "type": "boolean"
+                },
                 "id": {
                     "type": "integer"
                 },
                 "min_order_value": {
                     "type": "number"
                 },
+                "per_user_limit": {
+                    "description": "once per user if not given",
+                    "type": "integer"
+                },
+                "scope": {
+                    "$ref": "#/definitions/domain.CouponScope"
+                },
+                "target_ids": {
+                    "description": "categories, brands or product items, depending on the scope",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "usage_limit": {
+                    "type": "integer"
+                },
+                "user_ids": {
+                    "description": "empty for a coupon for everyone",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "valid_from": {
+                    "type": "string"
+                },
                 "valid_till": {
                     "type": "string"
                 }
@@ -3071,6 +5268,20 @@ const docTemplate = `{
                 }
             }
         },
+        "model.WalletAdjustment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
         "response.Response": {
             "type": "object",
             "properties": {
@@ -1,10 +1,14 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"flag"
 	_ "github.com/amalmadhu06/project-laptop-store-clean-arch/cmd/api/docs"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/config"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/di"
 	"log"
+	"os"
 )
 
 func main() {
@@ -13,6 +17,11 @@ func main() {
 		log.Fatal("cannot load cfg: ", configErr)
 	}
 
+	if len(os.Args) > 1 && os.Args[1] == "reconcile-payments" {
+		reconcilePayments(cfg, os.Args[2:])
+		return
+	}
+
 	server, diErr := di.InitializeAPI(cfg)
 	if diErr != nil {
 		log.Fatal("cannot start server: ", diErr)
@@ -20,3 +29,28 @@ func main() {
 		server.Start()
 	}
 }
+
+// reconcilePayments runs the payment reconciler once and prints the report of what it changed
+//
+//	api reconcile-payments -older-than 30m -expire-after 24h
+func reconcilePayments(cfg config.Config, args []string) {
+	reconciler, diErr := di.InitializePaymentReconciler(cfg)
+	if diErr != nil {
+		log.Fatal("cannot start payment reconciler: ", diErr)
+	}
+
+	flags := flag.NewFlagSet("reconcile-payments", flag.ExitOnError)
+	flags.DurationVar(&reconciler.OlderThan, "older-than", reconciler.OlderThan, "reconcile payments of orders placed before this long ago")
+	flags.DurationVar(&reconciler.ExpireAfter, "expire-after", reconciler.ExpireAfter, "expire unpaid orders placed before this long ago")
+	flags.Parse(args)
+
+	report, err := reconciler.RunOnce(context.Background())
+	if err != nil {
+		log.Fatal("payment reconciliation failed: ", err)
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Fatal("cannot print report: ", err)
+	}
+}
@@ -0,0 +1,42 @@
+// Command webhook-signer signs a webhook payload the way Razorpay does, so the webhook endpoint can be tested locally
+// without a Razorpay account.
+//
+//	webhook-signer -event-id evt_test_1 < payment_captured.json
+//
+// The payload is read from stdin and the secret from RAZORPAY_WEBHOOK_SECRET, and a curl command posting the signed
+// payload to the local server is printed.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/webhook"
+	"io"
+	"log"
+	"os"
+	"strings"
+)
+
+func main() {
+	secret := flag.String("secret", os.Getenv("RAZORPAY_WEBHOOK_SECRET"), "webhook secret, defaults to RAZORPAY_WEBHOOK_SECRET")
+	eventID := flag.String("event-id", "", "value of the X-Razorpay-Event-Id header")
+	url := flag.String("url", "http://localhost:3000/payments/razorpay/webhook", "webhook endpoint")
+	flag.Parse()
+
+	if *secret == "" {
+		log.Fatal("webhook secret is required")
+	}
+	if *eventID == "" {
+		log.Fatal("event id is required")
+	}
+	payload, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		log.Fatal("cannot read payload: ", err)
+	}
+
+	fmt.Printf("curl -X POST %s \\\n", *url)
+	fmt.Printf("\t-H 'Content-Type: application/json' \\\n")
+	fmt.Printf("\t-H 'X-Razorpay-Event-Id: %s' \\\n", *eventID)
+	fmt.Printf("\t-H 'X-Razorpay-Signature: %s' \\\n", webhook.Sign(*secret, payload))
+	fmt.Printf("\t-d '%s'\n", strings.ReplaceAll(string(payload), "'", `'\''`))
+}
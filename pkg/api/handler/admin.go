@@ -226,7 +226,8 @@ func (cr *AdminHandler) SalesReport(c *gin.Context) {
 	c.Header("Content-Disposition", "attachment;filename=laptopstoresales.csv")
 	wr := csv.NewWriter(c.Writer)
 
-	headers := []string{"Order ID", "User ID", "Total", "Coupon Code", "Payment Method", "Order Status", "Delivery Status", "Order Date"}
+	headers := []string{"Order ID", "User ID", "Total", "Coupon Code", "Payment Method", "Payment Status", "COD Collected", "COD Collected By", "Order Status", "Delivery Status", "Order Date",
+		"House Number", "Street", "City", "District", "Pincode", "Landmark", "Address Label"}
 	if err := wr.Write(headers); err != nil {
 		c.JSON(http.StatusInternalServerError, response.Response{StatusCode: 500, Message: "failed to generate sales report", Data: nil, Errors: err.Error()})
 		return
@@ -244,9 +245,19 @@ func (cr *AdminHandler) SalesReport(c *gin.Context) {
 			fmt.Sprintf("%v", sale.Total),
 			sale.CouponCode,
 			sale.PaymentMethod,
+			sale.PaymentStatus,
+			fmt.Sprintf("%v", sale.CODCollected),
+			sale.CODCollectedBy,
 			sale.OrderStatus,
 			sale.DeliveryStatus,
-			sale.OrderDate.Format("2006-01-02 15:04:05")}
+			sale.OrderDate.Format("2006-01-02 15:04:05"),
+			sale.ShippingAddress.HouseNumber,
+			sale.ShippingAddress.Street,
+			sale.ShippingAddress.City,
+			sale.ShippingAddress.District,
+			sale.ShippingAddress.Pincode,
+			sale.ShippingAddress.Landmark,
+			sale.ShippingAddress.Label}
 
 		if err := wr.Write(row); err != nil {
 			c.JSON(http.StatusInternalServerError, response.Response{StatusCode: 500, Message: "failed to generate sales report", Data: nil, Errors: err})
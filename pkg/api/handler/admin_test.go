@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/usecase/mockUsecase"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSalesReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	adminUseCase := mockUsecase.NewMockAdminUseCase(ctrl)
+	adminHandler := NewAdminHandler(adminUseCase)
+
+	r := gin.Default()
+	r.GET("/admin/sales-report", adminHandler.SalesReport)
+
+	adminUseCase.EXPECT().SalesReport(gomock.Any()).Times(1).Return([]model.SalesReport{
+		{
+			OrderID:        4,
+			UserID:         1,
+			Total:          54000,
+			PaymentMethod:  "cash on delivery",
+			PaymentStatus:  "completed",
+			CODCollected:   54000,
+			CODCollectedBy: "agent",
+			OrderStatus:    "completed",
+			DeliveryStatus: "delivered",
+			OrderDate:      time.Date(2023, 4, 2, 10, 30, 0, 0, time.UTC),
+			ShippingAddress: domain.OrderAddress{
+				HouseNumber: "12B",
+				Street:      "MG Road",
+				City:        "Kochi",
+				District:    "Ernakulam",
+				Pincode:     "682016",
+				Landmark:    "near metro, gate 2",
+				Label:       "home",
+			},
+		},
+	}, nil)
+
+	req, err := http.NewRequest(http.MethodGet, "/admin/sales-report", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+	//every order is written with the address it was shipped to, fields with commas are quoted
+	assert.Equal(t, "Order ID,User ID,Total,Coupon Code,Payment Method,Payment Status,COD Collected,COD Collected By,Order Status,"+
+		"Delivery Status,Order Date,House Number,Street,City,District,Pincode,Landmark,Address Label\n"+
+		"4,1,54000,,cash on delivery,completed,54000,agent,completed,delivered,2023-04-02 10:30:00,"+
+		"12B,MG Road,Kochi,Ernakulam,682016,\"near metro, gate 2\",home\n", w.Body.String())
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"encoding/csv"
+	"fmt"
+	services "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/usecase/interface"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type CampaignHandler struct {
+	campaignUseCase services.CampaignUseCase
+}
+
+func NewCampaignHandler(usecase services.CampaignUseCase) *CampaignHandler {
+	return &CampaignHandler{
+		campaignUseCase: usecase,
+	}
+}
+
+// CreateCampaign
+// @Summary Admin can create a coupon campaign
+// @ID create-campaign
+// @Description Generates the given number of single use coupons from the rules, each with a random code. A code is the prefix followed by code length characters from the alphabet.
+// @Tags Campaign
+// @Accept json
+// @Produce json
+// @Param campaign body model.CreateCouponCampaign true "campaign and the rules of its codes"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /admin/campaigns [post]
+func (cr *CampaignHandler) CreateCampaign(c *gin.Context) {
+	var campaign model.CreateCouponCampaign
+	if err := c.Bind(&campaign); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "unable to read the request body", Data: nil, Errors: err.Error()})
+		return
+	}
+	createdCampaign, err := cr.campaignUseCase.CreateCampaign(c.Request.Context(), campaign)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to create campaign", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, response.Response{StatusCode: 201, Message: "successfully created campaign", Data: createdCampaign, Errors: nil})
+}
+
+// ViewCampaigns
+// @Summary Admin can see the coupon campaigns
+// @ID view-campaigns
+// @Description Lists the campaigns with how many of their codes were redeemed and the discount they cost. Codes given back by cancelled orders are not counted.
+// @Tags Campaign
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /admin/campaigns [get]
+func (cr *CampaignHandler) ViewCampaigns(c *gin.Context) {
+	campaigns, err := cr.campaignUseCase.ViewCampaigns(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Response{StatusCode: 500, Message: "failed to fetch campaigns", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{StatusCode: 200, Message: "successfully fetched campaigns", Data: campaigns, Errors: nil})
+}
+
+// ExportCampaignCodes
+// @Summary Admin can download the codes of a campaign
+// @ID export-campaign-codes
+// @Description Downloads the codes of the campaign in .csv format, with the order that redeemed each code
+// @Tags Campaign
+// @Accept json
+// @Produce text/csv
+// @Param id path string true "campaign id"
+// @Success 200 {file} file
+// @Failure 400 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /admin/campaigns/{id}/codes [get]
+func (cr *CampaignHandler) ExportCampaignCodes(c *gin.Context) {
+	campaignID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "unable to parse campaign id", Data: nil, Errors: err.Error()})
+		return
+	}
+	codes, err := cr.campaignUseCase.ViewCampaignCodes(c.Request.Context(), campaignID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to fetch campaign codes", Data: nil, Errors: err.Error()})
+		return
+	}
+
+	//	set headers for downloading in browser
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment;filename=campaign-%v-codes.csv", campaignID))
+	wr := csv.NewWriter(c.Writer)
+
+	if err := wr.Write([]string{"Code", "Valid Till", "Redeemed", "Order ID", "Redeemed At"}); err != nil {
+		c.JSON(http.StatusInternalServerError, response.Response{StatusCode: 500, Message: "failed to export campaign codes", Data: nil, Errors: err.Error()})
+		return
+	}
+	for _, code := range codes {
+		row := []string{code.Code, code.ValidTill.Format("2006-01-02 15:04:05"), "no", "", ""}
+		if code.RedeemedAt != nil {
+			row[2] = "yes"
+			row[3] = fmt.Sprintf("%v", code.OrderID)
+			row[4] = code.RedeemedAt.Format("2006-01-02 15:04:05")
+		}
+		if err := wr.Write(row); err != nil {
+			c.JSON(http.StatusInternalServerError, response.Response{StatusCode: 500, Message: "failed to export campaign codes", Data: nil, Errors: err.Error()})
+			return
+		}
+	}
+	wr.Flush()
+}
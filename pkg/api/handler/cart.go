@@ -1,8 +1,11 @@
 package handler
 
 import (
+	"errors"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/api/handlerUtil"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
 	services "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/usecase/interface"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/response"
 	"github.com/gin-gonic/gin"
 	"net/http"
@@ -89,10 +92,58 @@ func (cr *CartHandler) RemoveFromCart(c *gin.Context) {
 	c.JSON(http.StatusNoContent, response.Response{StatusCode: 204, Message: "Successfully removed product from the cart", Data: nil, Errors: nil})
 }
 
+// UpdateCartItem
+// @Summary Set the quantity of a product item in the cart
+// @ID update-cart-item
+// @Description User can set the quantity of a product item in the cart, 0 removes the item. The quantity should be in stock and within the purchase limit of the item. Setting the quantity accepts the current price of the item.
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Param product_item_id path string true "product_item_id"
+// @Param quantity body model.UpdateCartItem true "Quantity"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /cart/items/{product_item_id} [put]
+func (cr *CartHandler) UpdateCartItem(c *gin.Context) {
+	paramsID := c.Param("product_item_id")
+	productItemID, err := strconv.Atoi(paramsID)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "unable to process the request", Data: nil, Errors: err.Error()})
+		return
+	}
+
+	var body model.UpdateCartItem
+	if err := c.Bind(&body); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "unable to read request body", Data: nil, Errors: err.Error()})
+		return
+	}
+
+	userID, err := handlerUtil.GetUserIdFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, response.Response{StatusCode: 400, Message: "unable to fetch user id from context", Data: nil, Errors: err.Error()})
+		return
+	}
+
+	cart, err := cr.cartUseCase.UpdateCartItem(c.Request.Context(), userID, productItemID, body)
+	var outOfStock *domain.OutOfStockError
+	if errors.As(err, &outOfStock) {
+		c.JSON(http.StatusConflict, response.Response{StatusCode: 409, Message: "failed to update the cart", Data: nil, Errors: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to update the cart", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{StatusCode: 200, Message: "Successfully updated the cart", Data: cart, Errors: nil})
+}
+
 // ViewCart
 // @Summary User can view cart items and total
 // @ID view-cart
-// @Description User can view cart and cart items
+// @Description User can view cart and cart items. The cart is priced from the current prices of its items, with the coupon discount and the tax of their categories. A coupon that no longer applies is removed and the reason is returned in coupon_removed. Items that are out of stock, over their purchase limit or whose price changed since they were added are reported with an issue, and the cart cannot be checked out until they are updated.
 // @Tags Cart
 // @Accept json
 // @Produce json
@@ -144,7 +195,7 @@ func (cr *CartHandler) EmptyCart(c *gin.Context) {
 // AddCouponToCart
 // @Summary User can add a coupon to the cart
 // @ID add-coupon-to-cart
-// @Description User can add coupon to the cart
+// @Description User can add coupon to the cart. The coupon is added only if it applies to the cart as it is priced now.
 // @Tags Cart
 // @Accept json
 // @Produce json
@@ -174,3 +225,55 @@ func (cr *CartHandler) AddCouponToCart(c *gin.Context) {
 	}
 	c.JSON(http.StatusAccepted, response.Response{StatusCode: 202, Message: "successfully added coupon to the cart", Data: cart, Errors: nil})
 }
+
+// ApplicableCoupons
+// @Summary User can see the coupons that apply to the cart
+// @ID applicable-coupons
+// @Description User can see every coupon that applies to the cart, with the discount it gives, the largest discount first. Expired coupons and coupons the user already used are left out.
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /cart/coupons/applicable [get]
+func (cr *CartHandler) ApplicableCoupons(c *gin.Context) {
+	userID, err := handlerUtil.GetUserIdFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, response.Response{StatusCode: 400, Message: "unable to fetch user id from context", Data: nil, Errors: err.Error()})
+		return
+	}
+
+	coupons, err := cr.cartUseCase.ApplicableCoupons(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Response{StatusCode: 500, Message: "failed to fetch applicable coupons", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{StatusCode: 200, Message: "Successfully fetched applicable coupons", Data: coupons, Errors: nil})
+}
+
+// ApplyBestCoupon
+// @Summary User can add the coupon with the largest discount to the cart
+// @ID apply-best-coupon
+// @Description User can add the coupon that gives the largest discount on the cart, out of the coupons that apply to it. The coupons that apply are listed along with the cart.
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Success 202 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /cart/coupons/apply-best [post]
+func (cr *CartHandler) ApplyBestCoupon(c *gin.Context) {
+	userID, err := handlerUtil.GetUserIdFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, response.Response{StatusCode: 400, Message: "unable to fetch user id from context", Data: nil, Errors: err.Error()})
+		return
+	}
+
+	coupons, err := cr.cartUseCase.ApplyBestCoupon(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to add coupon to the cart", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, response.Response{StatusCode: 202, Message: "successfully added coupon to the cart", Data: coupons, Errors: nil})
+}
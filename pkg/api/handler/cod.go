@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/api/handlerUtil"
+	services "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/usecase/interface"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type CODHandler struct {
+	codUseCase services.CODUseCase
+}
+
+func NewCODHandler(usecase services.CODUseCase) *CODHandler {
+	return &CODHandler{
+		codUseCase: usecase,
+	}
+}
+
+// ViewCODRules
+// @Summary Admin can view the cash on delivery rules
+// @ID view-cod-rules
+// @Description Shows the limits for cash on delivery orders and the pincodes it is available in. Cash on delivery is available in every pincode while the list is empty.
+// @Tags COD
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /admin/cod/rules [get]
+func (cr *CODHandler) ViewCODRules(c *gin.Context) {
+	rules, err := cr.codUseCase.ViewCODRules(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Response{StatusCode: 500, Message: "failed to fetch cod rules", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{StatusCode: 200, Message: "successfully fetched cod rules", Data: rules, Errors: nil})
+}
+
+// UpdateCODRules
+// @Summary Admin can update the cash on delivery limits
+// @ID update-cod-rules
+// @Description Sets the maximum order value and the number of refused deliveries after which a user cannot order cash on delivery. Zero removes a limit.
+// @Tags COD
+// @Accept json
+// @Produce json
+// @Param rules body model.UpdateCODRules true "COD limits"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /admin/cod/rules [put]
+func (cr *CODHandler) UpdateCODRules(c *gin.Context) {
+	var rules model.UpdateCODRules
+	if err := c.Bind(&rules); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "unable to read the request body", Data: nil, Errors: err.Error()})
+		return
+	}
+	updatedRules, err := cr.codUseCase.UpdateCODRules(c.Request.Context(), rules)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to update cod rules", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{StatusCode: 200, Message: "successfully updated cod rules", Data: updatedRules, Errors: nil})
+}
+
+// AddCODPincode
+// @Summary Admin can allow cash on delivery in a pincode
+// @ID add-cod-pincode
+// @Description Adds a pincode to the list of pincodes cash on delivery is available in
+// @Tags COD
+// @Accept json
+// @Produce json
+// @Param pincode body model.CODPincode true "Pincode"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /admin/cod/pincodes [post]
+func (cr *CODHandler) AddCODPincode(c *gin.Context) {
+	var body model.CODPincode
+	if err := c.Bind(&body); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "unable to read the request body", Data: nil, Errors: err.Error()})
+		return
+	}
+	pincode, err := cr.codUseCase.AddCODPincode(c.Request.Context(), body.Pincode)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to add pincode", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, response.Response{StatusCode: 201, Message: "successfully added pincode", Data: pincode, Errors: nil})
+}
+
+// RemoveCODPincode
+// @Summary Admin can remove a pincode from the cash on delivery allowlist
+// @ID remove-cod-pincode
+// @Description Removes a pincode from the list of pincodes cash on delivery is available in
+// @Tags COD
+// @Accept json
+// @Produce json
+// @Param pincode path string true "Pincode"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /admin/cod/pincodes/{pincode} [delete]
+func (cr *CODHandler) RemoveCODPincode(c *gin.Context) {
+	pincode := c.Param("pincode")
+	if err := cr.codUseCase.RemoveCODPincode(c.Request.Context(), pincode); err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to remove pincode", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{StatusCode: 200, Message: "successfully removed pincode", Data: nil, Errors: nil})
+}
+
+// RecordCODCollection
+// @Summary Admin or courier can record the outcome of a cash on delivery attempt
+// @ID record-cod-collection
+// @Description Status should be collected or refused. Collecting the amount due on the order completes its payment.
+// @Tags COD
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the order"
+// @Param collection body model.CODCollection true "Collection details"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /admin/orders/{id}/cod-collection [post]
+func (cr *CODHandler) RecordCODCollection(c *gin.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "failed to read order id", Data: nil, Errors: err.Error()})
+		return
+	}
+	var collection model.CODCollection
+	if err := c.Bind(&collection); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "unable to read the request body", Data: nil, Errors: err.Error()})
+		return
+	}
+	collection.OrderID = orderID
+
+	adminID, err := handlerUtil.GetAdminIdFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, response.Response{StatusCode: 400, Message: "failed to fetch admin id", Data: nil, Errors: err.Error()})
+		return
+	}
+	recordedCollection, err := cr.codUseCase.RecordCODCollection(c.Request.Context(), collection, adminID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to record cod collection", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, response.Response{StatusCode: 201, Message: "successfully recorded cod collection", Data: recordedCollection, Errors: nil})
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/api/handlerUtil"
+	services "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/usecase/interface"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type InventoryHandler struct {
+	inventoryUseCase services.InventoryUseCase
+}
+
+func NewInventoryHandler(usecase services.InventoryUseCase) *InventoryHandler {
+	return &InventoryHandler{
+		inventoryUseCase: usecase,
+	}
+}
+
+// AdjustStock
+// @Summary Admin can restock or adjust the stock of a product item
+// @ID adjust-stock
+// @Description Posts a stock movement to the inventory ledger. Reason should be restock or adjustment, quantity is negative to reduce stock.
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the product item"
+// @Param adjustment body model.StockAdjustment true "Stock adjustment details"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /admin/product-items/{id}/stock [post]
+func (cr *InventoryHandler) AdjustStock(c *gin.Context) {
+	productItemID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "failed to read product item id", Data: nil, Errors: err.Error()})
+		return
+	}
+	var adjustment model.StockAdjustment
+	if err := c.Bind(&adjustment); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "unable to read the request body", Data: nil, Errors: err.Error()})
+		return
+	}
+	adjustment.ProductItemID = productItemID
+
+	adminID, err := handlerUtil.GetAdminIdFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, response.Response{StatusCode: 400, Message: "failed to fetch admin id", Data: nil, Errors: err.Error()})
+		return
+	}
+
+	movement, err := cr.inventoryUseCase.AdjustStock(c.Request.Context(), adjustment, adminID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to adjust stock", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, response.Response{StatusCode: 201, Message: "successfully adjusted stock", Data: movement, Errors: nil})
+}
+
+// ViewStockMovements
+// @Summary Admin can view the stock history of a product item
+// @ID view-stock-movements
+// @Description Lists the inventory ledger entries of a product item, latest first
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the product item"
+// @Success 200 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /admin/product-items/{id}/stock [get]
+func (cr *InventoryHandler) ViewStockMovements(c *gin.Context) {
+	productItemID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "failed to read product item id", Data: nil, Errors: err.Error()})
+		return
+	}
+	movements, err := cr.inventoryUseCase.ViewStockMovements(c.Request.Context(), productItemID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Response{StatusCode: 500, Message: "failed to fetch stock history", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{StatusCode: 200, Message: "successfully fetched stock history", Data: movements, Errors: nil})
+}
+
+// ReconcileStock
+// @Summary Admin can check the stock of product items against the inventory ledger
+// @ID reconcile-stock
+// @Description Lists product items whose stock does not match the sum of their inventory ledger entries
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /admin/inventory/reconcile [get]
+func (cr *InventoryHandler) ReconcileStock(c *gin.Context) {
+	mismatches, err := cr.inventoryUseCase.ReconcileStock(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Response{StatusCode: 500, Message: "failed to reconcile stock", Data: nil, Errors: err.Error()})
+		return
+	}
+	message := "stock matches the inventory ledger"
+	if len(mismatches) > 0 {
+		message = "found product items with stock not matching the inventory ledger"
+	}
+	c.JSON(http.StatusOK, response.Response{StatusCode: 200, Message: message, Data: mismatches, Errors: nil})
+}
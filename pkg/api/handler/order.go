@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"errors"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/api/handlerUtil"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
 	services "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/usecase/interface"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/response"
@@ -28,9 +30,11 @@ func NewOrderHandler(usecase services.OrderUseCases) *OrderHandler {
 // @Accept json
 // @Produce json
 // @Param order_details body model.PlaceOrder true "Order Details"
+// @Param Idempotency-Key header string false "Retries with the same key get the first response instead of running the request again"
 // @Success 201 {object} response.Response "Successfully ordered product item"
 // @Failure 400 {object} response.Response "Failed to order the product item"
 // @Failure 401 {object} response.Response "Unable to fetch authentication cookie"
+// @Failure 409 {object} response.Response "Product item out of stock"
 // @Failure 422 {object} response.Response "Unable to process the request"
 // @Router /orders/ [post]
 func (cr *OrderHandler) BuyProductItem(c *gin.Context) {
@@ -47,6 +51,11 @@ func (cr *OrderHandler) BuyProductItem(c *gin.Context) {
 	}
 
 	order, err := cr.orderUseCase.BuyProductItem(c.Request.Context(), userID, body)
+	var outOfStock *domain.OutOfStockError
+	if errors.As(err, &outOfStock) {
+		c.JSON(http.StatusConflict, response.Response{StatusCode: 409, Message: "failed to order the product item", Data: nil, Errors: err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to order the product item", Data: nil, Errors: err.Error()})
 		return
@@ -57,14 +66,16 @@ func (cr *OrderHandler) BuyProductItem(c *gin.Context) {
 // BuyAll
 // @Summary Buy all items from the user's cart
 // @ID buyAll
-// @Description This endpoint allows a user to purchase all items in their cart
+// @Description This endpoint allows a user to purchase all items in their cart. Items that are out of stock, over their purchase limit or whose price changed since they were added have to be updated in the cart first.
 // @Tags Order
 // @Accept json
 // @Produce json
 // @Param order_details body model.PlaceAllOrders true "Order Details"
+// @Param Idempotency-Key header string false "Retries with the same key get the first response instead of running the request again"
 // @Success 201 {object} response.Response
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
+// @Failure 409 {object} response.Response
 // @Failure 422 {object} response.Response
 // @Router /orders/buy-all [post]
 func (cr *OrderHandler) BuyAll(c *gin.Context) {
@@ -81,6 +92,12 @@ func (cr *OrderHandler) BuyAll(c *gin.Context) {
 	}
 
 	order, err := cr.orderUseCase.BuyAll(c.Request.Context(), userID, body)
+	var outOfStock *domain.OutOfStockError
+	var cartItemErr *domain.CartItemError
+	if errors.As(err, &outOfStock) || errors.As(err, &cartItemErr) {
+		c.JSON(http.StatusConflict, response.Response{StatusCode: 409, Message: "failed to create order", Data: nil, Errors: err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to create order", Data: nil, Errors: err.Error()})
 		return
@@ -91,18 +108,18 @@ func (cr *OrderHandler) BuyAll(c *gin.Context) {
 // ViewOrderByID function retrieves order details for a given order ID, if authorized.
 // @Summary Retrieves order details for a given order ID, if authorized.
 // @ID view-order-by-id
-// @Description This function handles requests for retrieving the details of a specific order identified by its order ID. The user must be authorized with a valid cookie to view the order details.
+// @Description This function handles requests for retrieving the details of a specific order identified by its order ID. The user must be authorized with a valid cookie to view the order details. The response includes the tracking timeline of every shipment of the order.
 // @Tags Order
 // @Accept json
 // @Produce json
-// @Param order_id path int true "Order ID"
+// @Param id path int true "Order ID"
 // @Success 200 {object} response.Response "Successfully fetched order details"
 // @Failure 400 {object} response.Response "Failed to fetch order details"
 // @Failure 401 {object} response.Response "Failed to authorize user"
 // @Failure 422 {object} response.Response "Failed to read order ID from path"
-// @Router /orders/{order_id} [get]
+// @Router /orders/{id} [get]
 func (cr *OrderHandler) ViewOrderByID(c *gin.Context) {
-	paramsId := c.Param("order_id")
+	paramsId := c.Param("id")
 	orderID, err := strconv.Atoi(paramsId)
 	if err != nil {
 		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "failed to read order id from path", Data: nil, Errors: err.Error()})
@@ -154,13 +171,14 @@ func (cr *OrderHandler) ViewAllOrders(c *gin.Context) {
 // @Tags Order
 // @Accept json
 // @Produce json
-// @Param order_id path int true "ID of the order to be cancelled"
+// @Param id path int true "ID of the order to be cancelled"
 // @Success 200 {object} response.Response
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
-// @Router /orders/cancel/{order_id} [put]
+// @Failure 409 {object} response.Response
+// @Router /orders/cancel/{id} [put]
 func (cr *OrderHandler) CancelOrder(c *gin.Context) {
-	paramsId := c.Param("order_id")
+	paramsId := c.Param("id")
 	orderID, err := strconv.Atoi(paramsId)
 	if err != nil {
 		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "failed to read order id from path", Data: nil, Errors: err.Error()})
@@ -172,6 +190,11 @@ func (cr *OrderHandler) CancelOrder(c *gin.Context) {
 		return
 	}
 	order, err := cr.orderUseCase.CancelOrder(c.Request.Context(), orderID, userID)
+	var statusErr *domain.OrderStatusError
+	if errors.As(err, &statusErr) {
+		c.JSON(http.StatusConflict, response.Response{StatusCode: 409, Message: "failed to cancel order", Data: nil, Errors: err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to cancel order", Data: nil, Errors: err.Error()})
 		return
@@ -189,6 +212,7 @@ func (cr *OrderHandler) CancelOrder(c *gin.Context) {
 // @Param order_info body model.UpdateOrder true "Details of the order to be updated"
 // @Success 200 {object} response.Response
 // @Failure 400 {object} response.Response
+// @Failure 409 {object} response.Response
 // @Failure 422 {object} response.Response
 // @Router /admin/orders [put]
 func (cr *OrderHandler) UpdateOrder(c *gin.Context) {
@@ -198,6 +222,11 @@ func (cr *OrderHandler) UpdateOrder(c *gin.Context) {
 		return
 	}
 	order, err := cr.orderUseCase.UpdateOrder(c.Request.Context(), body)
+	var statusErr *domain.OrderStatusError
+	if errors.As(err, &statusErr) {
+		c.JSON(http.StatusConflict, response.Response{StatusCode: 409, Message: "failed to update order", Data: nil, Errors: err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to update order", Data: nil, Errors: err.Error()})
 		return
@@ -205,6 +234,64 @@ func (cr *OrderHandler) UpdateOrder(c *gin.Context) {
 	c.JSON(http.StatusOK, response.Response{StatusCode: 200, Message: "successfully updated order", Data: order, Errors: nil})
 }
 
+// ViewOrderStatusHistory
+// @Summary User can view the status history of an order
+// @ID view-order-status-history
+// @Description Lists every change to the order and delivery status of an order of the user, oldest first
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /orders/{id}/history [get]
+func (cr *OrderHandler) ViewOrderStatusHistory(c *gin.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "failed to read order id from path", Data: nil, Errors: err.Error()})
+		return
+	}
+	userID, err := handlerUtil.GetUserIdFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, response.Response{StatusCode: 400, Message: "unable to fetch user id from context", Data: nil, Errors: err.Error()})
+		return
+	}
+	history, err := cr.orderUseCase.ViewOrderStatusHistory(c.Request.Context(), orderID, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to fetch order status history", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{StatusCode: 200, Message: "successfully fetched order status history", Data: history, Errors: nil})
+}
+
+// AdminViewOrderStatusHistory
+// @Summary Admin can view the status history of any order
+// @ID admin-view-order-status-history
+// @Description Lists every change to the order and delivery status of an order, oldest first
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /admin/orders/{id}/history [get]
+func (cr *OrderHandler) AdminViewOrderStatusHistory(c *gin.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "failed to read order id from path", Data: nil, Errors: err.Error()})
+		return
+	}
+	history, err := cr.orderUseCase.AdminViewOrderStatusHistory(c.Request.Context(), orderID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to fetch order status history", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{StatusCode: 200, Message: "successfully fetched order status history", Data: history, Errors: nil})
+}
+
 // ReturnRequest
 // @Summary User can request for returning products within 15 days of order delivery
 // @ID return-request
@@ -234,3 +321,141 @@ func (cr *OrderHandler) ReturnRequest(c *gin.Context) {
 	}
 	c.JSON(http.StatusAccepted, response.Response{StatusCode: 202, Message: "successfully placed return request", Data: order, Errors: nil})
 }
+
+// ListReturns
+// @Summary Admin can list return requests
+// @ID list-returns
+// @Description Lists return requests in the given status, pending requests are listed when no status is given
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Param status query string false "requested, approved, rejected, picked_up or received"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /admin/returns [get]
+func (cr *OrderHandler) ListReturns(c *gin.Context) {
+	returns, err := cr.orderUseCase.ListReturns(c.Request.Context(), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to fetch return requests", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{StatusCode: 200, Message: "successfully fetched return requests", Data: returns, Errors: nil})
+}
+
+// ApproveReturn
+// @Summary Admin can approve a return request
+// @ID approve-return
+// @Description Approves a pending return request and initiates a refund of the order payment
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the return request"
+// @Param decision body model.ReturnDecision false "Note for the user"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /admin/returns/{id}/approve [put]
+func (cr *OrderHandler) ApproveReturn(c *gin.Context) {
+	returnID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "failed to read return id", Data: nil, Errors: err.Error()})
+		return
+	}
+	var body model.ReturnDecision
+	if c.Request.ContentLength > 0 {
+		if err := c.Bind(&body); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "failed to read request body", Data: nil, Errors: err.Error()})
+			return
+		}
+	}
+	returnDetails, err := cr.orderUseCase.ApproveReturn(c.Request.Context(), returnID, body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to approve return", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{StatusCode: 200, Message: "successfully approved return", Data: returnDetails, Errors: nil})
+}
+
+// RejectReturn
+// @Summary Admin can reject a return request
+// @ID reject-return
+// @Description Rejects a pending return request, a note explaining the rejection is required
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the return request"
+// @Param decision body model.ReturnDecision true "Reason for rejecting the return"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /admin/returns/{id}/reject [put]
+func (cr *OrderHandler) RejectReturn(c *gin.Context) {
+	returnID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "failed to read return id", Data: nil, Errors: err.Error()})
+		return
+	}
+	var body model.ReturnDecision
+	if err := c.Bind(&body); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "failed to read request body", Data: nil, Errors: err.Error()})
+		return
+	}
+	returnDetails, err := cr.orderUseCase.RejectReturn(c.Request.Context(), returnID, body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to reject return", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{StatusCode: 200, Message: "successfully rejected return", Data: returnDetails, Errors: nil})
+}
+
+// ReturnPickedUp
+// @Summary Admin can record the pickup of returned products
+// @ID return-picked-up
+// @Description Marks an approved return as picked up from the user
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the return request"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /admin/returns/{id}/pickup [put]
+func (cr *OrderHandler) ReturnPickedUp(c *gin.Context) {
+	returnID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "failed to read return id", Data: nil, Errors: err.Error()})
+		return
+	}
+	returnDetails, err := cr.orderUseCase.ReturnPickedUp(c.Request.Context(), returnID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to mark return as picked up", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{StatusCode: 200, Message: "successfully marked return as picked up", Data: returnDetails, Errors: nil})
+}
+
+// ReturnReceived
+// @Summary Admin can mark returned products as received
+// @ID return-received
+// @Description Marks a picked up return as received back in the warehouse and puts the products back into stock
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the return request"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /admin/returns/{id}/received [put]
+func (cr *OrderHandler) ReturnReceived(c *gin.Context) {
+	returnID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "failed to read return id", Data: nil, Errors: err.Error()})
+		return
+	}
+	returnDetails, err := cr.orderUseCase.ReturnReceived(c.Request.Context(), returnID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to mark return as received", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{StatusCode: 200, Message: "successfully marked return as received", Data: returnDetails, Errors: nil})
+}
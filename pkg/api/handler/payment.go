@@ -1,8 +1,9 @@
 package handler
 
 import (
-	"fmt"
+	"errors"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/api/handlerUtil"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
 	services "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/usecase/interface"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/response"
@@ -30,6 +31,7 @@ func NewPaymentHandler(paymentUseCase services.PaymentUseCases) *PaymentHandler
 // @Accept json
 // @Produce json
 // @Param order_id path string true "Order id"
+// @Param Idempotency-Key header string false "Retries with the same key get the first response instead of running the request again"
 // @Success 200 {object} response.Response
 // @Failure 400 {object} response.Response
 // @Failure 500 {object} response.Response
@@ -48,22 +50,22 @@ func (cr *PaymentHandler) CreateRazorpayPayment(c *gin.Context) {
 		return
 	}
 
-	order, razorpayID, err := cr.paymentUseCase.CreateRazorpayPayment(c.Request.Context(), userID, orderID)
-	fmt.Println("order total :", order.OrderTotal)
-	fmt.Println(razorpayID)
+	checkout, err := cr.paymentUseCase.CreateRazorpayPayment(c.Request.Context(), userID, orderID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, response.Response{StatusCode: 500, Message: "failed to complete order", Data: nil, Errors: err.Error()})
 		return
 	}
 
+	order := checkout.Order
 	c.HTML(200, "app.html", gin.H{
+		"key":         checkout.KeyID,
 		"UserID":      order.UserID,
-		"total_price": order.OrderTotal,
-		"total":       order.OrderTotal,
+		"total_price": checkout.Amount,
+		"total":       checkout.Amount,
 		"orderData":   order.ID,
-		"orderid":     razorpayID,
+		"orderid":     checkout.GatewayOrderID,
 		//"orderid":      order.ID,
-		"amount":       order.OrderTotal,
+		"amount":       checkout.Amount,
 		"Email":        "amalmadhu@gmail.com",
 		"Phone_Number": "7902638843",
 	})
@@ -74,44 +76,81 @@ func (cr *PaymentHandler) CreateRazorpayPayment(c *gin.Context) {
 // PaymentSuccess
 // @Summary Handling successful payment
 // @ID payment-success
-// @Description Handler for automatically updating payment details upon successful payment
+// @Description Handler for updating payment details once the Razorpay checkout succeeds. The checkout signature is verified against the Razorpay order created for the order.
 // @Tags Payment
 // @Accept json
 // @Produce json
-// @Param c query string true "Payment details"
+// @Param order_id query string true "Order id"
+// @Param razorpay_order_id query string true "Razorpay order id returned by the checkout"
+// @Param razorpay_payment_id query string true "Razorpay payment id returned by the checkout"
+// @Param razorpay_signature query string true "Razorpay signature returned by the checkout"
+// @Param Idempotency-Key header string false "Retries with the same key get the first response instead of running the request again"
 // @Success 202 {object} response.Response "Successfully updated payment details"
-// @Failure 500 {object} response.Response "Failed to update payment details"
+// @Failure 400 {object} response.Response "Failed to update payment details"
+// @Failure 401 {object} response.Response "Checkout signature is not valid"
+// @Failure 422 {object} response.Response "Failed to read order id"
 // @Router /payments/success/ [get]
 func (cr *PaymentHandler) PaymentSuccess(c *gin.Context) {
-	paymentRef := c.Query("payment_ref")
-
-	idStr := c.Query("order_id")
-
-	idStr = strings.ReplaceAll(idStr, " ", "")
-
-	orderID, err := strconv.Atoi(idStr)
-
-	uID := c.Query("user_id")
-	userID, err := strconv.Atoi(uID)
-
-	t := c.Query("total")
-	total, err := strconv.ParseFloat(t, 32)
+	orderID, err := strconv.Atoi(strings.TrimSpace(c.Query("order_id")))
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "failed to read order id", Data: false, Errors: err.Error()})
+		return
+	}
 
+	userID, err := handlerUtil.GetUserIdFromContext(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to fetch total from request", Data: nil, Errors: err.Error()})
+		c.JSON(http.StatusUnauthorized, response.Response{StatusCode: 400, Message: "unable to fetch user id from context", Data: false, Errors: err.Error()})
+		return
 	}
 
 	paymentVerifier := model.PaymentVerification{
-		UserID:     userID,
-		OrderID:    orderID,
-		PaymentRef: paymentRef,
-		Total:      total,
+		UserID:            userID,
+		OrderID:           orderID,
+		RazorpayOrderID:   c.Query("razorpay_order_id"),
+		RazorpayPaymentID: c.Query("razorpay_payment_id"),
+		RazorpaySignature: c.Query("razorpay_signature"),
 	}
 
 	err = cr.paymentUseCase.UpdatePaymentDetails(c.Request.Context(), paymentVerifier)
+	if errors.Is(err, domain.ErrInvalidSignature) {
+		c.JSON(http.StatusUnauthorized, response.Response{StatusCode: 401, Message: "failed to verify payment", Data: false, Errors: err.Error()})
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{StatusCode: 500, Message: "failed to update payment details", Data: false, Errors: err.Error()})
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to update payment details", Data: false, Errors: err.Error()})
 		return
 	}
 	c.JSON(http.StatusAccepted, response.Response{StatusCode: 202, Message: "payment success", Data: true, Errors: nil})
 }
+
+// RazorpayWebhook
+// @Summary Razorpay notifies payment events
+// @ID razorpay-webhook
+// @Description Server to server webhook for captured and failed payments and processed refunds. The body is verified against the X-Razorpay-Signature header, and every event is applied only once by its X-Razorpay-Event-Id.
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param X-Razorpay-Signature header string true "HMAC-SHA256 of the body with the webhook secret"
+// @Param X-Razorpay-Event-Id header string true "ID of the event"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /payments/razorpay/webhook [post]
+func (cr *PaymentHandler) RazorpayWebhook(c *gin.Context) {
+	payload, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "unable to read the request body", Data: nil, Errors: err.Error()})
+		return
+	}
+
+	err = cr.paymentUseCase.HandleRazorpayWebhook(c.Request.Context(), payload, c.GetHeader("X-Razorpay-Signature"), c.GetHeader("X-Razorpay-Event-Id"))
+	if errors.Is(err, domain.ErrInvalidSignature) {
+		c.JSON(http.StatusUnauthorized, response.Response{StatusCode: 401, Message: "failed to verify webhook", Data: nil, Errors: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to handle webhook", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{StatusCode: 200, Message: "webhook handled", Data: nil, Errors: nil})
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
 	services "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/usecase/interface"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
@@ -331,8 +332,10 @@ func (cr *ProductHandler) CreateProduct(c *gin.Context) {
 // @Param limit query int false "Number of items to retrieve per page"
 // @Param query query string false "Search query string"
 // @Param filter query string false "Filter criteria for the products"
+// @Param filters query []string false "Filters written as field:operator:value, the operator is one of eq, in, range or ilike" collectionFormat(multi)
 // @Param sort_by query string false "Sorting criteria for the products"
 // @Success 200 {object} response.Response
+// @Failure 422 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /admin/products/ [get]
 
@@ -347,8 +350,10 @@ func (cr *ProductHandler) CreateProduct(c *gin.Context) {
 // @Param limit query int false "Number of items to retrieve per page"
 // @Param query query string false "Search query string"
 // @Param filter query string false "Filter criteria for the product items"
+// @Param filters query []string false "Filters written as field:operator:value, the operator is one of eq, in, range or ilike" collectionFormat(multi)
 // @Param sort_by query string false "Sorting criteria for the product items"
 // @Success 200 {object} response.Response
+// @Failure 422 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /products/ [get]
 func (cr *ProductHandler) ViewAllProducts(c *gin.Context) {
@@ -360,10 +365,16 @@ func (cr *ProductHandler) ViewAllProducts(c *gin.Context) {
 	viewProduct.Limit, _ = strconv.Atoi(c.Query("limit"))
 	viewProduct.Query = c.Query("query")
 	viewProduct.Filter = c.Query("filter")
+	viewProduct.Filters = c.QueryArray("filters")
 	viewProduct.SortBy = c.Query("sort_by")
 	viewProduct.SortDesc, _ = strconv.ParseBool(c.Query("sort_desc"))
 
 	products, err := cr.productUseCase.ViewAllProducts(c.Request.Context(), viewProduct)
+	var queryErr *domain.QueryParamsError
+	if errors.As(err, &queryErr) {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "failed to fetch products", Data: nil, Errors: err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, response.Response{StatusCode: 500, Message: "failed to fetch products", Data: nil, Errors: err.Error()})
 		return
@@ -495,8 +506,10 @@ func (cr *ProductHandler) CreateProductItem(c *gin.Context) {
 // @Param limit query int false "Number of items to retrieve per page"
 // @Param query query string false "Search query string"
 // @Param filter query string false "Filter criteria for the product items"
+// @Param filters query []string false "Filters written as field:operator:value, the operator is one of eq, in, range or ilike" collectionFormat(multi)
 // @Param sort_by query string false "Sorting criteria for the product items"
 // @Success 200 {object} response.Response
+// @Failure 422 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /admin/product-items/ [get]
 
@@ -511,9 +524,11 @@ func (cr *ProductHandler) CreateProductItem(c *gin.Context) {
 // @Param limit query int false "Number of items to retrieve per page"
 // @Param query query string false "Search query string"
 // @Param filter query string false "Filter criteria for the product items"
+// @Param filters query []string false "Filters written as field:operator:value, the operator is one of eq, in, range or ilike" collectionFormat(multi)
 // @Param sort_by query string false "Sorting criteria for the product items"
 // @Param sort_desc query bool false "Sorting in descending order"
 // @Success 200 {object} response.Response
+// @Failure 422 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /product-items/ [get]
 func (cr *ProductHandler) ViewAllProductItems(c *gin.Context) {
@@ -524,10 +539,16 @@ func (cr *ProductHandler) ViewAllProductItems(c *gin.Context) {
 	viewProductItem.Limit, _ = strconv.Atoi(c.Query("limit"))
 	viewProductItem.Query = c.Query("query")
 	viewProductItem.Filter = c.Query("filter")
+	viewProductItem.Filters = c.QueryArray("filters")
 	viewProductItem.SortBy = c.Query("sort_by")
 	viewProductItem.SortDesc, _ = strconv.ParseBool(c.Query("sort_desc"))
 
 	productItems, err := cr.productUseCase.ViewAllProductItems(c.Request.Context(), viewProductItem)
+	var queryErr *domain.QueryParamsError
+	if errors.As(err, &queryErr) {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "failed to fetch product items", Data: nil, Errors: err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, response.Response{StatusCode: 500, Message: "failed to fetch product items", Data: nil, Errors: err.Error()})
 		return
@@ -568,7 +589,7 @@ func (cr *ProductHandler) FindProductItemByID(c *gin.Context) {
 // UpdateProductItem updates a product item in the database.
 // @Summary Update a product item
 // @ID update-product-item
-// @Description Update an existing product item with new information.
+// @Description Update an existing product item with new information. Stock is not updated here, use /admin/product-items/{id}/stock to adjust stock.
 // @Tags Product Item
 // @Accept json
 // @Produce json
@@ -624,7 +645,9 @@ func (cr *ProductHandler) DeleteProductItem(c *gin.Context) {
 // CreateCoupon
 // @Summary Admin can create new coupon
 // @ID create-coupon
-// @Description Admin can create new coupons
+// @Description Admin can create new coupons. A coupon takes a percent, capped at its max amount, or a flat amount off the items it applies to.
+// @Description The scope limits it to the whole cart or to target categories, brands or product items. Usage limits, a first order only flag
+// @Description and target users limit who can use it and how many times.
 // @Tags Coupon
 // @Accept json
 // @Produce json
@@ -653,7 +676,7 @@ func (cr *ProductHandler) CreateCoupon(c *gin.Context) {
 // UpdateCoupon
 // @Summary Admin can update existing coupon
 // @ID update-coupon
-// @Description Admin can update existing coupon
+// @Description Admin can update existing coupon. The targets and users given replace the ones of the coupon.
 // @Tags Coupon
 // @Accept json
 // @Produce json
@@ -756,3 +779,22 @@ func (cr *ProductHandler) ViewAllCoupons(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, response.Response{StatusCode: 200, Message: "successfully fetched coupons", Data: coupons, Errors: nil})
 }
+
+// CouponRedemptionReport
+// @Summary Admin can see how much each coupon was redeemed
+// @ID coupon-redemption-report
+// @Description Admin can see the redemptions of each coupon and the discount they cost. Redemptions given back by cancelled orders are counted as released.
+// @Tags Coupon
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /admin/coupons/redemptions [get]
+func (cr *ProductHandler) CouponRedemptionReport(c *gin.Context) {
+	report, err := cr.productUseCase.CouponRedemptionReport(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Response{StatusCode: 500, Message: "failed to fetch coupon redemptions", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{StatusCode: 200, Message: "successfully fetched coupon redemptions", Data: report, Errors: nil})
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	services "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/usecase/interface"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type RefundHandler struct {
+	refundUseCase services.RefundUseCase
+}
+
+func NewRefundHandler(usecase services.RefundUseCase) *RefundHandler {
+	return &RefundHandler{
+		refundUseCase: usecase,
+	}
+}
+
+// ListRefunds
+// @Summary Admin can list refunds
+// @ID list-refunds
+// @Description Lists refunds in the given status, all refunds are listed when no status is given
+// @Tags Refund
+// @Accept json
+// @Produce json
+// @Param status query string false "initiated, processed or failed"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /admin/refunds [get]
+func (cr *RefundHandler) ListRefunds(c *gin.Context) {
+	refunds, err := cr.refundUseCase.ListRefunds(c.Request.Context(), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to fetch refunds", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{StatusCode: 200, Message: "successfully fetched refunds", Data: refunds, Errors: nil})
+}
+
+// CreateRefund
+// @Summary Admin can refund a part of an order
+// @ID create-refund
+// @Description Refunds an amount paid for an order. Without an amount, everything not refunded yet is refunded.
+// @Tags Refund
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the order"
+// @Param refund body model.CreateRefund true "Refund details"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /admin/orders/{id}/refunds [post]
+func (cr *RefundHandler) CreateRefund(c *gin.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "failed to read order id", Data: nil, Errors: err.Error()})
+		return
+	}
+	var newRefund model.CreateRefund
+	if err := c.Bind(&newRefund); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "unable to read the request body", Data: nil, Errors: err.Error()})
+		return
+	}
+	newRefund.OrderID = orderID
+	refund, err := cr.refundUseCase.CreateRefund(c.Request.Context(), newRefund)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to refund order", Data: refund, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, response.Response{StatusCode: 201, Message: "successfully initiated refund", Data: refund, Errors: nil})
+}
+
+// RetryRefund
+// @Summary Admin can retry a failed refund
+// @ID retry-refund
+// @Description Sends a refund the payment gateway failed to process to the gateway again
+// @Tags Refund
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the refund"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /admin/refunds/{id}/retry [put]
+func (cr *RefundHandler) RetryRefund(c *gin.Context) {
+	refundID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "failed to read refund id", Data: nil, Errors: err.Error()})
+		return
+	}
+	refund, err := cr.refundUseCase.RetryRefund(c.Request.Context(), refundID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to retry refund", Data: refund, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{StatusCode: 200, Message: "successfully initiated refund", Data: refund, Errors: nil})
+}
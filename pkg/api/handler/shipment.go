@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"errors"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	services "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/usecase/interface"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type ShipmentHandler struct {
+	shipmentUseCase services.ShipmentUseCase
+}
+
+func NewShipmentHandler(usecase services.ShipmentUseCase) *ShipmentHandler {
+	return &ShipmentHandler{
+		shipmentUseCase: usecase,
+	}
+}
+
+// CreateCarrier
+// @Summary Admin can add a carrier orders are shipped with
+// @ID create-carrier
+// @Description Adds a courier service. Tracking url is optional and should contain {tracking_number} in place of the tracking number.
+// @Tags Shipment
+// @Accept json
+// @Produce json
+// @Param carrier body model.CreateCarrier true "Carrier details"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /admin/carriers [post]
+func (cr *ShipmentHandler) CreateCarrier(c *gin.Context) {
+	var newCarrier model.CreateCarrier
+	if err := c.Bind(&newCarrier); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "unable to read the request body", Data: nil, Errors: err.Error()})
+		return
+	}
+	carrier, err := cr.shipmentUseCase.CreateCarrier(c.Request.Context(), newCarrier)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to create carrier", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, response.Response{StatusCode: 201, Message: "successfully created carrier", Data: carrier, Errors: nil})
+}
+
+// ListCarriers
+// @Summary Admin can list the carriers
+// @ID list-carriers
+// @Description Lists the courier services orders can be shipped with
+// @Tags Shipment
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /admin/carriers [get]
+func (cr *ShipmentHandler) ListCarriers(c *gin.Context) {
+	carriers, err := cr.shipmentUseCase.ListCarriers(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Response{StatusCode: 500, Message: "failed to fetch carriers", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{StatusCode: 200, Message: "successfully fetched carriers", Data: carriers, Errors: nil})
+}
+
+// CreateShipment
+// @Summary Admin can ship an order
+// @ID create-shipment
+// @Description Hands over order lines of a pending order to a carrier. Lines are optional, without them everything of the order that is not shipped yet is sent.
+// @Tags Shipment
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the order"
+// @Param shipment body model.CreateShipment true "Shipment details"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /admin/orders/{id}/shipments [post]
+func (cr *ShipmentHandler) CreateShipment(c *gin.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "failed to read order id", Data: nil, Errors: err.Error()})
+		return
+	}
+	var newShipment model.CreateShipment
+	if err := c.Bind(&newShipment); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "unable to read the request body", Data: nil, Errors: err.Error()})
+		return
+	}
+	newShipment.OrderID = orderID
+
+	shipment, err := cr.shipmentUseCase.CreateShipment(c.Request.Context(), newShipment)
+	var statusErr *domain.OrderStatusError
+	if errors.As(err, &statusErr) {
+		c.JSON(http.StatusConflict, response.Response{StatusCode: 409, Message: "failed to create shipment", Data: nil, Errors: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to create shipment", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, response.Response{StatusCode: 201, Message: "successfully created shipment", Data: shipment, Errors: nil})
+}
+
+// ViewOrderShipments
+// @Summary Admin can view the shipments of an order
+// @ID view-order-shipments
+// @Description Lists the shipments of an order with the order lines they carry and their tracking timeline
+// @Tags Shipment
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the order"
+// @Success 200 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /admin/orders/{id}/shipments [get]
+func (cr *ShipmentHandler) ViewOrderShipments(c *gin.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "failed to read order id", Data: nil, Errors: err.Error()})
+		return
+	}
+	shipments, err := cr.shipmentUseCase.ViewOrderShipments(c.Request.Context(), orderID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Response{StatusCode: 500, Message: "failed to fetch shipments", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{StatusCode: 200, Message: "successfully fetched shipments", Data: shipments, Errors: nil})
+}
+
+// AddShipmentEvent
+// @Summary Admin can post a tracking event to a shipment
+// @ID add-shipment-event
+// @Description Posts an in_transit, out_for_delivery or delivered event. Occurred at defaults to now. The order is marked delivered once all of it is shipped and delivered.
+// @Tags Shipment
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the shipment"
+// @Param event body model.ShipmentEvent true "Tracking event"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /admin/shipments/{id}/events [post]
+func (cr *ShipmentHandler) AddShipmentEvent(c *gin.Context) {
+	shipmentID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "failed to read shipment id", Data: nil, Errors: err.Error()})
+		return
+	}
+	var event model.ShipmentEvent
+	if err := c.Bind(&event); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "unable to read the request body", Data: nil, Errors: err.Error()})
+		return
+	}
+	event.ShipmentID = shipmentID
+
+	recordedEvent, err := cr.shipmentUseCase.AddShipmentEvent(c.Request.Context(), event)
+	var statusErr *domain.ShipmentStatusError
+	if errors.As(err, &statusErr) {
+		c.JSON(http.StatusConflict, response.Response{StatusCode: 409, Message: "failed to add shipment event", Data: nil, Errors: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to add shipment event", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, response.Response{StatusCode: 201, Message: "successfully added shipment event", Data: recordedEvent, Errors: nil})
+}
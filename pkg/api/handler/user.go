@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/api/handlerUtil"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
 	services "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/usecase/interface"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/response"
@@ -244,18 +246,26 @@ func (cr *UserHandler) AddAddress(c *gin.Context) {
 }
 
 // UpdateAddress
-// @Summary User can update existing address
+// @Summary User can update one of their saved addresses
 // @ID update-address
 // @Description Update address
 // @Tags Users
 // @Accept json
 // @Produce json
+// @Param id path string true "ID of the address to be updated"
 // @Param user_address body model.AddressInput true "User address"
 // @Success 200 {object} response.Response
 // @Failure 422 {object} response.Response
 // @Failure 500 {object} response.Response
-// @Router /addresses/ [put]
+// @Router /addresses/{id} [put]
 func (cr *UserHandler) UpdateAddress(c *gin.Context) {
+	paramsID := c.Param("id")
+	addressID, err := strconv.Atoi(paramsID)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "failed to parse address id", Data: nil, Errors: err.Error()})
+		return
+	}
+
 	var body model.AddressInput
 	if err := c.Bind(&body); err != nil {
 		// Return a 421 response if the request body is malformed.
@@ -269,7 +279,7 @@ func (cr *UserHandler) UpdateAddress(c *gin.Context) {
 		return
 	}
 
-	address, err := cr.userUseCase.UpdateAddress(c.Request.Context(), body, userID)
+	address, err := cr.userUseCase.UpdateAddress(c.Request.Context(), addressID, body, userID)
 	if err != nil {
 		// Return a 400 Bad Request response if there is an error while creating the user.
 		c.JSON(http.StatusInternalServerError, response.Response{StatusCode: 500, Message: "failed to update address", Data: nil, Errors: err.Error()})
@@ -280,6 +290,134 @@ func (cr *UserHandler) UpdateAddress(c *gin.Context) {
 	})
 }
 
+// ListAddresses
+// @Summary User can view all saved addresses
+// @ID list-addresses
+// @Description Lists the address book of the logged-in user. The default address is listed first.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /addresses/ [get]
+func (cr *UserHandler) ListAddresses(c *gin.Context) {
+	userID, err := handlerUtil.GetUserIdFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, response.Response{StatusCode: 400, Message: "unable to fetch user id from context", Data: nil, Errors: err.Error()})
+		return
+	}
+
+	addresses, err := cr.userUseCase.ListAddresses(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Response{StatusCode: 500, Message: "failed to fetch addresses", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{StatusCode: 200, Message: "Successfully fetched addresses", Data: addresses, Errors: nil})
+}
+
+// FindAddressByID
+// @Summary User can view a saved address
+// @ID find-address-by-id
+// @Description Fetch a saved address of the logged-in user using address id
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the address"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /addresses/{id} [get]
+func (cr *UserHandler) FindAddressByID(c *gin.Context) {
+	paramsID := c.Param("id")
+	addressID, err := strconv.Atoi(paramsID)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "failed to parse address id", Data: nil, Errors: err.Error()})
+		return
+	}
+
+	userID, err := handlerUtil.GetUserIdFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, response.Response{StatusCode: 400, Message: "unable to fetch user id from context", Data: nil, Errors: err.Error()})
+		return
+	}
+
+	address, err := cr.userUseCase.FindAddressByID(c.Request.Context(), addressID, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to fetch address", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{StatusCode: 200, Message: "Successfully fetched address", Data: address, Errors: nil})
+}
+
+// SetDefaultAddress
+// @Summary User can mark a saved address as default
+// @ID set-default-address
+// @Description The default address is used for orders placed without a shipping address
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the address"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /addresses/{id}/default [put]
+func (cr *UserHandler) SetDefaultAddress(c *gin.Context) {
+	paramsID := c.Param("id")
+	addressID, err := strconv.Atoi(paramsID)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "failed to parse address id", Data: nil, Errors: err.Error()})
+		return
+	}
+
+	userID, err := handlerUtil.GetUserIdFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, response.Response{StatusCode: 400, Message: "unable to fetch user id from context", Data: nil, Errors: err.Error()})
+		return
+	}
+
+	address, err := cr.userUseCase.SetDefaultAddress(c.Request.Context(), addressID, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to set default address", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{StatusCode: 200, Message: "Successfully set default address", Data: address, Errors: nil})
+}
+
+// DeleteAddress
+// @Summary User can delete a saved address
+// @ID delete-address
+// @Description Delete a saved address. If the default address is deleted, the oldest remaining address becomes the default.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the address"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /addresses/{id} [delete]
+func (cr *UserHandler) DeleteAddress(c *gin.Context) {
+	paramsID := c.Param("id")
+	addressID, err := strconv.Atoi(paramsID)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "failed to parse address id", Data: nil, Errors: err.Error()})
+		return
+	}
+
+	userID, err := handlerUtil.GetUserIdFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, response.Response{StatusCode: 400, Message: "unable to fetch user id from context", Data: nil, Errors: err.Error()})
+		return
+	}
+
+	err = cr.userUseCase.DeleteAddress(c.Request.Context(), addressID, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to delete address", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{StatusCode: 200, Message: "Successfully deleted address", Data: nil, Errors: nil})
+}
+
 // ListAllUsers
 // @Summary Admin can list all registered users
 // @ID list-all-users
@@ -291,9 +429,11 @@ func (cr *UserHandler) UpdateAddress(c *gin.Context) {
 // @Param limit query int false "Number of items to retrieve per page"
 // @Param query query string false "Search query string"
 // @Param filter query string false "Filter criteria for the users"
+// @Param filters query []string false "Filters written as field:operator:value, the operator is one of eq, in, range or ilike" collectionFormat(multi)
 // @Param sort_by query string false "Sorting criteria for the users"
 // @Param sort_desc query bool false "Sorting in descending order"
 // @Success 200 {object} response.Response
+// @Failure 422 {object} response.Response
 // @Failure 400 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /admin/users [get]
@@ -305,10 +445,16 @@ func (cr *UserHandler) ListAllUsers(c *gin.Context) {
 	viewUserInfo.Limit, _ = strconv.Atoi(c.Query("limit"))
 	viewUserInfo.Query = c.Query("query")
 	viewUserInfo.Filter = c.Query("filter")
+	viewUserInfo.Filters = c.QueryArray("filters")
 	viewUserInfo.SortBy = c.Query("sort_by")
 	viewUserInfo.SortDesc, _ = strconv.ParseBool(c.Query("sort_desc"))
 
 	users, err := cr.userUseCase.ListAllUsers(c.Request.Context(), viewUserInfo)
+	var queryErr *domain.QueryParamsError
+	if errors.As(err, &queryErr) {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "failed fetch users", Data: nil, Errors: err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, response.Response{StatusCode: 500, Message: "failed fetch users", Errors: err.Error()})
 		return
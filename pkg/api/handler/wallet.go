@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/api/handlerUtil"
+	services "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/usecase/interface"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type WalletHandler struct {
+	walletUseCase services.WalletUseCase
+}
+
+func NewWalletHandler(usecase services.WalletUseCase) *WalletHandler {
+	return &WalletHandler{
+		walletUseCase: usecase,
+	}
+}
+
+// ViewWallet
+// @Summary User can view their wallet
+// @ID view-wallet
+// @Description Shows the balance of the wallet of the user along with its history, latest first
+// @Tags Wallet
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /wallet [get]
+func (cr *WalletHandler) ViewWallet(c *gin.Context) {
+	userID, err := handlerUtil.GetUserIdFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, response.Response{StatusCode: 400, Message: "unable to fetch user id from context", Data: nil, Errors: err.Error()})
+		return
+	}
+	wallet, err := cr.walletUseCase.ViewWallet(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Response{StatusCode: 500, Message: "failed to fetch wallet", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{StatusCode: 200, Message: "successfully fetched wallet", Data: wallet, Errors: nil})
+}
+
+// AdminViewWallet
+// @Summary Admin can view the wallet of a user
+// @ID admin-view-wallet
+// @Description Shows the balance of the wallet of a user along with its history, latest first
+// @Tags Wallet
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the user"
+// @Success 200 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /admin/users/{id}/wallet [get]
+func (cr *WalletHandler) AdminViewWallet(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "failed to read user id", Data: nil, Errors: err.Error()})
+		return
+	}
+	wallet, err := cr.walletUseCase.ViewWallet(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Response{StatusCode: 500, Message: "failed to fetch wallet", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{StatusCode: 200, Message: "successfully fetched wallet", Data: wallet, Errors: nil})
+}
+
+// CreditWallet
+// @Summary Admin can credit the wallet of a user
+// @ID credit-wallet
+// @Description Adds money to the wallet of a user. Reason should be goodwill, referral or adjustment, a note is required.
+// @Tags Wallet
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the user"
+// @Param adjustment body model.WalletAdjustment true "Credit details"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /admin/users/{id}/wallet/credit [post]
+func (cr *WalletHandler) CreditWallet(c *gin.Context) {
+	adjustment, adminID, ok := readWalletAdjustment(c)
+	if !ok {
+		return
+	}
+	entry, err := cr.walletUseCase.CreditWallet(c.Request.Context(), adjustment, adminID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to credit wallet", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, response.Response{StatusCode: 201, Message: "successfully credited wallet", Data: entry, Errors: nil})
+}
+
+// DebitWallet
+// @Summary Admin can debit the wallet of a user
+// @ID debit-wallet
+// @Description Takes money out of the wallet of a user. The balance cannot go below zero, a note is required.
+// @Tags Wallet
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the user"
+// @Param adjustment body model.WalletAdjustment true "Debit details"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /admin/users/{id}/wallet/debit [post]
+func (cr *WalletHandler) DebitWallet(c *gin.Context) {
+	adjustment, adminID, ok := readWalletAdjustment(c)
+	if !ok {
+		return
+	}
+	entry, err := cr.walletUseCase.DebitWallet(c.Request.Context(), adjustment, adminID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "failed to debit wallet", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, response.Response{StatusCode: 201, Message: "successfully debited wallet", Data: entry, Errors: nil})
+}
+
+// readWalletAdjustment reads a wallet adjustment and the admin making it, responding with the error if either cannot be read
+func readWalletAdjustment(c *gin.Context) (model.WalletAdjustment, int, bool) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "failed to read user id", Data: nil, Errors: err.Error()})
+		return model.WalletAdjustment{}, 0, false
+	}
+	var adjustment model.WalletAdjustment
+	if err := c.Bind(&adjustment); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "unable to read the request body", Data: nil, Errors: err.Error()})
+		return model.WalletAdjustment{}, 0, false
+	}
+	adjustment.UserID = userID
+
+	adminID, err := handlerUtil.GetAdminIdFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, response.Response{StatusCode: 400, Message: "failed to fetch admin id", Data: nil, Errors: err.Error()})
+		return model.WalletAdjustment{}, 0, false
+	}
+	return adjustment, adminID, true
+}
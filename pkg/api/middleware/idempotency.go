@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/api/handlerUtil"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	services "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/usecase/interface"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/response"
+	"github.com/gin-gonic/gin"
+	"io"
+	"log"
+	"net/http"
+)
+
+// Idempotency makes a request sent with an Idempotency-Key header safe to retry. The first response for a key of the
+// user is stored and replayed for every retry with the same key, marked by an Idempotent-Replayed header. Requests
+// without the header are run as usual. It should be used after UserAuth.
+type Idempotency struct {
+	idempotencyUseCase services.IdempotencyUseCase
+}
+
+func NewIdempotency(usecase services.IdempotencyUseCase) *Idempotency {
+	return &Idempotency{
+		idempotencyUseCase: usecase,
+	}
+}
+
+func (m *Idempotency) Handle(c *gin.Context) {
+	key := c.GetHeader("Idempotency-Key")
+	if key == "" {
+		c.Next()
+		return
+	}
+	userID, err := handlerUtil.GetUserIdFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, response.Response{StatusCode: 400, Message: "unable to fetch user id from context", Data: nil, Errors: err.Error()})
+		return
+	}
+
+	//the body is read to tell a retry from a different request with the same key, and put back for the handler
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "unable to read request body", Data: nil, Errors: err.Error()})
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	request := append([]byte(c.Request.Method+" "+c.Request.URL.RequestURI()+"\n"), body...)
+
+	idempotencyKey, run, err := m.idempotencyUseCase.BeginRequest(c.Request.Context(), userID, key, request)
+	switch {
+	case errors.Is(err, domain.ErrIdempotencyKeyReused):
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response.Response{StatusCode: 422, Message: "invalid idempotency key", Data: nil, Errors: err.Error()})
+		return
+	case errors.Is(err, domain.ErrIdempotencyKeyInProgress):
+		c.AbortWithStatusJSON(http.StatusConflict, response.Response{StatusCode: 409, Message: "request is still in progress", Data: nil, Errors: err.Error()})
+		return
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.Response{StatusCode: 400, Message: "invalid idempotency key", Data: nil, Errors: err.Error()})
+		return
+	}
+	if !run {
+		c.Header("Idempotent-Replayed", "true")
+		//responses stored before their content type was kept are all JSON
+		contentType := idempotencyKey.ContentType
+		if contentType == "" {
+			contentType = "application/json; charset=utf-8"
+		}
+		c.Data(idempotencyKey.StatusCode, contentType, idempotencyKey.ResponseBody)
+		c.Abort()
+		return
+	}
+
+	//a handler that panics never finishes its request, the key is released so that a retry runs it again
+	defer func() {
+		if r := recover(); r != nil {
+			m.abort(c, idempotencyKey)
+			panic(r)
+		}
+	}()
+
+	recorder := &responseRecorder{ResponseWriter: c.Writer}
+	c.Writer = recorder
+	c.Next()
+
+	err = m.idempotencyUseCase.CompleteRequest(c.Request.Context(), idempotencyKey, recorder.Status(), recorder.Header().Get("Content-Type"), recorder.body.Bytes())
+	if err != nil {
+		log.Println("failed to store the response for idempotency key: ", err)
+		m.abort(c, idempotencyKey)
+	}
+}
+
+// abort releases the key of a request whose response was not stored, so that it is not left in progress
+func (m *Idempotency) abort(c *gin.Context, idempotencyKey domain.IdempotencyKey) {
+	if err := m.idempotencyUseCase.AbortRequest(c.Request.Context(), idempotencyKey); err != nil {
+		log.Println("failed to release idempotency key: ", err)
+	}
+}
+
+// responseRecorder keeps a copy of the response body written by the handler
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
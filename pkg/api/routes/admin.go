@@ -12,6 +12,12 @@ func AdminRoutes(
 	userHandler *handler.UserHandler,
 	productHandler *handler.ProductHandler,
 	orderHandler *handler.OrderHandler,
+	inventoryHandler *handler.InventoryHandler,
+	shipmentHandler *handler.ShipmentHandler,
+	refundHandler *handler.RefundHandler,
+	walletHandler *handler.WalletHandler,
+	codHandler *handler.CODHandler,
+	campaignHandler *handler.CampaignHandler,
 ) {
 
 	api.POST("/login", adminHandler.AdminLogin)
@@ -29,6 +35,9 @@ func AdminRoutes(
 			userRoutes.GET("/:id", userHandler.FindUserByID)
 			userRoutes.PUT("/block", userHandler.BlockUser)
 			userRoutes.PUT("/unblock/:id", userHandler.UnblockUser)
+			userRoutes.GET("/:id/wallet", walletHandler.AdminViewWallet)
+			userRoutes.POST("/:id/wallet/credit", walletHandler.CreditWallet)
+			userRoutes.POST("/:id/wallet/debit", walletHandler.DebitWallet)
 		}
 
 		//admin management
@@ -77,21 +86,78 @@ func AdminRoutes(
 			productItemRoutes.GET("/:id", productHandler.FindProductItemByID)
 			productItemRoutes.PUT("/", productHandler.UpdateProductItem)
 			productItemRoutes.DELETE("/:id", productHandler.DeleteProductItem)
+			productItemRoutes.POST("/:id/stock", inventoryHandler.AdjustStock)
+			productItemRoutes.GET("/:id/stock", inventoryHandler.ViewStockMovements)
+		}
+
+		// Inventory routes
+		inventoryRoutes := api.Group("/inventory")
+		{
+			inventoryRoutes.GET("/reconcile", inventoryHandler.ReconcileStock)
 		}
 
 		//	Coupon Management Routes
 		couponRoutes := api.Group("/coupons")
 		{
 			couponRoutes.GET("/", productHandler.ViewAllCoupons)
+			couponRoutes.GET("/redemptions", productHandler.CouponRedemptionReport)
 			couponRoutes.GET("/:coupon_id", productHandler.ViewCouponByID)
 			couponRoutes.POST("/", productHandler.CreateCoupon)
 			couponRoutes.PUT("/", productHandler.UpdateCoupon)
 			couponRoutes.DELETE("/:coupon_id", productHandler.DeleteCoupon)
 		}
 
+		//	Coupon campaign routes
+		campaigns := api.Group("/campaigns")
+		{
+			campaigns.POST("", campaignHandler.CreateCampaign)
+			campaigns.GET("", campaignHandler.ViewCampaigns)
+			campaigns.GET("/:id/codes", campaignHandler.ExportCampaignCodes)
+		}
+
 		order := api.Group("/orders")
 		{
 			order.PUT("/", orderHandler.UpdateOrder)
+			order.GET("/:id/history", orderHandler.AdminViewOrderStatusHistory)
+			order.POST("/:id/shipments", shipmentHandler.CreateShipment)
+			order.GET("/:id/shipments", shipmentHandler.ViewOrderShipments)
+			order.POST("/:id/refunds", refundHandler.CreateRefund)
+			order.POST("/:id/cod-collection", codHandler.RecordCODCollection)
+		}
+
+		carriers := api.Group("/carriers")
+		{
+			carriers.POST("/", shipmentHandler.CreateCarrier)
+			carriers.GET("/", shipmentHandler.ListCarriers)
+		}
+
+		shipments := api.Group("/shipments")
+		{
+			shipments.POST("/:id/events", shipmentHandler.AddShipmentEvent)
+		}
+
+		returns := api.Group("/returns")
+		{
+			returns.GET("/", orderHandler.ListReturns)
+			returns.PUT("/:id/approve", orderHandler.ApproveReturn)
+			returns.PUT("/:id/reject", orderHandler.RejectReturn)
+			returns.PUT("/:id/pickup", orderHandler.ReturnPickedUp)
+			returns.PUT("/:id/received", orderHandler.ReturnReceived)
+		}
+
+		refunds := api.Group("/refunds")
+		{
+			refunds.GET("/", refundHandler.ListRefunds)
+			refunds.PUT("/:id/retry", refundHandler.RetryRefund)
+		}
+
+		//cash on delivery rules
+		cod := api.Group("/cod")
+		{
+			cod.GET("/rules", codHandler.ViewCODRules)
+			cod.PUT("/rules", codHandler.UpdateCODRules)
+			cod.POST("/pincodes", codHandler.AddCODPincode)
+			cod.DELETE("/pincodes/:pincode", codHandler.RemoveCODPincode)
 		}
 	}
 }
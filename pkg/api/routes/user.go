@@ -15,6 +15,8 @@ func UserRoutes(
 	otpHandler *handler.OtpHandler,
 	paymentHandler *handler.PaymentHandler,
 	wishlistHandler *handler.WishlistHandler,
+	walletHandler *handler.WalletHandler,
+	idempotency *middleware.Idempotency,
 ) {
 
 	// User routes that don't require authentication
@@ -24,6 +26,9 @@ func UserRoutes(
 	api.POST("/send-otp", otpHandler.SendOtp)
 	api.POST("/verify-otp", otpHandler.ValidateOtp)
 
+	// Payment gateway webhooks, authenticated by their signature
+	api.POST("/payments/razorpay/webhook", paymentHandler.RazorpayWebhook)
+
 	// Category routes
 	category := api.Group("/categories")
 	{
@@ -62,7 +67,11 @@ func UserRoutes(
 		address := api.Group("/addresses")
 		{
 			address.POST("/", userHandler.AddAddress)
-			address.PUT("/", userHandler.UpdateAddress)
+			address.GET("/", userHandler.ListAddresses)
+			address.GET("/:id", userHandler.FindAddressByID)
+			address.PUT("/:id", userHandler.UpdateAddress)
+			address.PUT("/:id/default", userHandler.SetDefaultAddress)
+			address.DELETE("/:id", userHandler.DeleteAddress)
 		}
 
 		// Cart routes
@@ -70,7 +79,10 @@ func UserRoutes(
 		{
 			cart.POST("/add/:product_item_id", cartHandler.AddToCart)
 			cart.DELETE("/remove/:product_item_id", cartHandler.RemoveFromCart)
+			cart.PUT("/items/:product_item_id", cartHandler.UpdateCartItem)
 			cart.POST("/coupon/:coupon_id", cartHandler.AddCouponToCart)
+			cart.GET("/coupons/applicable", cartHandler.ApplicableCoupons)
+			cart.POST("/coupons/apply-best", cartHandler.ApplyBestCoupon)
 			cart.GET("", cartHandler.ViewCart)
 			cart.DELETE("", cartHandler.EmptyCart)
 		}
@@ -85,16 +97,17 @@ func UserRoutes(
 		// Order routes
 		order := api.Group("/orders")
 		{
-			order.POST("", orderHandler.BuyProductItem)
-			order.POST("/buy-all", orderHandler.BuyAll)
+			order.POST("", idempotency.Handle, orderHandler.BuyProductItem)
+			order.POST("/buy-all", idempotency.Handle, orderHandler.BuyAll)
 			order.GET("/:id", orderHandler.ViewOrderByID)
+			order.GET("/:id/history", orderHandler.ViewOrderStatusHistory)
 			order.GET("", orderHandler.ViewAllOrders)
 			order.PUT("/cancel/:id", orderHandler.CancelOrder)
 			order.POST("/return", orderHandler.ReturnRequest)
 		}
 
 		// Payment routes
-		payment := api.Group("/payments")
+		payment := api.Group("/payments", idempotency.Handle)
 		{
 			payment.GET("/razorpay/:order_id", paymentHandler.CreateRazorpayPayment)
 			payment.GET("/success", paymentHandler.PaymentSuccess)
@@ -108,6 +121,9 @@ func UserRoutes(
 			wishlist.DELETE("/:id", wishlistHandler.RemoveFromWishlist)
 			wishlist.DELETE("/", wishlistHandler.EmptyWishlist)
 		}
+
+		//wallet routes
+		api.GET("/wallet", walletHandler.ViewWallet)
 	}
 
 }
@@ -1,15 +1,19 @@
 package http
 
 import (
+	"context"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/api/handler"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/api/middleware"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/api/routes"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/job"
 	"github.com/gin-gonic/gin"
 	swaggerfiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
 type ServerHTTP struct {
-	engine *gin.Engine
+	engine            *gin.Engine
+	paymentReconciler *job.PaymentReconciler
 }
 
 func NewServerHTTP(userHandler *handler.UserHandler,
@@ -20,6 +24,14 @@ func NewServerHTTP(userHandler *handler.UserHandler,
 	orderHandler *handler.OrderHandler,
 	paymentHandler *handler.PaymentHandler,
 	wishlistHandler *handler.WishlistHandler,
+	inventoryHandler *handler.InventoryHandler,
+	shipmentHandler *handler.ShipmentHandler,
+	refundHandler *handler.RefundHandler,
+	walletHandler *handler.WalletHandler,
+	codHandler *handler.CODHandler,
+	campaignHandler *handler.CampaignHandler,
+	idempotency *middleware.Idempotency,
+	paymentReconciler *job.PaymentReconciler,
 ) *ServerHTTP {
 
 	engine := gin.New()
@@ -29,13 +41,16 @@ func NewServerHTTP(userHandler *handler.UserHandler,
 	engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
 
 	// set up routes
-	routes.UserRoutes(engine.Group("/"), userHandler, productHandler, cartHandler, orderHandler, otpHandler, paymentHandler, wishlistHandler)
-	routes.AdminRoutes(engine.Group("/admin"), adminHandler, userHandler, productHandler, orderHandler)
+	routes.UserRoutes(engine.Group("/"), userHandler, productHandler, cartHandler, orderHandler, otpHandler, paymentHandler, wishlistHandler, walletHandler, idempotency)
+	routes.AdminRoutes(engine.Group("/admin"), adminHandler, userHandler, productHandler, orderHandler, inventoryHandler, shipmentHandler, refundHandler, walletHandler, codHandler, campaignHandler)
 
-	return &ServerHTTP{engine: engine}
+	return &ServerHTTP{engine: engine, paymentReconciler: paymentReconciler}
 }
 
 func (sh *ServerHTTP) Start() {
+	// settle payments left pending in the background
+	go sh.paymentReconciler.Run(context.Background())
+
 	//sh.engine.LoadHTMLGlob("template/*.html")
 	err := sh.engine.Run(":3000")
 	if err != nil {
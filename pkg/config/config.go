@@ -3,6 +3,7 @@ package config
 import (
 	"github.com/go-playground/validator/v10"
 	"github.com/spf13/viper"
+	"time"
 )
 
 type Config struct {
@@ -15,11 +16,26 @@ type Config struct {
 	TWILIOACCOUNTSID string `mapstructure:"TWILIO_ACCOUNT_SID"`
 	TWILIOAUTHTOKEN  string `mapstructure:"TWILIO_AUTHTOKEN"`
 	TWILIOSERVICESID string `mapstructure:"TWILIO_SERVICES_ID"`
+
+	RazorpayKeyID         string `mapstructure:"RAZORPAY_KEY_ID"`
+	RazorpayKeySecret     string `mapstructure:"RAZORPAY_KEY_SECRET"`
+	RazorpayWebhookSecret string `mapstructure:"RAZORPAY_WEBHOOK_SECRET"`
+
+	// durations like 15m or 24h, see job.NewPaymentReconciler for the defaults
+	PaymentReconcileInterval time.Duration `mapstructure:"PAYMENT_RECONCILE_INTERVAL"`
+	PaymentReconcileAfter    time.Duration `mapstructure:"PAYMENT_RECONCILE_AFTER"`
+	PaymentExpireAfter       time.Duration `mapstructure:"PAYMENT_EXPIRE_AFTER"`
+
+	// how long idempotency keys are kept, defaults to 24h
+	IdempotencyKeyTTL time.Duration `mapstructure:"IDEMPOTENCY_KEY_TTL"`
 }
 
 var envs = []string{
 	"DB_HOST", "DB_NAME", "DB_USER", "DB_PORT", "DB_PASSWORD",
 	"TWILIO_ACCOUNT_SID", "TWILIO_AUTHTOKEN", "TWILIO_SERVICES_ID",
+	"RAZORPAY_KEY_ID", "RAZORPAY_KEY_SECRET", "RAZORPAY_WEBHOOK_SECRET",
+	"PAYMENT_RECONCILE_INTERVAL", "PAYMENT_RECONCILE_AFTER", "PAYMENT_EXPIRE_AFTER",
+	"IDEMPOTENCY_KEY_TTL",
 }
 
 func LoadConfig() (Config, error) {
@@ -33,7 +33,7 @@ WHERE os.order_status IS NULL;
 INSERT INTO
     payment_methods (payment_method)
 	SELECT pm.payment_method FROM
-    (VALUES ('cod'), ('online')) AS pm(payment_method)
+    (VALUES ('cod'), ('online'), ('wallet')) AS pm(payment_method)
     LEFT JOIN payment_methods p ON p.payment_method = pm.payment_method
 WHERE
     p.payment_method IS NULL;
@@ -43,8 +43,138 @@ WHERE
 INSERT INTO
 	payment_statuses (payment_status)
 	SELECT ps.payment_status FROM
-	(VALUES ('pending'), ('completed')) AS ps(payment_status)
+	(VALUES ('pending'), ('completed'), ('failed'), ('refunded'), ('expired')) AS ps(payment_status)
 	LEFT JOIN payment_statuses p ON p.payment_status = ps.payment_status
+WHERE
+	p.payment_status IS NULL;
+`
+
+	// copy the shipping address into orders placed before address snapshots were stored on the order
+	backfillOrderAddress string = `
+UPDATE orders o
+	SET shipping_house_number = a.house_number,
+		shipping_street = a.street,
+		shipping_city = a.city,
+		shipping_district = a.district,
+		shipping_pincode = a.pincode,
+		shipping_landmark = a.landmark,
+		shipping_label = a.label
+	FROM addresses a
+WHERE a.id = o.shipping_address_id AND o.shipping_pincode IS NULL;
+`
+
+	// addresses saved before they could be deleted or made default may have the flags unset. They are filled in before
+	// the migration, as the columns cannot be made not null while they hold nulls.
+	fillAddressFlags string = `
+UPDATE addresses
+	SET is_deleted = COALESCE(is_deleted, false),
+		is_default = COALESCE(is_default, false)
+WHERE is_deleted IS NULL OR is_default IS NULL;
+`
+
+	// users who saved addresses before one of them could be made default get their oldest address as the default
+	backfillDefaultAddress string = `
+UPDATE addresses
+	SET is_default = true
+WHERE id IN (
+	SELECT MIN(a.id) FROM addresses a
+	WHERE a.is_deleted = false
+	GROUP BY a.user_id
+	HAVING NOT BOOL_OR(a.is_default)
+);
+`
+
+	// order lines used to store only the line total in the legacy price column. Lines of orders placed for a single item
+	// with a coupon stored the discounted order total instead, their unit price is taken from the product item, as the
+	// discount is recorded on the order.
+	legacyOrderLinePriceExists string = `
+SELECT EXISTS (
+	SELECT 1 FROM information_schema.columns
+	WHERE table_schema = current_schema() AND table_name = 'order_lines' AND column_name = 'price'
+);
+`
+	backfillOrderLinePrice string = `
+UPDATE order_lines ol
+	SET unit_price = l.unit_price,
+		line_total = l.unit_price * ol.quantity
+	FROM (
+		SELECT order_lines.id,
+			CASE WHEN o.coupon_id != 0 AND order_lines.quantity = 1 AND order_lines.price = o.order_total
+				THEN GREATEST(COALESCE(pi.price, 0), order_lines.price)
+				ELSE order_lines.price / order_lines.quantity END AS unit_price
+		FROM order_lines
+		JOIN orders o ON o.id = order_lines.order_id
+		LEFT JOIN product_items pi ON pi.id = order_lines.product_item_id
+		WHERE order_lines.line_total IS NULL AND order_lines.quantity > 0
+	) l
+WHERE l.id = ol.id;
+`
+
+	// items put in the cart before prices were recorded on the cart item are taken to be added at the current price
+	backfillCartItemPrice string = `
+UPDATE cart_items ci
+	SET price_when_added = pi.price
+	FROM product_items pi
+WHERE pi.id = ci.product_item_id AND ci.price_when_added IS NULL;
+`
+
+	// orders placed with a coupon before redemptions were recorded are redeemed in the order they were placed, the
+	// ones cancelled since are released. Their discount is what the lines cost over the order total, as those orders
+	// were not taxed.
+	backfillCouponRedemptions string = `
+INSERT INTO coupon_redemptions (coupon_id, user_id, order_id, usage_slot, user_slot, discount, created_at, released_at)
+	SELECT o.coupon_id, o.user_id, o.id,
+		CASE WHEN o.released THEN NULL ELSE ROW_NUMBER() OVER (PARTITION BY o.coupon_id, o.released ORDER BY o.id) END,
+		CASE WHEN o.released THEN NULL ELSE ROW_NUMBER() OVER (PARTITION BY o.coupon_id, o.user_id, o.released ORDER BY o.id) END,
+		GREATEST(COALESCE((SELECT SUM(ol.line_total) FROM order_lines ol WHERE ol.order_id = o.id), 0) - o.order_total, 0),
+		o.order_date,
+		CASE WHEN o.released THEN o.order_date END
+	FROM (
+		SELECT orders.*, os.order_status IN ('cancelled by user', 'cancelled by admin') AS released
+		FROM orders
+		JOIN order_statuses os ON os.id = orders.order_status_id
+		WHERE orders.coupon_id != 0
+			AND NOT EXISTS (SELECT 1 FROM coupon_redemptions r WHERE r.order_id = orders.id)
+	) o
+ON CONFLICT DO NOTHING;
+`
+
+	// returns placed before the return workflow existed only had the approved flag
+	backfillReturnStatus string = `
+UPDATE returns
+	SET status = CASE WHEN approved THEN 'approved' ELSE 'requested' END
+WHERE status IS NULL;
+`
+
+	// refunds made before wallets existed were all paid back through the payment the order was paid with
+	backfillRefundDestination string = `
+UPDATE refunds
+	SET destination = 'gateway'
+WHERE destination IS NULL;
+`
+
+	// orders placed before delivery status was set at checkout are waiting for delivery
+	backfillDeliveryStatus string = `
+UPDATE orders
+	SET delivery_status_id = (SELECT id FROM delivery_statuses WHERE status = 'pending')
+WHERE delivery_status_id IS NULL OR delivery_status_id = 0;
+`
+
+	// orders placed before status history was recorded start their history with the status they are in now
+	initOrderStatusHistory string = `
+INSERT INTO order_status_histories (order_id, order_status_id, delivery_status_id, changed_by, created_at)
+	SELECT o.id, o.order_status_id, o.delivery_status_id, 'system', NOW()
+	FROM orders o
+WHERE NOT EXISTS (SELECT 1 FROM order_status_histories h WHERE h.order_id = o.id);
+`
+
+	// product items created before the inventory ledger existed get their current stock as an opening balance
+	initStockLedger string = `
+INSERT INTO stock_movements (product_item_id, quantity, reason, note, created_at)
+	SELECT pi.id, pi.qnty_in_stock, 'adjustment', 'opening balance', NOW()
+	FROM product_items pi
+WHERE pi.qnty_in_stock != 0
+	AND NOT EXISTS (SELECT 1 FROM stock_movements sm WHERE sm.product_item_id = pi.id);
 `
 )
 
@@ -54,6 +184,9 @@ func ConnectDatabase(cfg config.Config) (*gorm.DB, error) {
 		SkipDefaultTransaction: true,
 	})
 
+	// the address columns may not exist yet, in which case there is nothing to fill
+	db.Exec(fillAddressFlags)
+
 	err := db.AutoMigrate(
 
 		//user tables
@@ -69,7 +202,14 @@ func ConnectDatabase(cfg config.Config) (*gorm.DB, error) {
 		&domain.ProductBrand{},
 		&domain.Product{},
 		&domain.ProductItem{},
+		&domain.CouponCampaign{},
 		&domain.Coupon{},
+		&domain.CouponTarget{},
+		&domain.CouponUser{},
+		&domain.CouponRedemption{},
+
+		//inventory tables
+		&domain.StockMovement{},
 
 		//cart tables
 		&domain.Cart{},
@@ -86,10 +226,30 @@ func ConnectDatabase(cfg config.Config) (*gorm.DB, error) {
 		&domain.OrderStatus{},
 		&domain.DeliveryStatus{},
 		&domain.Return{},
+		&domain.OrderStatusHistory{},
+
+		//shipment tables
+		&domain.Carrier{},
+		&domain.Shipment{},
+		&domain.ShipmentLine{},
+		&domain.ShipmentEvent{},
 
 		//	payment details
 		&domain.PaymentStatus{},
 		&domain.PaymentDetails{},
+		&domain.Refund{},
+		&domain.PaymentEvent{},
+
+		//wallet tables
+		&domain.WalletEntry{},
+
+		//cash on delivery tables
+		&domain.CODRules{},
+		&domain.CODPincode{},
+		&domain.CODCollection{},
+
+		//idempotency keys of retried requests
+		&domain.IdempotencyKey{},
 	)
 	if err != nil {
 		return nil, err
@@ -99,6 +259,36 @@ func ConnectDatabase(cfg config.Config) (*gorm.DB, error) {
 	db.Exec(initOrderStatus)
 	db.Exec(initPaymentMethod)
 	db.Exec(initPaymentStatus)
+	db.Exec(backfillOrderAddress)
+	db.Exec(backfillDefaultAddress)
+	if err := backfillOrderLines(db); err != nil {
+		return nil, err
+	}
+	db.Exec(backfillCartItemPrice)
+	db.Exec(backfillCouponRedemptions)
+	db.Exec(initStockLedger)
+	db.Exec(backfillRefundDestination)
+
+	// resolve the ids of statuses and payment methods by their names
+	if err := resolveStatuses(db); err != nil {
+		return nil, err
+	}
+	db.Exec(backfillReturnStatus)
+	db.Exec(backfillDeliveryStatus)
+	db.Exec(initOrderStatusHistory)
 
 	return db, dbErr
 }
+
+// backfillOrderLines fills in the unit price and line total of order lines written before they were stored. Databases
+// created after that never had the legacy price column, and have nothing to backfill.
+func backfillOrderLines(db *gorm.DB) error {
+	var legacy bool
+	if err := db.Raw(legacyOrderLinePriceExists).Scan(&legacy).Error; err != nil {
+		return err
+	}
+	if !legacy {
+		return nil
+	}
+	return db.Exec(backfillOrderLinePrice).Error
+}
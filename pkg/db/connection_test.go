@@ -0,0 +1,56 @@
+package db
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func TestBackfillOrderLines(t *testing.T) {
+	tests := []struct {
+		name      string
+		buildStub func(mock sqlmock.Sqlmock)
+	}{
+		{ //test case for a database with order lines written before unit prices were stored
+			name: "legacy price column",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT EXISTS (.+) column_name = 'price'(.+)$").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectExec("^UPDATE order_lines ol SET unit_price = l.unit_price, line_total = l.unit_price \\* ol.quantity (.+)$").
+					WillReturnResult(sqlmock.NewResult(0, 3))
+			},
+		},
+		{ //test case for a database created after unit prices were stored, nothing is backfilled
+			name: "no legacy price column",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT EXISTS (.+)$").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when initializing a mock db session", err)
+			}
+			tt.buildStub(mock)
+
+			assert.NoError(t, backfillOrderLines(gormDB))
+
+			err = mock.ExpectationsWereMet()
+			if err != nil {
+				t.Errorf("Unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
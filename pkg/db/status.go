@@ -0,0 +1,54 @@
+package db
+
+import (
+	"fmt"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"gorm.io/gorm"
+)
+
+type lookupRow struct {
+	ID   uint
+	Name string
+}
+
+// resolveStatuses sets the status and payment method values in domain to the ids they have in the database.
+// Startup fails if a name the code depends on is missing from its table, or if a table holds a name the code does not know.
+func resolveStatuses(db *gorm.DB) error {
+	if err := resolveLookup(db, `SELECT id, order_status AS name FROM order_statuses ORDER BY id`, "order_statuses", domain.OrderStatuses); err != nil {
+		return err
+	}
+	if err := resolveLookup(db, `SELECT id, status AS name FROM delivery_statuses ORDER BY id`, "delivery_statuses", domain.DeliveryStatuses); err != nil {
+		return err
+	}
+	if err := resolveLookup(db, `SELECT id, payment_status AS name FROM payment_statuses ORDER BY id`, "payment_statuses", domain.PaymentStatuses); err != nil {
+		return err
+	}
+	return resolveLookup(db, `SELECT id, payment_method AS name FROM payment_methods ORDER BY id`, "payment_methods", domain.PaymentMethods)
+}
+
+func resolveLookup[T ~uint](db *gorm.DB, query, table string, lookup map[string]*T) error {
+	var rows []lookupRow
+	if err := db.Raw(query).Scan(&rows).Error; err != nil {
+		return fmt.Errorf("failed to read %v : %w", table, err)
+	}
+
+	// older databases may hold the same name more than once, the first row inserted is the one in use
+	ids := make(map[string]uint)
+	for _, row := range rows {
+		if _, ok := lookup[row.Name]; !ok {
+			return fmt.Errorf("%v has %q with id %v which is not known to the application", table, row.Name, row.ID)
+		}
+		if _, ok := ids[row.Name]; !ok {
+			ids[row.Name] = row.ID
+		}
+	}
+
+	for name, value := range lookup {
+		id, ok := ids[name]
+		if !ok {
+			return fmt.Errorf("%v is missing %q", table, name)
+		}
+		*value = T(id)
+	}
+	return nil
+}
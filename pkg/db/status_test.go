@@ -0,0 +1,72 @@
+package db
+
+import (
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func TestResolveLookup(t *testing.T) {
+	tests := []struct {
+		name           string
+		rows           *sqlmock.Rows
+		expectedCOD    domain.PaymentMethodID
+		expectedOnline domain.PaymentMethodID
+		expectedError  error
+	}{
+		{ //test case for ids that differ from the ones of a fresh database
+			name:           "resolved by name",
+			rows:           sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "online").AddRow(2, "cod"),
+			expectedCOD:    2,
+			expectedOnline: 1,
+			expectedError:  nil,
+		},
+		{ //test case for a name inserted more than once by an older seed
+			name:           "duplicate name",
+			rows:           sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "cod").AddRow(2, "online").AddRow(3, "cod"),
+			expectedCOD:    1,
+			expectedOnline: 2,
+			expectedError:  nil,
+		},
+		{ //test case for a name the application depends on
+			name:          "missing name",
+			rows:          sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "cod"),
+			expectedError: errors.New(`payment_methods is missing "online"`),
+		},
+		{ //test case for a name added to the database but not to the application
+			name:          "unknown name",
+			rows:          sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "cod").AddRow(2, "online").AddRow(3, "upi"),
+			expectedError: errors.New(`payment_methods has "upi" with id 3 which is not known to the application`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when initializing a mock db session", err)
+			}
+			mock.ExpectQuery("^SELECT id, payment_method AS name FROM payment_methods (.+)$").WillReturnRows(tt.rows)
+
+			var cod, online domain.PaymentMethodID
+			lookup := map[string]*domain.PaymentMethodID{"cod": &cod, "online": &online}
+			err = resolveLookup(gormDB, `SELECT id, payment_method AS name FROM payment_methods ORDER BY id`, "payment_methods", lookup)
+
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, tt.expectedCOD, cod)
+				assert.Equal(t, tt.expectedOnline, online)
+			}
+		})
+	}
+}
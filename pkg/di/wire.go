@@ -6,8 +6,11 @@ package di
 import (
 	http "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/api"
 	handler "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/api/handler"
+	middleware "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/api/middleware"
 	config "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/config"
 	db "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/db"
+	gateway "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/gateway"
+	job "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/job"
 	repository "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository"
 	usecase "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/usecase"
 	"github.com/google/wire"
@@ -27,6 +30,15 @@ func InitializeAPI(cfg config.Config) (*http.ServerHTTP, error) {
 		handler.NewOrderHandler,
 		handler.NewPaymentHandler,
 		handler.NewWishlistHandler,
+		handler.NewInventoryHandler,
+		handler.NewShipmentHandler,
+		handler.NewRefundHandler,
+		handler.NewWalletHandler,
+		handler.NewCODHandler,
+		handler.NewCampaignHandler,
+
+		//middleware
+		middleware.NewIdempotency,
 
 		//database queries
 		repository.NewAdminRepository,
@@ -37,6 +49,16 @@ func InitializeAPI(cfg config.Config) (*http.ServerHTTP, error) {
 		repository.NewOrderRepository,
 		repository.NewPaymentRepository,
 		repository.NewWishlistRepository,
+		repository.NewInventoryRepository,
+		repository.NewShipmentRepository,
+		repository.NewRefundRepository,
+		repository.NewWalletRepository,
+		repository.NewCODRepository,
+		repository.NewCampaignRepository,
+		repository.NewIdempotencyRepository,
+
+		//payment gateway
+		gateway.NewRazorpayGateway,
 
 		//use case
 		usecase.NewAdminUseCase,
@@ -47,9 +69,41 @@ func InitializeAPI(cfg config.Config) (*http.ServerHTTP, error) {
 		usecase.NewOrderUseCase,
 		usecase.NewPaymentUseCase,
 		usecase.NewWishlistUsecase,
+		usecase.NewInventoryUseCase,
+		usecase.NewShipmentUseCase,
+		usecase.NewRefundUseCase,
+		usecase.NewWalletUseCase,
+		usecase.NewCODUseCase,
+		usecase.NewCampaignUseCase,
+		usecase.NewIdempotencyUseCase,
+
+		//background jobs
+		job.NewPaymentReconciler,
 
 		//server connection
 		http.NewServerHTTP)
 
 	return &http.ServerHTTP{}, nil
 }
+
+func InitializePaymentReconciler(cfg config.Config) (*job.PaymentReconciler, error) {
+	wire.Build(
+		//database connection
+		db.ConnectDatabase,
+
+		//database queries
+		repository.NewOrderRepository,
+		repository.NewPaymentRepository,
+		repository.NewRefundRepository,
+
+		//payment gateway
+		gateway.NewRazorpayGateway,
+
+		//use case
+		usecase.NewPaymentUseCase,
+		usecase.NewRefundUseCase,
+
+		job.NewPaymentReconciler)
+
+	return &job.PaymentReconciler{}, nil
+}
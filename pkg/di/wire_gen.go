@@ -9,8 +9,11 @@ package di
 import (
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/api"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/api/handler"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/api/middleware"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/config"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/db"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/gateway"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/job"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/usecase"
 )
@@ -38,14 +41,52 @@ func InitializeAPI(cfg config.Config) (*http.ServerHTTP, error) {
 	cartRepository := repository.NewCartRepository(gormDB)
 	cartUseCases := usecase.NewCartUseCase(cartRepository, productRepository)
 	cartHandler := handler.NewCartHandler(cartUseCases)
-	orderUseCases := usecase.NewOrderUseCase(orderRepository, userRepository, productRepository)
-	orderHandler := handler.NewOrderHandler(orderUseCases)
+	shipmentRepository := repository.NewShipmentRepository(gormDB)
+	refundRepository := repository.NewRefundRepository(gormDB)
 	paymentRepository := repository.NewPaymentRepository(gormDB)
-	paymentUseCases := usecase.NewPaymentUseCase(orderRepository, paymentRepository)
+	paymentGateway := gateway.NewRazorpayGateway(cfg)
+	refundUseCase := usecase.NewRefundUseCase(refundRepository, paymentRepository, paymentGateway)
+	orderUseCases := usecase.NewOrderUseCase(orderRepository, userRepository, productRepository, shipmentRepository, refundUseCase)
+	orderHandler := handler.NewOrderHandler(orderUseCases)
+	paymentUseCases := usecase.NewPaymentUseCase(orderRepository, paymentRepository, paymentGateway, refundUseCase)
 	paymentHandler := handler.NewPaymentHandler(paymentUseCases)
 	wishlistRepository := repository.NewWishlistRepository(gormDB)
 	wishlistUseCase := usecase.NewWishlistUsecase(wishlistRepository)
 	wishlistHandler := handler.NewWishlistHandler(wishlistUseCase)
-	serverHTTP := http.NewServerHTTP(userHandler, adminHandler, otpHandler, productHandler, cartHandler, orderHandler, paymentHandler, wishlistHandler)
+	inventoryRepository := repository.NewInventoryRepository(gormDB)
+	inventoryUseCase := usecase.NewInventoryUseCase(inventoryRepository)
+	inventoryHandler := handler.NewInventoryHandler(inventoryUseCase)
+	shipmentUseCase := usecase.NewShipmentUseCase(shipmentRepository)
+	shipmentHandler := handler.NewShipmentHandler(shipmentUseCase)
+	refundHandler := handler.NewRefundHandler(refundUseCase)
+	walletRepository := repository.NewWalletRepository(gormDB)
+	walletUseCase := usecase.NewWalletUseCase(walletRepository)
+	walletHandler := handler.NewWalletHandler(walletUseCase)
+	codRepository := repository.NewCODRepository(gormDB)
+	codUseCase := usecase.NewCODUseCase(codRepository)
+	codHandler := handler.NewCODHandler(codUseCase)
+	campaignRepository := repository.NewCampaignRepository(gormDB)
+	campaignUseCase := usecase.NewCampaignUseCase(campaignRepository)
+	campaignHandler := handler.NewCampaignHandler(campaignUseCase)
+	idempotencyRepository := repository.NewIdempotencyRepository(gormDB)
+	idempotencyUseCase := usecase.NewIdempotencyUseCase(idempotencyRepository, cfg)
+	idempotency := middleware.NewIdempotency(idempotencyUseCase)
+	paymentReconciler := job.NewPaymentReconciler(cfg, paymentUseCases)
+	serverHTTP := http.NewServerHTTP(userHandler, adminHandler, otpHandler, productHandler, cartHandler, orderHandler, paymentHandler, wishlistHandler, inventoryHandler, shipmentHandler, refundHandler, walletHandler, codHandler, campaignHandler, idempotency, paymentReconciler)
 	return serverHTTP, nil
 }
+
+func InitializePaymentReconciler(cfg config.Config) (*job.PaymentReconciler, error) {
+	gormDB, err := db.ConnectDatabase(cfg)
+	if err != nil {
+		return nil, err
+	}
+	orderRepository := repository.NewOrderRepository(gormDB)
+	paymentRepository := repository.NewPaymentRepository(gormDB)
+	refundRepository := repository.NewRefundRepository(gormDB)
+	paymentGateway := gateway.NewRazorpayGateway(cfg)
+	refundUseCase := usecase.NewRefundUseCase(refundRepository, paymentRepository, paymentGateway)
+	paymentUseCases := usecase.NewPaymentUseCase(orderRepository, paymentRepository, paymentGateway, refundUseCase)
+	paymentReconciler := job.NewPaymentReconciler(cfg, paymentUseCases)
+	return paymentReconciler, nil
+}
@@ -1,20 +1,23 @@
 package domain
 
 type Cart struct {
-	ID       uint    `json:"id"`
-	UserID   uint    `json:"user_id"`
-	Users    Users   `gorm:"foreignKey:UserID" json:"-"`
-	CouponID int     `json:"coupon_id"`
-	SubTotal float64 `json:"sub_total"`
-	Discount float64 `json:"discount"`
-	Total    float64 `json:"total"`
+	ID            uint    `json:"id"`
+	UserID        uint    `json:"user_id"`
+	Users         Users   `gorm:"foreignKey:UserID" json:"-"`
+	CouponID      int     `json:"coupon_id"`
+	CouponRemoved string  `json:"coupon_removed"` // why the last coupon was removed, kept until another coupon is applied
+	SubTotal      float64 `json:"sub_total"`
+	Discount      float64 `json:"discount"`
+	Tax           float64 `json:"tax"`
+	Total         float64 `json:"total"`
 }
 
 type CartItems struct {
-	ID            uint        `json:"id"`
-	CartID        uint        `json:"cart_id"`
-	Cart          Cart        `gorm:"foreignKey:CartID" json:"-"`
-	ProductItemID uint        `json:"product_item_id"`
-	ProductItem   ProductItem `json:"-"`
-	Quantity      uint        `json:"quantity"`
+	ID             uint        `json:"id"`
+	CartID         uint        `json:"cart_id"`
+	Cart           Cart        `gorm:"foreignKey:CartID" json:"-"`
+	ProductItemID  uint        `json:"product_item_id"`
+	ProductItem    ProductItem `json:"-"`
+	Quantity       uint        `json:"quantity"`
+	PriceWhenAdded float64     `json:"price_when_added"` // price when the item was put in the cart or its quantity was last set
 }
@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// CODRules decide which orders can be paid cash on delivery. There is a single row of rules, a limit of zero means no limit.
+// Cash on delivery is available in every pincode until pincodes are added to the allowlist.
+type CODRules struct {
+	ID                   uint      `gorm:"primaryKey" json:"-"`
+	MaxOrderValue        float64   `gorm:"not null;default:0" json:"max_order_value"`
+	MaxRefusedDeliveries int       `gorm:"not null;default:0" json:"max_refused_deliveries"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+// CODPincode is a pincode cash on delivery is available in
+type CODPincode struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Pincode   string    `gorm:"unique;not null" json:"pincode"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CODCollectionStatus string
+
+// the courier either collects the cash at the door, or the user refuses to take the delivery
+const (
+	CODCollected CODCollectionStatus = "collected"
+	CODRefused   CODCollectionStatus = "refused"
+)
+
+// CODCollection is the outcome of a cash on delivery attempt, as reported by the courier. A refused delivery can be
+// attempted again, but an order is collected only once.
+type CODCollection struct {
+	ID               uint                `gorm:"primaryKey" json:"id"`
+	OrderID          uint                `gorm:"not null;index" json:"order_id"`
+	Order            Order               `gorm:"foreignKey:OrderID" json:"-"`
+	PaymentDetailsID uint                `gorm:"not null" json:"payment_details_id"`
+	PaymentDetails   PaymentDetails      `gorm:"foreignKey:PaymentDetailsID" json:"-"`
+	Status           CODCollectionStatus `gorm:"not null" json:"status"`
+	Amount           float64             `gorm:"not null;default:0" json:"amount"`
+	CollectedBy      string              `gorm:"not null" json:"collected_by"`
+	AdminID          uint                `json:"admin_id"`
+	Note             string              `json:"note,omitempty"`
+	CreatedAt        time.Time           `json:"created_at"`
+}
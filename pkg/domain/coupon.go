@@ -2,11 +2,79 @@ package domain
 
 import "time"
 
+type CouponDiscountType string
+
+// a percent discount is capped at the max amount of the coupon, a flat discount takes the discount amount off
+const (
+	CouponPercent CouponDiscountType = "percent"
+	CouponFlat    CouponDiscountType = "flat"
+)
+
+type CouponScope string
+
+// the items a coupon gives a discount on. A coupon scoped to categories, brands or product items is limited to
+// the items of its targets.
+const (
+	CouponScopeCart        CouponScope = "cart"
+	CouponScopeCategory    CouponScope = "category"
+	CouponScopeBrand       CouponScope = "brand"
+	CouponScopeProductItem CouponScope = "product_item"
+)
+
 type Coupon struct {
-	ID                uint      `gorm:"primaryKey" json:"id,omitempty"`
-	Code              string    `gorm:"unique" json:"code,omitempty"`
-	MinOrderValue     float64   `json:"min_order_value,omitempty"`
-	DiscountPercent   float64   `json:"discount_percent,omitempty"`
-	DiscountMaxAmount float64   `json:"discount_max_amount,omitempty"`
-	ValidTill         time.Time `json:"valid_till"`
+	ID                uint               `gorm:"primaryKey" json:"id,omitempty"`
+	Code              string             `gorm:"unique" json:"code,omitempty"`
+	MinOrderValue     float64            `json:"min_order_value,omitempty"`
+	DiscountType      CouponDiscountType `gorm:"not null;default:'percent'" json:"discount_type"`
+	DiscountPercent   float64            `json:"discount_percent,omitempty"`
+	DiscountMaxAmount float64            `json:"discount_max_amount,omitempty"`
+	DiscountAmount    float64            `gorm:"not null;default:0" json:"discount_amount,omitempty"`
+	Scope             CouponScope        `gorm:"not null;default:'cart'" json:"scope"`
+	ValidFrom         time.Time          `gorm:"not null;default:CURRENT_TIMESTAMP" json:"valid_from"`
+	ValidTill         time.Time          `json:"valid_till"`
+	UsageLimit        int                `gorm:"not null;default:0" json:"usage_limit"`    // 0 means no limit
+	PerUserLimit      int                `gorm:"not null;default:1" json:"per_user_limit"` // 0 means no limit
+	FirstOrderOnly    bool               `gorm:"not null;default:false" json:"first_order_only"`
+	CampaignID        *uint              `gorm:"index" json:"campaign_id,omitempty"` // set on the codes generated for a campaign
+}
+
+// CouponCampaign is a batch of single use coupons generated from the same rules, each with a random code of its own.
+// A code is the prefix of the campaign followed by code length characters from the alphabet of the campaign.
+type CouponCampaign struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Name       string    `gorm:"not null;unique" json:"name"`
+	Prefix     string    `gorm:"not null;default:''" json:"prefix"`
+	Alphabet   string    `gorm:"not null" json:"alphabet"`
+	CodeLength int       `gorm:"not null" json:"code_length"`
+	CodeCount  int       `gorm:"not null" json:"code_count"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CouponTarget is a category, brand or product item a scoped coupon is limited to, depending on the scope of the coupon
+type CouponTarget struct {
+	ID       uint `gorm:"primaryKey" json:"-"`
+	CouponID uint `gorm:"not null;uniqueIndex:idx_coupon_target" json:"coupon_id"`
+	TargetID uint `gorm:"not null;uniqueIndex:idx_coupon_target" json:"target_id"`
+}
+
+// CouponUser is a user a targeted coupon is for. A coupon without users is for everyone.
+type CouponUser struct {
+	ID       uint `gorm:"primaryKey" json:"-"`
+	CouponID uint `gorm:"not null;uniqueIndex:idx_coupon_user" json:"coupon_id"`
+	UserID   uint `gorm:"not null;uniqueIndex:idx_coupon_user" json:"user_id"`
+}
+
+// CouponRedemption is a use of a coupon by an order. The slots number the uses that count towards the usage limit and
+// the per user limit of the coupon, and the unique indexes on them stop two orders from taking the same use. A redemption
+// gives up its slots when it is released, so that the coupon can be used again.
+type CouponRedemption struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	CouponID   uint       `gorm:"not null;uniqueIndex:idx_coupon_usage_slot;uniqueIndex:idx_coupon_user_slot;index" json:"coupon_id"`
+	UserID     uint       `gorm:"not null;uniqueIndex:idx_coupon_user_slot" json:"user_id"`
+	OrderID    uint       `gorm:"not null;uniqueIndex" json:"order_id"`
+	UsageSlot  *int       `gorm:"uniqueIndex:idx_coupon_usage_slot" json:"-"` // null if the coupon has no usage limit
+	UserSlot   *int       `gorm:"uniqueIndex:idx_coupon_user_slot" json:"-"`  // null if the coupon has no per user limit
+	Discount   float64    `gorm:"not null;default:0" json:"discount"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ReleasedAt *time.Time `json:"released_at,omitempty"`
 }
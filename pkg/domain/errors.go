@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// OutOfStockError is returned when checkout cannot reserve the ordered quantity of a product item,
+// either because the stock is not enough or because a concurrent checkout reserved it first.
+type OutOfStockError struct {
+	ProductItemID uint
+	Requested     int
+}
+
+func (e *OutOfStockError) Error() string {
+	return fmt.Sprintf("product item out of stock for id : %v", e.ProductItemID)
+}
+
+// CartItemError is returned when a cart item cannot be checked out as it is, because its price changed since it
+// was added or its quantity is over the purchase limit. The item has to be updated in the cart first.
+type CartItemError struct {
+	ProductItemID uint
+	Reason        string
+}
+
+func (e *CartItemError) Error() string {
+	return fmt.Sprintf("product item %v: %v", e.ProductItemID, e.Reason)
+}
+
+// OrderStatusError is returned when an order cannot be moved to the requested status from the status it is in,
+// or when its status was changed by another request in the meantime.
+type OrderStatusError struct {
+	Reason string
+}
+
+func (e *OrderStatusError) Error() string {
+	return e.Reason
+}
+
+// ShipmentStatusError is returned when a tracking event cannot be posted to a shipment in its current status
+type ShipmentStatusError struct {
+	Reason string
+}
+
+func (e *ShipmentStatusError) Error() string {
+	return e.Reason
+}
+
+// QueryParamsError is returned when a listing is asked to be filtered or sorted on a field it cannot be, or with a
+// filter that is not valid for the field
+type QueryParamsError struct {
+	Reason string
+}
+
+func (e *QueryParamsError) Error() string {
+	return e.Reason
+}
+
+// ErrInvalidSignature is returned when a callback or webhook claiming to come from the payment gateway is not signed by it
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// ErrIdempotencyKeyReused is returned when an idempotency key is sent again with a different request
+var ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different request")
+
+// ErrIdempotencyKeyInProgress is returned when a request is retried while the first request with its key is still running
+var ErrIdempotencyKeyInProgress = errors.New("a request with this idempotency key is still in progress")
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// IdempotencyKey is a request sent with an Idempotency-Key header, along with the response it got. A retry of the request
+// with the same key gets the stored response instead of being run again, until the key expires.
+type IdempotencyKey struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	UserID       uint      `gorm:"not null;uniqueIndex:idx_user_idempotency_key" json:"user_id"`
+	Key          string    `gorm:"not null;uniqueIndex:idx_user_idempotency_key" json:"key"`
+	RequestHash  string    `gorm:"not null" json:"request_hash"`
+	Completed    bool      `gorm:"not null;default:false" json:"completed"`
+	StatusCode   int       `json:"status_code"`
+	ContentType  string    `json:"content_type"`
+	ResponseBody []byte    `json:"-"`
+	ExpiresAt    time.Time `gorm:"not null;index" json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+type StockMovementReason string
+
+const (
+	StockRestock      StockMovementReason = "restock"
+	StockSale         StockMovementReason = "sale"
+	StockCancellation StockMovementReason = "cancellation"
+	StockReturn       StockMovementReason = "return"
+	StockAdjustment   StockMovementReason = "adjustment"
+)
+
+// StockMovement is an entry in the inventory ledger. Every change to ProductItem.QntyInStock is recorded as a
+// movement, so the sum of the movements of a product item always equals its stock.
+type StockMovement struct {
+	ID            uint                `gorm:"primaryKey" json:"id"`
+	ProductItemID uint                `gorm:"not null;index" json:"product_item_id"`
+	Quantity      int                 `gorm:"not null" json:"quantity"`
+	Reason        StockMovementReason `gorm:"not null" json:"reason"`
+	AdminID       uint                `json:"admin_id,omitempty"`
+	OrderID       uint                `json:"order_id,omitempty"`
+	Note          string              `json:"note,omitempty"`
+	CreatedAt     time.Time           `json:"created_at"`
+}
@@ -3,21 +3,34 @@ package domain
 import "time"
 
 type Order struct {
-	ID                uint           `gorm:"primaryKey"`
-	UserID            uint           `json:"user_id"`
-	Users             Users          `gorm:"foreignKey:UserID" json:"-"`
-	OrderDate         time.Time      `json:"order_date"`
-	PaymentMethodID   uint           `json:"payment_method_id"`
-	PaymentMethod     PaymentMethod  `gorm:"foreignKey:PaymentMethodID" json:"-"`
-	ShippingAddressID uint           `json:"shipping_address_id"`
-	Address           Address        `gorm:"foreignKey:ShippingAddressID" json:"-"`
-	OrderTotal        float64        `json:"order_total"`
-	OrderStatusID     uint           `json:"order_status_id"`
-	OrderStatus       OrderStatus    `gorm:"foreignKey:OrderStatusID" json:"-"`
-	CouponID          uint           `json:"coupon_id"`
-	DeliveryStatusID  int            `json:"delivery_status_id"`
-	DeliveryStatus    DeliveryStatus `gorm:"primaryKey" json:"-"`
-	DeliveryUpdatedAt time.Time      `json:"delivery_time"`
+	ID                uint             `gorm:"primaryKey"`
+	UserID            uint             `json:"user_id"`
+	Users             Users            `gorm:"foreignKey:UserID" json:"-"`
+	OrderDate         time.Time        `json:"order_date"`
+	PaymentMethodID   PaymentMethodID  `json:"payment_method_id"`
+	PaymentMethod     PaymentMethod    `gorm:"foreignKey:PaymentMethodID" json:"-"`
+	ShippingAddressID uint             `json:"shipping_address_id"`
+	Address           Address          `gorm:"foreignKey:ShippingAddressID" json:"-"`
+	ShippingAddress   OrderAddress     `gorm:"embedded;embeddedPrefix:shipping_" json:"shipping_address"`
+	OrderTotal        float64          `json:"order_total"`
+	OrderStatusID     OrderStatusID    `json:"order_status_id"`
+	OrderStatus       OrderStatus      `gorm:"foreignKey:OrderStatusID" json:"-"`
+	CouponID          uint             `json:"coupon_id"`
+	DeliveryStatusID  DeliveryStatusID `json:"delivery_status_id"`
+	DeliveryStatus    DeliveryStatus   `gorm:"primaryKey" json:"-"`
+	DeliveryUpdatedAt time.Time        `json:"delivery_time"`
+}
+
+// OrderAddress is a copy of the address an order was placed with. It is stored on the order itself,
+// so that later changes to the address book of the user do not change where past orders were sent.
+type OrderAddress struct {
+	HouseNumber string `json:"house_number"`
+	Street      string `json:"street"`
+	City        string `json:"city"`
+	District    string `json:"district"`
+	Pincode     string `json:"pincode"`
+	Landmark    string `json:"landmark"`
+	Label       string `json:"label"`
 }
 
 type OrderLine struct {
@@ -27,7 +40,8 @@ type OrderLine struct {
 	OrderID       uint        `json:"order_Id"`
 	Order         Order       `gorm:"foreignKey:OrderID" json:"-"`
 	Quantity      int         `json:"quantity"`
-	Price         float64     `json:"price"`
+	UnitPrice     float64     `json:"unit_price"`
+	LineTotal     float64     `json:"line_total"`
 }
 
 type OrderStatus struct {
@@ -40,10 +54,26 @@ type DeliveryStatus struct {
 	Status string `json:"status"`
 }
 
+type ReturnStatus string
+
+// a return request moves from requested to either rejected, or approved, picked up and received
+const (
+	ReturnRequested ReturnStatus = "requested"
+	ReturnApproved  ReturnStatus = "approved"
+	ReturnRejected  ReturnStatus = "rejected"
+	ReturnPickedUp  ReturnStatus = "picked_up"
+	ReturnReceived  ReturnStatus = "received"
+)
+
 type Return struct {
-	ID       uint   `gorm:"primaryKey"`
-	OrderID  int    `json:"order_id"`
-	Order    Order  `gorm:"foreignKey:OrderID"`
-	Reason   string `json:"string"`
-	Approved bool   `json:"approved"`
+	ID         uint         `gorm:"primaryKey" json:"id"`
+	OrderID    int          `json:"order_id"`
+	Order      Order        `gorm:"foreignKey:OrderID" json:"-"`
+	Reason     string       `json:"reason"`
+	Status     ReturnStatus `json:"status"`
+	AdminNote  string       `json:"admin_note"`
+	CreatedAt  time.Time    `json:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+	PickedUpAt time.Time    `json:"picked_up_at"`
+	ReceivedAt time.Time    `json:"received_at"`
 }
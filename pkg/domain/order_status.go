@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// allowedOrderStatuses lists the statuses an order can move to from the given status.
+// Cancelled orders cannot move anywhere, and an order with a return requested leaves it only when the return is
+// approved or rejected.
+func allowedOrderStatuses(from OrderStatusID) []OrderStatusID {
+	switch from {
+	case OrderPending:
+		return []OrderStatusID{OrderCancelledByUser, OrderCancelledByAdmin, OrderCompleted}
+	case OrderCompleted:
+		return []OrderStatusID{OrderReturnRequested}
+	}
+	return nil
+}
+
+// allowedDeliveryStatuses lists the statuses a delivery can move to from the given status.
+func allowedDeliveryStatuses(from DeliveryStatusID) []DeliveryStatusID {
+	switch from {
+	case DeliveryPending:
+		return []DeliveryStatusID{DeliveryDelivered}
+	}
+	return nil
+}
+
+// OrderStatusHistory records every change to the order or delivery status of an order
+type OrderStatusHistory struct {
+	ID               uint             `gorm:"primaryKey" json:"id"`
+	OrderID          uint             `gorm:"not null;index" json:"order_id"`
+	OrderStatusID    OrderStatusID    `json:"order_status_id"`
+	OrderStatus      string           `gorm:"-" json:"order_status"`
+	DeliveryStatusID DeliveryStatusID `json:"delivery_status_id"`
+	DeliveryStatus   string           `gorm:"-" json:"delivery_status"`
+	ChangedBy        string           `json:"changed_by"`
+	CreatedAt        time.Time        `json:"created_at"`
+}
+
+// CheckOrderTransition checks if an order in the given order and delivery status can be moved to the new ones.
+// Keeping a status unchanged is always allowed.
+func CheckOrderTransition(fromStatus OrderStatusID, fromDelivery DeliveryStatusID, toStatus OrderStatusID, toDelivery DeliveryStatusID) error {
+	if fromStatus != toStatus && !contains(allowedOrderStatuses(fromStatus), toStatus) {
+		return &OrderStatusError{Reason: fmt.Sprintf("cannot change order status from %v to %v", fromStatus.Name(), toStatus.Name())}
+	}
+	if fromDelivery != toDelivery && !contains(allowedDeliveryStatuses(fromDelivery), toDelivery) {
+		return &OrderStatusError{Reason: fmt.Sprintf("cannot change delivery status from %v to %v", fromDelivery.Name(), toDelivery.Name())}
+	}
+	if toStatus == OrderCompleted && toDelivery != DeliveryDelivered {
+		return &OrderStatusError{Reason: "order cannot be completed before it is delivered"}
+	}
+	if (toStatus == OrderCancelledByUser || toStatus == OrderCancelledByAdmin) && toDelivery == DeliveryDelivered {
+		return &OrderStatusError{Reason: "delivered order cannot be cancelled"}
+	}
+	return nil
+}
+
+func contains[T comparable](values []T, value T) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -1,17 +1,22 @@
 package domain
 
-import "time"
+import (
+	"math"
+	"time"
+)
 
 type PaymentDetails struct {
-	ID              uint          `gorm:"primaryKey" json:"id,omitempty"`
-	OrderID         uint          `json:"order_id,omitempty"`
-	Order           Order         `gorm:"foreignKey:OrderID" json:"-"`
-	OrderTotal      float64       `json:"order_total"`
-	PaymentMethodID uint          `json:"payment_method_id"`
-	PaymentMethod   PaymentMethod `gorm:"foreignKey:PaymentMethodID"`
-	PaymentStatusID uint          `json:"payment_status_id,omitempty"`
-	PaymentStatus   PaymentStatus `gorm:"foreignKey:PaymentStatusID" json:"-"`
-	PaymentRef      string        `gorm:"unique"`
+	ID              uint            `gorm:"primaryKey" json:"id,omitempty"`
+	OrderID         uint            `json:"order_id,omitempty"`
+	Order           Order           `gorm:"foreignKey:OrderID" json:"-"`
+	OrderTotal      float64         `json:"order_total"`
+	WalletAmount    float64         `gorm:"not null;default:0" json:"wallet_amount"`
+	PaymentMethodID PaymentMethodID `json:"payment_method_id"`
+	PaymentMethod   PaymentMethod   `gorm:"foreignKey:PaymentMethodID"`
+	PaymentStatusID PaymentStatusID `json:"payment_status_id,omitempty"`
+	PaymentStatus   PaymentStatus   `gorm:"foreignKey:PaymentStatusID" json:"-"`
+	PaymentRef      string          `gorm:"unique"`
+	GatewayOrderID  string          `gorm:"index" json:"gateway_order_id,omitempty"`
 	UpdatedAt       time.Time
 }
 
@@ -24,3 +29,55 @@ type PaymentMethod struct {
 	ID            uint `gorm:"primaryKey"`
 	PaymentMethod string
 }
+
+// AmountDue is the part of the order total paid through the payment method of the order, the rest is paid from the wallet
+func (p PaymentDetails) AmountDue() float64 {
+	return math.Round((p.OrderTotal-p.WalletAmount)*100) / 100
+}
+
+type RefundStatus string
+
+// a refund is initiated when it is created, and is processed or failed as reported by the payment gateway.
+// A failed refund can be retried, which initiates it again.
+const (
+	RefundInitiated RefundStatus = "initiated"
+	RefundProcessed RefundStatus = "processed"
+	RefundFailed    RefundStatus = "failed"
+)
+
+type RefundDestination string
+
+// the part of an order paid online is refunded through the payment gateway, everything else is credited to the wallet
+const (
+	RefundToGateway RefundDestination = "gateway"
+	RefundToWallet  RefundDestination = "wallet"
+)
+
+// Refund is money owed back to the user against the payment of an order. An order can be refunded in parts,
+// but never more than was paid for it.
+type Refund struct {
+	ID               uint              `gorm:"primaryKey" json:"id"`
+	OrderID          uint              `gorm:"index" json:"order_id"`
+	Order            Order             `gorm:"foreignKey:OrderID" json:"-"`
+	PaymentDetailsID uint              `json:"payment_details_id"`
+	PaymentDetails   PaymentDetails    `gorm:"foreignKey:PaymentDetailsID" json:"-"`
+	ReturnID         uint              `json:"return_id,omitempty"`
+	Amount           float64           `json:"amount"`
+	Reason           string            `json:"reason,omitempty"`
+	Destination      RefundDestination `json:"destination"`
+	Status           RefundStatus      `json:"status"`
+	RefundRef        string            `json:"refund_ref,omitempty"`
+	FailureReason    string            `json:"failure_reason,omitempty"`
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+}
+
+// PaymentEvent is a webhook event received from the payment gateway. Gateways deliver an event more than once,
+// so events are recorded by their id and an event that is already recorded is not applied again.
+type PaymentEvent struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	EventID    string    `gorm:"unique;not null" json:"event_id"`
+	Event      string    `gorm:"not null" json:"event"`
+	PaymentRef string    `json:"payment_ref"`
+	CreatedAt  time.Time `json:"created_at"`
+}
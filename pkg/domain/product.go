@@ -1,8 +1,9 @@
 package domain
 
 type ProductCategory struct {
-	ID           uint   `gorm:"primaryKey,uniqueIndex" json:"id"`
-	CategoryName string `gorm:"not null,index,unique" json:"category_name"`
+	ID           uint    `gorm:"primaryKey,uniqueIndex" json:"id"`
+	CategoryName string  `gorm:"not null,index,unique" json:"category_name"`
+	TaxPercent   float64 `gorm:"not null;default:0" json:"tax_percent"` // tax charged on products of the category
 }
 
 type ProductBrand struct {
@@ -36,4 +37,5 @@ type ProductItem struct {
 	QntyInStock      int     `gorm:"not null" json:"qnty_in_stock" validate:"required"`
 	ProductItemImage string  `json:"product_item_image"`
 	Price            float64 `gorm:"not null" json:"price" validate:"required"`
+	MaxPerOrder      int     `gorm:"not null;default:0" json:"max_per_order"` // 0 means no limit
 }
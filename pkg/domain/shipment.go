@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type ShipmentStatus string
+
+// a shipment is dispatched when it is created, and moves through transit until it is delivered
+const (
+	ShipmentDispatched     ShipmentStatus = "dispatched"
+	ShipmentInTransit      ShipmentStatus = "in_transit"
+	ShipmentOutForDelivery ShipmentStatus = "out_for_delivery"
+	ShipmentDelivered      ShipmentStatus = "delivered"
+)
+
+// Carrier is a courier service orders are shipped with. TrackingURL is the page a tracking number can be looked up on,
+// with {tracking_number} in place of the tracking number.
+type Carrier struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	Name        string `gorm:"unique;not null" json:"name"`
+	TrackingURL string `json:"tracking_url"`
+}
+
+// Shipment is a parcel handed over to a carrier. An order can be sent in more than one shipment,
+// each carrying some quantity of its order lines.
+type Shipment struct {
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	OrderID        uint           `gorm:"not null;index" json:"order_id"`
+	Order          Order          `gorm:"foreignKey:OrderID" json:"-"`
+	CarrierID      uint           `gorm:"not null;uniqueIndex:idx_carrier_tracking_number" json:"carrier_id"`
+	Carrier        Carrier        `gorm:"foreignKey:CarrierID" json:"-"`
+	TrackingNumber string         `gorm:"not null;uniqueIndex:idx_carrier_tracking_number" json:"tracking_number"`
+	Status         ShipmentStatus `gorm:"not null" json:"status"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+type ShipmentLine struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	ShipmentID  uint      `gorm:"not null;index" json:"shipment_id"`
+	Shipment    Shipment  `gorm:"foreignKey:ShipmentID" json:"-"`
+	OrderLineID uint      `gorm:"not null;index" json:"order_line_id"`
+	OrderLine   OrderLine `gorm:"foreignKey:OrderLineID" json:"-"`
+	Quantity    int       `gorm:"not null" json:"quantity"`
+}
+
+// ShipmentEvent is a step in the tracking timeline of a shipment, as reported by the carrier
+type ShipmentEvent struct {
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	ShipmentID uint           `gorm:"not null;index" json:"shipment_id"`
+	Shipment   Shipment       `gorm:"foreignKey:ShipmentID" json:"-"`
+	Status     ShipmentStatus `gorm:"not null" json:"status"`
+	Location   string         `json:"location,omitempty"`
+	Note       string         `json:"note,omitempty"`
+	OccurredAt time.Time      `json:"occurred_at"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+// allowedShipmentStatuses lists the statuses a shipment can move to from the given status. A shipment can be scanned
+// in transit or go out for delivery more than once, but nothing follows delivery.
+func allowedShipmentStatuses(from ShipmentStatus) []ShipmentStatus {
+	switch from {
+	case ShipmentDispatched:
+		return []ShipmentStatus{ShipmentInTransit, ShipmentOutForDelivery, ShipmentDelivered}
+	case ShipmentInTransit:
+		return []ShipmentStatus{ShipmentInTransit, ShipmentOutForDelivery, ShipmentDelivered}
+	case ShipmentOutForDelivery:
+		return []ShipmentStatus{ShipmentInTransit, ShipmentOutForDelivery, ShipmentDelivered}
+	}
+	return nil
+}
+
+// CheckShipmentTransition checks if a shipment in the given status can have an event with the new status posted
+func CheckShipmentTransition(from, to ShipmentStatus) error {
+	if !contains(allowedShipmentStatuses(from), to) {
+		return &ShipmentStatusError{Reason: fmt.Sprintf("cannot move shipment from %v to %v", from, to)}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package domain
+
+import "fmt"
+
+type OrderStatusID uint
+type DeliveryStatusID uint
+type PaymentStatusID uint
+type PaymentMethodID uint
+
+// The ids of statuses and payment methods depend on the order the seed queries in db.ConnectDatabase inserted them in,
+// so they are resolved by name when the database is connected. The values below are the ids a fresh database gets.
+var (
+	OrderPending          OrderStatusID = 1
+	OrderCancelledByUser  OrderStatusID = 2
+	OrderCancelledByAdmin OrderStatusID = 3
+	OrderCompleted        OrderStatusID = 4
+	OrderReturnRequested  OrderStatusID = 5
+
+	DeliveryDelivered DeliveryStatusID = 1
+	DeliveryPending   DeliveryStatusID = 2
+
+	PaymentPending   PaymentStatusID = 1
+	PaymentCompleted PaymentStatusID = 2
+	PaymentFailed    PaymentStatusID = 3
+	PaymentRefunded  PaymentStatusID = 4
+	PaymentExpired   PaymentStatusID = 5
+
+	PaymentCOD    PaymentMethodID = 1
+	PaymentOnline PaymentMethodID = 2
+	PaymentWallet PaymentMethodID = 3
+)
+
+// OrderStatuses maps the name of every status seeded in order_statuses to the value it is resolved into
+var OrderStatuses = map[string]*OrderStatusID{
+	"pending":            &OrderPending,
+	"cancelled by user":  &OrderCancelledByUser,
+	"cancelled by admin": &OrderCancelledByAdmin,
+	"completed":          &OrderCompleted,
+	"return requested":   &OrderReturnRequested,
+}
+
+// DeliveryStatuses maps the name of every status seeded in delivery_statuses to the value it is resolved into
+var DeliveryStatuses = map[string]*DeliveryStatusID{
+	"delivered": &DeliveryDelivered,
+	"pending":   &DeliveryPending,
+}
+
+// PaymentStatuses maps the name of every status seeded in payment_statuses to the value it is resolved into
+var PaymentStatuses = map[string]*PaymentStatusID{
+	"pending":   &PaymentPending,
+	"completed": &PaymentCompleted,
+	"failed":    &PaymentFailed,
+	"refunded":  &PaymentRefunded,
+	"expired":   &PaymentExpired,
+}
+
+// PaymentMethods maps the name of every method seeded in payment_methods to the value it is resolved into
+var PaymentMethods = map[string]*PaymentMethodID{
+	"cod":    &PaymentCOD,
+	"online": &PaymentOnline,
+	"wallet": &PaymentWallet,
+}
+
+// Name returns the name the status is seeded with, the types do not implement fmt.Stringer so that the database driver
+// keeps sending them as ids
+func (s OrderStatusID) Name() string {
+	return nameOf(OrderStatuses, s)
+}
+
+func (s DeliveryStatusID) Name() string {
+	return nameOf(DeliveryStatuses, s)
+}
+
+func (s PaymentStatusID) Name() string {
+	return nameOf(PaymentStatuses, s)
+}
+
+func (m PaymentMethodID) Name() string {
+	return nameOf(PaymentMethods, m)
+}
+
+func nameOf[T ~uint](lookup map[string]*T, value T) string {
+	for name, v := range lookup {
+		if *v == value {
+			return name
+		}
+	}
+	return fmt.Sprintf("unknown (%d)", uint(value))
+}
@@ -37,4 +37,7 @@ type Address struct {
 	District    string `json:"district"`
 	Pincode     string `json:"pincode"`
 	Landmark    string `json:"landmark"`
+	Label       string `json:"label"`
+	IsDefault   bool   `gorm:"not null;default:false" json:"is_default"`
+	IsDeleted   bool   `gorm:"not null;default:false" json:"-"`
 }
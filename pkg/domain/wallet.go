@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+type WalletEntryReason string
+
+const (
+	WalletRefund     WalletEntryReason = "refund"
+	WalletGoodwill   WalletEntryReason = "goodwill"
+	WalletReferral   WalletEntryReason = "referral"
+	WalletPurchase   WalletEntryReason = "purchase"
+	WalletAdjustment WalletEntryReason = "adjustment"
+)
+
+// WalletEntry is an entry in the wallet ledger of a user. Entries are never changed once written, the balance of a
+// wallet is the sum of its entries. Credits are positive and debits negative.
+type WalletEntry struct {
+	ID           uint              `gorm:"primaryKey" json:"id"`
+	UserID       uint              `gorm:"not null;index" json:"user_id"`
+	User         Users             `gorm:"foreignKey:UserID" json:"-"`
+	Amount       float64           `gorm:"not null" json:"amount"`
+	BalanceAfter float64           `gorm:"not null" json:"balance_after"`
+	Reason       WalletEntryReason `gorm:"not null" json:"reason"`
+	OrderID      uint              `json:"order_id,omitempty"`
+	RefundID     uint              `json:"refund_id,omitempty"`
+	AdminID      uint              `json:"admin_id,omitempty"`
+	Note         string            `json:"note,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
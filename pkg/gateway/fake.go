@@ -0,0 +1,150 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/webhook"
+	"sort"
+	"sync"
+)
+
+// FakeGateway is an in-memory PaymentGateway, so that the payment flow can be tested without a gateway account.
+// Checkouts and webhooks are signed with the secret it is created with, the same way razorpay signs them.
+type FakeGateway struct {
+	secret string
+
+	mu       sync.Mutex
+	nextID   int
+	orders   map[string]float64
+	payments map[string]*Payment
+	refunded map[string]float64
+}
+
+func NewFakeGateway(secret string) *FakeGateway {
+	return &FakeGateway{
+		secret:   secret,
+		orders:   make(map[string]float64),
+		payments: make(map[string]*Payment),
+		refunded: make(map[string]float64),
+	}
+}
+
+func (g *FakeGateway) KeyID() string {
+	return "fake_key"
+}
+
+func (g *FakeGateway) CreateOrder(ctx context.Context, receipt string, amount float64, notes map[string]string) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	orderID := g.newID("order")
+	g.orders[orderID] = amount
+	return orderID, nil
+}
+
+// Pay makes an authorized payment of the full amount of a gateway order, and returns the payment id and signature
+// the checkout would return to the browser
+func (g *FakeGateway) Pay(gatewayOrderID string) (string, string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	amount, ok := g.orders[gatewayOrderID]
+	if !ok {
+		return "", "", fmt.Errorf("no gateway order found")
+	}
+	paymentID := g.newID("pay")
+	g.payments[paymentID] = &Payment{ID: paymentID, OrderID: gatewayOrderID, Amount: amount, Status: PaymentAuthorized}
+	return paymentID, webhook.Sign(g.secret, []byte(gatewayOrderID+"|"+paymentID)), nil
+}
+
+// SignWebhook signs a webhook payload the way the gateway would
+func (g *FakeGateway) SignWebhook(payload []byte) string {
+	return webhook.Sign(g.secret, payload)
+}
+
+func (g *FakeGateway) VerifyPayment(gatewayOrderID, paymentID, signature string) bool {
+	return webhook.Verify(g.secret, []byte(gatewayOrderID+"|"+paymentID), signature)
+}
+
+func (g *FakeGateway) VerifyWebhook(payload []byte, signature string) bool {
+	return webhook.Verify(g.secret, payload, signature)
+}
+
+func (g *FakeGateway) CapturePayment(ctx context.Context, paymentID string, amount float64) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	payment, ok := g.payments[paymentID]
+	if !ok {
+		return fmt.Errorf("no payment found")
+	}
+	if payment.Status != PaymentAuthorized {
+		return fmt.Errorf("payment is %v, only an authorized payment can be captured", payment.Status)
+	}
+	if payment.Amount != amount {
+		return fmt.Errorf("capture amount should be equal to the authorized amount")
+	}
+	payment.Status = PaymentCaptured
+	return nil
+}
+
+func (g *FakeGateway) Refund(ctx context.Context, paymentID string, amount float64) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	payment, ok := g.payments[paymentID]
+	if !ok {
+		return "", fmt.Errorf("no payment found")
+	}
+	if payment.Status != PaymentCaptured && payment.Status != PaymentRefunded {
+		return "", fmt.Errorf("payment is %v, only a captured payment can be refunded", payment.Status)
+	}
+	if g.refunded[paymentID]+amount > payment.Amount {
+		return "", fmt.Errorf("refund amount is more than the amount left to refund")
+	}
+	g.refunded[paymentID] += amount
+	if g.refunded[paymentID] == payment.Amount {
+		payment.Status = PaymentRefunded
+	}
+	return g.newID("rfnd"), nil
+}
+
+func (g *FakeGateway) FetchPayment(ctx context.Context, paymentID string) (Payment, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	payment, ok := g.payments[paymentID]
+	if !ok {
+		return Payment{}, fmt.Errorf("no payment found")
+	}
+	return *payment, nil
+}
+
+func (g *FakeGateway) FetchOrderPayments(ctx context.Context, gatewayOrderID string) ([]Payment, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.orders[gatewayOrderID]; !ok {
+		return nil, fmt.Errorf("no gateway order found")
+	}
+	var payments []Payment
+	for _, payment := range g.payments {
+		if payment.OrderID == gatewayOrderID {
+			payments = append(payments, *payment)
+		}
+	}
+	sort.Slice(payments, func(i, j int) bool { return payments[i].ID < payments[j].ID })
+	return payments, nil
+}
+
+// FailPayment makes a payment attempt of a gateway order that fails, the way a declined card does
+func (g *FakeGateway) FailPayment(gatewayOrderID string) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	amount, ok := g.orders[gatewayOrderID]
+	if !ok {
+		return "", fmt.Errorf("no gateway order found")
+	}
+	paymentID := g.newID("pay")
+	g.payments[paymentID] = &Payment{ID: paymentID, OrderID: gatewayOrderID, Amount: amount, Status: PaymentFailed}
+	return paymentID, nil
+}
+
+func (g *FakeGateway) newID(prefix string) string {
+	g.nextID++
+	return fmt.Sprintf("%v_fake%d", prefix, g.nextID)
+}
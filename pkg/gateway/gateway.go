@@ -0,0 +1,40 @@
+package gateway
+
+import "context"
+
+type PaymentStatus string
+
+// statuses of a payment at the gateway. An authorized payment has to be captured, or the gateway refunds it.
+const (
+	PaymentCreated    PaymentStatus = "created"
+	PaymentAuthorized PaymentStatus = "authorized"
+	PaymentCaptured   PaymentStatus = "captured"
+	PaymentRefunded   PaymentStatus = "refunded"
+	PaymentFailed     PaymentStatus = "failed"
+)
+
+// Payment is a payment as known to the gateway. Amounts are in rupees.
+type Payment struct {
+	ID      string
+	OrderID string
+	Amount  float64
+	Status  PaymentStatus
+}
+
+// PaymentGateway is the payment provider users pay for their orders through
+type PaymentGateway interface {
+	// KeyID is the public key the checkout is opened with on the browser
+	KeyID() string
+	// CreateOrder creates an order for the amount at the gateway, which the checkout pays, and returns its id
+	CreateOrder(ctx context.Context, receipt string, amount float64, notes map[string]string) (string, error)
+	// VerifyPayment checks that a checkout of the gateway order was signed by the gateway
+	VerifyPayment(gatewayOrderID, paymentID, signature string) bool
+	// VerifyWebhook checks that a webhook payload was signed by the gateway
+	VerifyWebhook(payload []byte, signature string) bool
+	CapturePayment(ctx context.Context, paymentID string, amount float64) error
+	// Refund refunds the amount of a captured payment and returns the id of the refund
+	Refund(ctx context.Context, paymentID string, amount float64) (string, error)
+	FetchPayment(ctx context.Context, paymentID string) (Payment, error)
+	// FetchOrderPayments lists every attempt made to pay a gateway order
+	FetchOrderPayments(ctx context.Context, gatewayOrderID string) ([]Payment, error)
+}
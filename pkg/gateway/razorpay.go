@@ -0,0 +1,118 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/config"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/webhook"
+	"github.com/razorpay/razorpay-go"
+	"math"
+)
+
+type razorpayGateway struct {
+	client        *razorpay.Client
+	keyID         string
+	keySecret     string
+	webhookSecret string
+}
+
+func NewRazorpayGateway(cfg config.Config) PaymentGateway {
+	return &razorpayGateway{
+		client:        razorpay.NewClient(cfg.RazorpayKeyID, cfg.RazorpayKeySecret),
+		keyID:         cfg.RazorpayKeyID,
+		keySecret:     cfg.RazorpayKeySecret,
+		webhookSecret: cfg.RazorpayWebhookSecret,
+	}
+}
+
+func (g *razorpayGateway) KeyID() string {
+	return g.keyID
+}
+
+func (g *razorpayGateway) CreateOrder(ctx context.Context, receipt string, amount float64, notes map[string]string) (string, error) {
+	data := map[string]interface{}{
+		"amount":   toPaise(amount),
+		"currency": "INR",
+		"receipt":  receipt,
+		"notes":    notes,
+	}
+	body, err := g.client.Order.Create(data, nil)
+	if err != nil {
+		return "", err
+	}
+	orderID, ok := body["id"].(string)
+	if !ok {
+		return "", fmt.Errorf("failed to create razorpay order")
+	}
+	return orderID, nil
+}
+
+// VerifyPayment checks the signature razorpay returns to the checkout, which is signed with the key secret
+func (g *razorpayGateway) VerifyPayment(gatewayOrderID, paymentID, signature string) bool {
+	return webhook.Verify(g.keySecret, []byte(gatewayOrderID+"|"+paymentID), signature)
+}
+
+// VerifyWebhook checks the X-Razorpay-Signature of a webhook, which is signed with the webhook secret
+func (g *razorpayGateway) VerifyWebhook(payload []byte, signature string) bool {
+	return webhook.Verify(g.webhookSecret, payload, signature)
+}
+
+func (g *razorpayGateway) CapturePayment(ctx context.Context, paymentID string, amount float64) error {
+	_, err := g.client.Payment.Capture(paymentID, int(toPaise(amount)), map[string]interface{}{"currency": "INR"}, nil)
+	return err
+}
+
+func (g *razorpayGateway) Refund(ctx context.Context, paymentID string, amount float64) (string, error) {
+	body, err := g.client.Payment.Refund(paymentID, int(toPaise(amount)), nil, nil)
+	if err != nil {
+		return "", err
+	}
+	refundID, ok := body["id"].(string)
+	if !ok {
+		return "", fmt.Errorf("failed to create razorpay refund")
+	}
+	return refundID, nil
+}
+
+func (g *razorpayGateway) FetchPayment(ctx context.Context, paymentID string) (Payment, error) {
+	body, err := g.client.Payment.Fetch(paymentID, nil, nil)
+	if err != nil {
+		return Payment{}, err
+	}
+	return toPayment(body), nil
+}
+
+func (g *razorpayGateway) FetchOrderPayments(ctx context.Context, gatewayOrderID string) ([]Payment, error) {
+	body, err := g.client.Order.Payments(gatewayOrderID, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	items, _ := body["items"].([]interface{})
+	payments := make([]Payment, 0, len(items))
+	for _, item := range items {
+		if entity, ok := item.(map[string]interface{}); ok {
+			payments = append(payments, toPayment(entity))
+		}
+	}
+	return payments, nil
+}
+
+// toPayment reads a payment entity returned by razorpay
+func toPayment(body map[string]interface{}) Payment {
+	//json numbers are decoded as float64
+	id, _ := body["id"].(string)
+	amount, _ := body["amount"].(float64)
+	status, _ := body["status"].(string)
+	orderID, _ := body["order_id"].(string)
+	return Payment{
+		ID:      id,
+		OrderID: orderID,
+		Amount:  amount / 100,
+		Status:  PaymentStatus(status),
+	}
+}
+
+// toPaise converts an amount in rupees to paise, the unit razorpay takes amounts in
+func toPaise(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
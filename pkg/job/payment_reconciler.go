@@ -0,0 +1,71 @@
+package job
+
+import (
+	"context"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/config"
+	services "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/usecase/interface"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"log"
+	"time"
+)
+
+// defaults used when the durations are not set in the config
+const (
+	defaultReconcileInterval = 15 * time.Minute
+	defaultReconcileAfter    = 30 * time.Minute
+	defaultExpireAfter       = 24 * time.Hour
+)
+
+// PaymentReconciler periodically settles online payments left pending, see PaymentUseCases.ReconcilePayments
+type PaymentReconciler struct {
+	paymentUseCase services.PaymentUseCases
+	Interval       time.Duration
+	OlderThan      time.Duration
+	ExpireAfter    time.Duration
+}
+
+func NewPaymentReconciler(cfg config.Config, paymentUseCase services.PaymentUseCases) *PaymentReconciler {
+	reconciler := &PaymentReconciler{
+		paymentUseCase: paymentUseCase,
+		Interval:       cfg.PaymentReconcileInterval,
+		OlderThan:      cfg.PaymentReconcileAfter,
+		ExpireAfter:    cfg.PaymentExpireAfter,
+	}
+	if reconciler.Interval <= 0 {
+		reconciler.Interval = defaultReconcileInterval
+	}
+	if reconciler.OlderThan <= 0 {
+		reconciler.OlderThan = defaultReconcileAfter
+	}
+	if reconciler.ExpireAfter <= 0 {
+		reconciler.ExpireAfter = defaultExpireAfter
+	}
+	return reconciler
+}
+
+// RunOnce reconciles the stale payments once
+func (r *PaymentReconciler) RunOnce(ctx context.Context) (model.PaymentReconciliation, error) {
+	return r.paymentUseCase.ReconcilePayments(ctx, r.OlderThan, r.ExpireAfter)
+}
+
+// Run reconciles the stale payments every interval until the context is cancelled
+func (r *PaymentReconciler) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := r.RunOnce(ctx)
+			if err != nil {
+				log.Println("payment reconciliation failed: ", err)
+				continue
+			}
+			if report.Checked > 0 {
+				log.Printf("payment reconciliation: checked %d, paid %d, failed %d, expired %d, errors %d",
+					report.Checked, report.Paid, report.Failed, report.Expired, report.Errors)
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package pricing
+
+import (
+	"fmt"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"time"
+)
+
+// Coupon is a coupon with what is needed to tell if it applies to an order of a user
+type Coupon struct {
+	domain.Coupon
+	// TargetIDs are the categories, brands or product items the coupon is limited to, depending on its scope
+	TargetIDs []uint
+	// UserIDs are the users the coupon is for, empty if it is for everyone
+	UserIDs []uint
+	// Redemptions is how many times the coupon was used, UserRedemptions how many times the user used it
+	Redemptions     int
+	UserRedemptions int
+	// UserOrders is how many orders the user placed before, leaving out cancelled orders
+	UserOrders int
+}
+
+// applies tells if the coupon gives a discount on the line
+func (c Coupon) applies(line Line) bool {
+	var id uint
+	switch c.Scope {
+	case domain.CouponScopeCategory:
+		id = line.CategoryID
+	case domain.CouponScopeBrand:
+		id = line.BrandID
+	case domain.CouponScopeProductItem:
+		id = line.ProductItemID
+	default:
+		return true
+	}
+	return containsID(c.TargetIDs, id)
+}
+
+// discount returns the discount the coupon gives the user on the lines, and which of the lines it is given on.
+// The error tells why the coupon does not apply.
+func (c Coupon) discount(userID uint, lines []PricedLine, subTotal float64, now time.Time) (float64, []bool, error) {
+	if c.ID == 0 {
+		return 0, nil, fmt.Errorf("coupon no longer exists")
+	}
+	if now.Before(c.ValidFrom) {
+		return 0, nil, fmt.Errorf("coupon %v is valid from %v", c.Code, c.ValidFrom.Format("02 Jan 2006"))
+	}
+	if c.ValidTill.Before(now) {
+		return 0, nil, fmt.Errorf("coupon %v expired on %v", c.Code, c.ValidTill.Format("02 Jan 2006"))
+	}
+	if len(c.UserIDs) > 0 && !containsID(c.UserIDs, userID) {
+		return 0, nil, fmt.Errorf("coupon %v is not available for this account", c.Code)
+	}
+	if c.UsageLimit > 0 && c.Redemptions >= c.UsageLimit {
+		return 0, nil, fmt.Errorf("coupon %v has been fully redeemed", c.Code)
+	}
+	if c.PerUserLimit > 0 && c.UserRedemptions >= c.PerUserLimit {
+		return 0, nil, fmt.Errorf("coupon %v was already used", c.Code)
+	}
+	if c.FirstOrderOnly && c.UserOrders > 0 {
+		return 0, nil, fmt.Errorf("coupon %v is only for the first order", c.Code)
+	}
+	if subTotal < c.MinOrderValue {
+		return 0, nil, fmt.Errorf("coupon %v needs a minimum order value of %v", c.Code, c.MinOrderValue)
+	}
+
+	eligible := make([]bool, len(lines))
+	var eligibleTotal float64
+	for i, line := range lines {
+		if c.applies(line.Line) {
+			eligible[i] = true
+			eligibleTotal += line.LineTotal
+		}
+	}
+	if eligibleTotal == 0 {
+		return 0, nil, fmt.Errorf("coupon %v does not apply to any of the items", c.Code)
+	}
+
+	var discount float64
+	switch c.DiscountType {
+	case domain.CouponFlat:
+		discount = c.DiscountAmount
+	default:
+		discount = eligibleTotal * (c.DiscountPercent / 100)
+		if discount > c.DiscountMaxAmount {
+			discount = c.DiscountMaxAmount
+		}
+	}
+	//a discount is never more than the items it is given on
+	if discount > eligibleTotal {
+		discount = eligibleTotal
+	}
+	return roundToPaise(discount), eligible, nil
+}
+
+func containsID(ids []uint, id uint) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
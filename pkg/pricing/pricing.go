@@ -0,0 +1,92 @@
+package pricing
+
+import (
+	"math"
+	"time"
+)
+
+// Line is an item being bought, priced at the current price of its product item. Tax is charged at the tax
+// percent of the category of the product.
+type Line struct {
+	ProductItemID uint
+	CategoryID    uint
+	BrandID       uint
+	Quantity      uint
+	UnitPrice     float64
+	TaxPercent    float64
+}
+
+// PricedLine is a line with its share of the coupon discount and the tax charged on what is left
+type PricedLine struct {
+	Line
+	LineTotal float64
+	Discount  float64
+	Tax       float64
+}
+
+// Quote is what the lines cost. Total is the sub total less the discount, plus tax. Amounts are in rupees,
+// rounded to paise.
+type Quote struct {
+	Lines    []PricedLine
+	SubTotal float64
+	CouponID uint
+	Discount float64
+	Tax      float64
+	Total    float64
+	// CouponRemoved tells why the coupon does not apply. It is empty when the coupon applies or there was none.
+	CouponRemoved string
+}
+
+// Price works out the quote of the lines for the user with the coupon, nil if there is none. A coupon that does not
+// apply is left out of the quote and the reason is set in CouponRemoved.
+func Price(lines []Line, userID uint, coupon *Coupon, now time.Time) Quote {
+	var quote Quote
+	for _, line := range lines {
+		lineTotal := roundToPaise(line.UnitPrice * float64(line.Quantity))
+		quote.Lines = append(quote.Lines, PricedLine{Line: line, LineTotal: lineTotal})
+		quote.SubTotal += lineTotal
+	}
+	quote.SubTotal = roundToPaise(quote.SubTotal)
+
+	eligible := make([]bool, len(quote.Lines))
+	if coupon != nil {
+		discount, lines, err := coupon.discount(userID, quote.Lines, quote.SubTotal, now)
+		if err != nil {
+			quote.CouponRemoved = err.Error()
+		} else {
+			quote.CouponID = coupon.ID
+			quote.Discount = discount
+			eligible = lines
+		}
+	}
+
+	//the discount is shared by the lines it is given on in proportion to their totals, the last of them takes what
+	//is left after rounding so that the shares add up to the discount
+	var eligibleTotal float64
+	last := -1
+	for i, line := range quote.Lines {
+		if eligible[i] {
+			eligibleTotal += line.LineTotal
+			last = i
+		}
+	}
+	remaining := quote.Discount
+	for i := range quote.Lines {
+		line := &quote.Lines[i]
+		if i == last {
+			line.Discount = roundToPaise(remaining)
+		} else if eligible[i] {
+			line.Discount = roundToPaise(quote.Discount * line.LineTotal / eligibleTotal)
+		}
+		remaining -= line.Discount
+		line.Tax = roundToPaise((line.LineTotal - line.Discount) * line.TaxPercent / 100)
+		quote.Tax += line.Tax
+	}
+	quote.Tax = roundToPaise(quote.Tax)
+	quote.Total = roundToPaise(quote.SubTotal - quote.Discount + quote.Tax)
+	return quote
+}
+
+func roundToPaise(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
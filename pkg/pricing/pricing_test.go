@@ -0,0 +1,176 @@
+package pricing
+
+import (
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestPrice(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	lines := []Line{
+		{ProductItemID: 1, CategoryID: 1, BrandID: 1, Quantity: 1, UnitPrice: 1000, TaxPercent: 18},
+		{ProductItemID: 2, CategoryID: 2, BrandID: 2, Quantity: 2, UnitPrice: 500},
+	}
+	var userID uint = 7
+	coupon := domain.Coupon{ID: 4, Code: "SAVE10", MinOrderValue: 1500, DiscountType: domain.CouponPercent, DiscountPercent: 10,
+		DiscountMaxAmount: 150, Scope: domain.CouponScopeCart, ValidFrom: now.Add(-24 * time.Hour), ValidTill: now.Add(24 * time.Hour), PerUserLimit: 1}
+	flat := domain.Coupon{ID: 5, Code: "FLAT300", DiscountType: domain.CouponFlat, DiscountAmount: 300, Scope: domain.CouponScopeCategory,
+		ValidFrom: now.Add(-24 * time.Hour), ValidTill: now.Add(24 * time.Hour)}
+	notApplied := Quote{
+		Lines: []PricedLine{
+			{Line: lines[0], LineTotal: 1000, Tax: 180},
+			{Line: lines[1], LineTotal: 1000},
+		},
+		SubTotal: 2000,
+		Tax:      180,
+		Total:    2180,
+	}
+	withReason := func(reason string) Quote {
+		quote := notApplied
+		quote.CouponRemoved = reason
+		return quote
+	}
+
+	tests := []struct {
+		name           string
+		coupon         *Coupon
+		expectedOutput Quote
+	}{
+		{ //test case for a cart without a coupon, tax is charged on each line at the tax of its category
+			name:   "no coupon",
+			coupon: nil,
+			expectedOutput: Quote{
+				Lines: []PricedLine{
+					{Line: lines[0], LineTotal: 1000, Tax: 180},
+					{Line: lines[1], LineTotal: 1000},
+				},
+				SubTotal: 2000,
+				Tax:      180,
+				Total:    2180,
+			},
+		},
+		{ //test case for a coupon that applies, the capped discount is shared by the lines before tax
+			name:   "coupon applies",
+			coupon: &Coupon{Coupon: coupon},
+			expectedOutput: Quote{
+				Lines: []PricedLine{
+					{Line: lines[0], LineTotal: 1000, Discount: 75, Tax: 166.5},
+					{Line: lines[1], LineTotal: 1000, Discount: 75},
+				},
+				SubTotal: 2000,
+				CouponID: 4,
+				Discount: 150,
+				Tax:      166.5,
+				Total:    2016.5,
+			},
+		},
+		{ //test case for an expired coupon, it is left out with the reason
+			name:           "coupon expired",
+			coupon:         &Coupon{Coupon: func() domain.Coupon { c := coupon; c.ValidTill = now.Add(-24 * time.Hour); return c }()},
+			expectedOutput: withReason("coupon SAVE10 expired on 09 Mar 2026"),
+		},
+		{ //test case for a coupon that is not valid yet
+			name:           "coupon not valid yet",
+			coupon:         &Coupon{Coupon: func() domain.Coupon { c := coupon; c.ValidFrom = now.Add(24 * time.Hour); return c }()},
+			expectedOutput: withReason("coupon SAVE10 is valid from 11 Mar 2026"),
+		},
+		{ //test case for a cart below the minimum order value of the coupon
+			name:           "below minimum order value",
+			coupon:         &Coupon{Coupon: func() domain.Coupon { c := coupon; c.MinOrderValue = 2500; return c }()},
+			expectedOutput: withReason("coupon SAVE10 needs a minimum order value of 2500"),
+		},
+		{ //test case for a coupon that was deleted after it was applied
+			name:           "coupon deleted",
+			coupon:         &Coupon{},
+			expectedOutput: withReason("coupon no longer exists"),
+		},
+		{ //test case for a coupon that reached its usage limit
+			name:           "usage limit reached",
+			coupon:         &Coupon{Coupon: func() domain.Coupon { c := coupon; c.UsageLimit = 100; return c }(), Redemptions: 100},
+			expectedOutput: withReason("coupon SAVE10 has been fully redeemed"),
+		},
+		{ //test case for a user who used the coupon as many times as a user can
+			name:           "per user limit reached",
+			coupon:         &Coupon{Coupon: coupon, Redemptions: 1, UserRedemptions: 1},
+			expectedOutput: withReason("coupon SAVE10 was already used"),
+		},
+		{ //test case for a first order coupon used by a user who ordered before
+			name:           "not the first order",
+			coupon:         &Coupon{Coupon: func() domain.Coupon { c := coupon; c.FirstOrderOnly = true; return c }(), UserOrders: 1},
+			expectedOutput: withReason("coupon SAVE10 is only for the first order"),
+		},
+		{ //test case for a coupon targeted at other users
+			name:           "coupon for other users",
+			coupon:         &Coupon{Coupon: coupon, UserIDs: []uint{3, 9}},
+			expectedOutput: withReason("coupon SAVE10 is not available for this account"),
+		},
+		{ //test case for a flat coupon scoped to a category, only the line of the category gets the discount
+			name:   "flat coupon on a category",
+			coupon: &Coupon{Coupon: flat, TargetIDs: []uint{2}},
+			expectedOutput: Quote{
+				Lines: []PricedLine{
+					{Line: lines[0], LineTotal: 1000, Tax: 180},
+					{Line: lines[1], LineTotal: 1000, Discount: 300},
+				},
+				SubTotal: 2000,
+				CouponID: 5,
+				Discount: 300,
+				Tax:      180,
+				Total:    1880,
+			},
+		},
+		{ //test case for a percent coupon scoped to a brand, the percent is taken on the items of the brand only
+			name: "percent coupon on a brand",
+			coupon: &Coupon{Coupon: func() domain.Coupon {
+				c := coupon
+				c.Scope = domain.CouponScopeBrand
+				c.MinOrderValue = 0
+				return c
+			}(), TargetIDs: []uint{1}},
+			expectedOutput: Quote{
+				Lines: []PricedLine{
+					{Line: lines[0], LineTotal: 1000, Discount: 100, Tax: 162},
+					{Line: lines[1], LineTotal: 1000},
+				},
+				SubTotal: 2000,
+				CouponID: 4,
+				Discount: 100,
+				Tax:      162,
+				Total:    2062,
+			},
+		},
+		{ //test case for a flat coupon larger than the items it is given on, the discount is capped at their total
+			name: "flat coupon capped at the items",
+			coupon: &Coupon{Coupon: func() domain.Coupon {
+				c := flat
+				c.Scope = domain.CouponScopeProductItem
+				c.DiscountAmount = 1500
+				return c
+			}(), TargetIDs: []uint{1}},
+			expectedOutput: Quote{
+				Lines: []PricedLine{
+					{Line: lines[0], LineTotal: 1000, Discount: 1000},
+					{Line: lines[1], LineTotal: 1000},
+				},
+				SubTotal: 2000,
+				CouponID: 5,
+				Discount: 1000,
+				Total:    1000,
+			},
+		},
+		{ //test case for a scoped coupon with none of its targets in the cart
+			name:           "no item in scope",
+			coupon:         &Coupon{Coupon: flat, TargetIDs: []uint{8}},
+			expectedOutput: withReason("coupon FLAT300 does not apply to any of the items"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actualOutput := Price(lines, userID, tt.coupon, now)
+			assert.Equal(t, tt.expectedOutput, actualOutput)
+		})
+	}
+}
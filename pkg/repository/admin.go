@@ -71,15 +71,15 @@ func (c *adminDatabase) UnblockAdmin(ctx context.Context, unblockID int) (domain
 func (c *adminDatabase) AdminDashboard(ctx context.Context) (model.AdminDashboard, error) {
 	var dashboardData model.AdminDashboard
 	fetchOrdersSummaryQuery := `SELECT 
-								  COUNT(CASE WHEN order_status_id = 4 THEN id END) AS completed_orders,
-								  COUNT(CASE WHEN order_status_id = 1 THEN id END) AS pending_orders,
-								  COUNT(CASE WHEN order_status_id = 2 OR order_status_id = 3 THEN id END) AS cancelled_orders,
+								  COUNT(CASE WHEN order_status_id = $1 THEN id END) AS completed_orders,
+								  COUNT(CASE WHEN order_status_id = $2 THEN id END) AS pending_orders,
+								  COUNT(CASE WHEN order_status_id = $3 OR order_status_id = $4 THEN id END) AS cancelled_orders,
 								  COUNT(id) AS total_orders,
-								  SUM(CASE WHEN o.order_status_id != 2 AND o.order_status_id != 3 THEN o.order_total ELSE 0 END) AS order_value,
+								  SUM(CASE WHEN o.order_status_id != $3 AND o.order_status_id != $4 THEN o.order_total ELSE 0 END) AS order_value,
 								  COUNT(DISTINCT o.user_id) AS ordered_users
 								FROM orders o;`
 
-	err := c.DB.Raw(fetchOrdersSummaryQuery).Scan(&dashboardData).Error
+	err := c.DB.Raw(fetchOrdersSummaryQuery, domain.OrderCompleted, domain.OrderPending, domain.OrderCancelledByUser, domain.OrderCancelledByAdmin).Scan(&dashboardData).Error
 	if err != nil {
 		return dashboardData, err
 	}
@@ -90,8 +90,8 @@ func (c *adminDatabase) AdminDashboard(ctx context.Context) (model.AdminDashboar
 		return dashboardData, err
 	}
 
-	creditedAmountQuery := `SELECT sum(order_total) as credited_amount FROM payment_details WHERE payment_status_id = 2`
-	err = c.DB.Raw(creditedAmountQuery).Scan(&dashboardData.CreditedAmount).Error
+	creditedAmountQuery := `SELECT sum(order_total) as credited_amount FROM payment_details WHERE payment_status_id = $1`
+	err = c.DB.Raw(creditedAmountQuery, domain.PaymentCompleted).Scan(&dashboardData.CreditedAmount).Error
 	if err != nil {
 		//return dashboardData, err
 	}
@@ -117,9 +117,19 @@ func (c *adminDatabase) SalesReport(ctx context.Context) ([]model.SalesReport, e
 							o.order_total AS total, 	
 							c.code AS coupon_code, 
 							pm.payment_method, 
+							ps.payment_status,
+							COALESCE(cc.amount, 0) AS cod_collected,
+							COALESCE(cc.collected_by, '') AS cod_collected_by,
 							os.order_status, 
 							ds.status AS delivery_status,
-							o.order_date		
+							o.order_date,
+							o.shipping_house_number,
+							o.shipping_street,
+							o.shipping_city,
+							o.shipping_district,
+							o.shipping_pincode,
+							o.shipping_landmark,
+							o.shipping_label
 						FROM orders o 
 						LEFT JOIN
 							payment_methods pm ON o.payment_method_id = pm.id 
@@ -128,8 +138,14 @@ func (c *adminDatabase) SalesReport(ctx context.Context) ([]model.SalesReport, e
 						LEFT JOIN	
 							delivery_statuses ds ON o.delivery_status_id = ds.id
 						LEFT JOIN 
-							coupons c ON o.coupon_id = c.id;`
+							coupons c ON o.coupon_id = c.id
+						LEFT JOIN
+							payment_details pd ON pd.order_id = o.id
+						LEFT JOIN
+							payment_statuses ps ON pd.payment_status_id = ps.id
+						LEFT JOIN
+							cod_collections cc ON cc.order_id = o.id AND cc.status = $1;`
 
-	err := c.DB.Raw(salesDataQuery).Scan(&salesData).Error
+	err := c.DB.Raw(salesDataQuery, domain.CODCollected).Scan(&salesData).Error
 	return salesData, err
 }
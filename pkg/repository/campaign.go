@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	interfaces "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/interface"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/couponcode"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"gorm.io/gorm"
+)
+
+// maxCodeAttempts is how many codes are tried for a coupon of a campaign before giving up, when the ones tried are
+// already taken by other coupons
+const maxCodeAttempts = 10
+
+type campaignDatabase struct {
+	DB *gorm.DB
+}
+
+func NewCampaignRepository(DB *gorm.DB) interfaces.CampaignRepository {
+	return &campaignDatabase{DB}
+}
+
+// CreateCampaign creates the campaign with a coupon for each of its codes. A code taken by another coupon is found
+// by the unique index on the coupon codes, and another code is generated in its place.
+func (c *campaignDatabase) CreateCampaign(ctx context.Context, campaign model.CreateCouponCampaign) (domain.CouponCampaign, error) {
+	tx := c.DB.Begin()
+
+	var createdCampaign domain.CouponCampaign
+	createCampaignQuery := `	INSERT INTO coupon_campaigns (name, prefix, alphabet, code_length, code_count, created_at)
+								VALUES ($1, $2, $3, $4, $5, NOW())
+								ON CONFLICT (name) DO NOTHING RETURNING *;`
+	err := tx.Raw(createCampaignQuery, campaign.Name, campaign.Prefix, campaign.Alphabet, campaign.CodeLength, campaign.CodeCount).Scan(&createdCampaign).Error
+	if err != nil {
+		tx.Rollback()
+		return domain.CouponCampaign{}, err
+	}
+	if createdCampaign.ID == 0 {
+		tx.Rollback()
+		return domain.CouponCampaign{}, fmt.Errorf("a campaign named %v already exists", campaign.Name)
+	}
+
+	generator := couponcode.Generator{Prefix: campaign.Prefix, Alphabet: campaign.Alphabet, Length: campaign.CodeLength}
+	rules := campaign.Rules
+	createCodeQuery := `	INSERT INTO coupons(code, campaign_id, min_order_value, discount_type, discount_percent, discount_max_amount, discount_amount,
+								scope, valid_from, valid_till, usage_limit, per_user_limit, first_order_only)
+							VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+							ON CONFLICT (code) DO NOTHING RETURNING id;`
+	for i := 0; i < campaign.CodeCount; i++ {
+		var couponID uint
+		for attempt := 0; couponID == 0; attempt++ {
+			if attempt == maxCodeAttempts {
+				tx.Rollback()
+				return domain.CouponCampaign{}, fmt.Errorf("could not find unused codes for the campaign, use longer codes or a larger alphabet")
+			}
+			code, err := generator.Generate()
+			if err != nil {
+				tx.Rollback()
+				return domain.CouponCampaign{}, err
+			}
+			err = tx.Raw(createCodeQuery, code, createdCampaign.ID, rules.MinOrderValue, rules.DiscountType, rules.DiscountPercent,
+				rules.DiscountMaxAmount, rules.DiscountAmount, rules.Scope, rules.ValidFrom, rules.ValidTill,
+				rules.UsageLimit, *rules.PerUserLimit, rules.FirstOrderOnly).Scan(&couponID).Error
+			if err != nil {
+				tx.Rollback()
+				return domain.CouponCampaign{}, err
+			}
+		}
+	}
+
+	//every code of the campaign is limited to the same targets
+	for _, targetID := range rules.TargetIDs {
+		insertTargetsQuery := `	INSERT INTO coupon_targets (coupon_id, target_id)
+								SELECT id, $1 FROM coupons WHERE campaign_id = $2
+								ON CONFLICT DO NOTHING`
+		if err := tx.Exec(insertTargetsQuery, targetID, createdCampaign.ID).Error; err != nil {
+			tx.Rollback()
+			return domain.CouponCampaign{}, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return domain.CouponCampaign{}, err
+	}
+	return createdCampaign, nil
+}
+
+// ViewCampaigns lists the campaigns, latest first, with how many of their codes were redeemed by orders that were not cancelled
+func (c *campaignDatabase) ViewCampaigns(ctx context.Context) ([]model.CouponCampaignReport, error) {
+	var campaigns []model.CouponCampaignReport
+	viewCampaignsQuery := `	SELECT cc.*,
+								COUNT(r.id) AS redeemed,
+								COALESCE(SUM(r.discount), 0) AS discount_cost
+							FROM coupon_campaigns cc
+							LEFT JOIN coupons c ON c.campaign_id = cc.id
+							LEFT JOIN coupon_redemptions r ON r.coupon_id = c.id AND r.released_at IS NULL
+							GROUP BY cc.id
+							ORDER BY cc.id DESC;`
+	err := c.DB.Raw(viewCampaignsQuery).Scan(&campaigns).Error
+	return campaigns, err
+}
+
+// ViewCampaignCodes lists the codes of the campaign in the order they were generated, with the order that redeemed each
+func (c *campaignDatabase) ViewCampaignCodes(ctx context.Context, campaignID int) ([]model.CampaignCode, error) {
+	var fetchedID uint
+	findCampaignQuery := `SELECT id FROM coupon_campaigns WHERE id = $1`
+	if err := c.DB.Raw(findCampaignQuery, campaignID).Scan(&fetchedID).Error; err != nil {
+		return nil, err
+	}
+	if fetchedID == 0 {
+		return nil, fmt.Errorf("no campaign found with id %v", campaignID)
+	}
+
+	var codes []model.CampaignCode
+	viewCodesQuery := `	SELECT c.code, c.valid_till, COALESCE(r.order_id, 0) AS order_id, r.created_at AS redeemed_at
+						FROM coupons c
+						LEFT JOIN coupon_redemptions r ON r.coupon_id = c.id AND r.released_at IS NULL
+						WHERE c.campaign_id = $1
+						ORDER BY c.id;`
+	err := c.DB.Raw(viewCodesQuery, campaignID).Scan(&codes).Error
+	return codes, err
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/pricing"
 	interfaces "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/interface"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
 	"gorm.io/gorm"
+	"sort"
+	"time"
 )
 
 type cartDatabase struct {
@@ -52,9 +55,21 @@ func (c *cartDatabase) AddToCart(ctx context.Context, userID int, productItemID
 		tx.Rollback()
 		return domain.CartItems{}, err
 	}
+
+	//one more of the item should be in stock and within the purchase limit
+	productItem, err := findCartProductItem(tx, productItemID)
+	if err != nil {
+		tx.Rollback()
+		return domain.CartItems{}, err
+	}
+	if err := checkCartQuantity(productItem, int(cartItem.Quantity)+1); err != nil {
+		tx.Rollback()
+		return domain.CartItems{}, err
+	}
+
 	//if item is not present in the cart
 	if cartItem.ID == 0 {
-		err := tx.Raw("INSERT INTO cart_items (cart_id, product_item_id, quantity) VALUES ($1, $2, 1) RETURNING *;", cartID, productItemID).Scan(&cartItem).Error
+		err := tx.Raw("INSERT INTO cart_items (cart_id, product_item_id, quantity, price_when_added) VALUES ($1, $2, 1, $3) RETURNING *;", cartID, productItemID, productItem.Price).Scan(&cartItem).Error
 
 		if err != nil {
 			tx.Rollback()
@@ -70,62 +85,12 @@ func (c *cartDatabase) AddToCart(ctx context.Context, userID int, productItemID
 		}
 	}
 
-	//update subtotal in cart table
-	//product_item_id is known, quantity is known, cart_id is known
-	//fetch price from product_items table
-	var currentSubTotal, total, itemPrice float64
-	err = tx.Raw("SELECT price FROM product_items WHERE id = $1", productItemID).Scan(&itemPrice).Error
-
-	if err != nil {
-		tx.Rollback()
-		return domain.CartItems{}, err
-	}
-	//fetch current subtotal from cart table
-	err = tx.Raw("SELECT sub_total FROM carts WHERE id = $1", cartItem.CartID).Scan(&currentSubTotal).Error
-	err = tx.Raw("SELECT total FROM carts WHERE id = $1", cartItem.CartID).Scan(&total).Error
-	if err != nil {
-		tx.Rollback()
-		return domain.CartItems{}, err
-	}
-	//add price of new product item to the current subtotal and update it in the cart table
-	newSubTotal := currentSubTotal + itemPrice
-	newTotal := total + itemPrice
-
-	err = tx.Exec("UPDATE carts SET sub_total = $1, total = $2 WHERE user_id = $3", newSubTotal, newTotal, userID).Error
-	if err != nil {
+	//the cart is priced again with the new quantity
+	if _, err := priceCart(tx, cartID); err != nil {
 		tx.Rollback()
 		return domain.CartItems{}, err
 	}
 
-	//check if the cart has a coupon
-	var couponID int
-	err = tx.Raw("SELECT COALESCE(coupon_id, 0) FROM carts WHERE user_id = $1", userID).Scan(&couponID).Error
-	if err != nil {
-		tx.Rollback()
-		return domain.CartItems{}, err
-	}
-	//if cart has a valid coupon
-	if couponID != 0 {
-		//	fetch coupon details
-		var couponInfo domain.Coupon
-		if err := tx.Raw("SELECT * FROM coupons WHERE id = $1;", couponID).Scan(&couponInfo).Error; err != nil {
-			tx.Rollback()
-			return domain.CartItems{}, err
-		}
-		discount := newSubTotal * (couponInfo.DiscountPercent / 100)
-		if discount > couponInfo.DiscountMaxAmount {
-			discount = couponInfo.DiscountMaxAmount
-		}
-
-		updatedTotal := newTotal - discount
-
-		//update cart table
-		if err := tx.Exec("UPDATE carts SET discount = $1, total = $2 WHERE user_id = $3", discount, updatedTotal, userID).Error; err != nil {
-			tx.Rollback()
-			return domain.CartItems{}, err
-		}
-
-	}
 	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
 		tx.Rollback()
@@ -170,65 +135,8 @@ func (c *cartDatabase) RemoveFromCart(ctx context.Context, userID int, productIt
 		}
 	}
 
-	//fetch price from product_items table
-	var itemPrice float64
-	//var currentTotal float64
-	err = tx.Raw("SELECT price FROM product_items WHERE id = $1", productItemID).Scan(&itemPrice).Error
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
-
-	var newSubTotal float64
-	result := tx.Raw("UPDATE carts SET sub_total = sub_total - $1, total = total-$2 WHERE id = $3 RETURNING sub_total;", itemPrice, itemPrice, cartID).Scan(&newSubTotal)
-
-	//check if the cart has a coupon
-	var couponID int
-	err = tx.Raw("SELECT COALESCE(coupon_id, 0) FROM carts WHERE user_id = $1", userID).Scan(&couponID).Error
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
-
-	//if cart has a valid coupon
-	if couponID != 0 {
-		//	fetch coupon details
-		var couponInfo domain.Coupon
-		if err := tx.Raw("SELECT * FROM coupons WHERE id = $1;", couponID).Scan(&couponInfo).Error; err != nil {
-			tx.Rollback()
-			return err
-		}
-
-		if newSubTotal < couponInfo.MinOrderValue {
-			var updatedCart domain.Cart
-			err := tx.Raw("UPDATE carts SET coupon_id = 0, discount = 0, total = $1 WHERE id = $2 RETURNING *;", newSubTotal, cartID).Scan(&updatedCart).Error
-			if err != nil {
-				tx.Rollback()
-				return err
-			}
-			if err := tx.Commit().Error; err != nil {
-				tx.Rollback()
-				return err
-			}
-			return nil
-		}
-
-		//calculating new discount amount
-		discount := newSubTotal * (couponInfo.DiscountPercent / 100)
-		if discount > couponInfo.DiscountMaxAmount {
-			discount = couponInfo.DiscountMaxAmount
-		}
-
-		updatedTotal := newSubTotal - discount
-
-		//update cart table
-		if err := tx.Exec("UPDATE carts SET discount = $1, total = $2 WHERE user_id = $3", discount, updatedTotal, userID).Error; err != nil {
-			tx.Rollback()
-			return err
-		}
-	}
-
-	if result.Error != nil {
+	//the cart is priced again with the new quantity
+	if _, err := priceCart(tx, cartID); err != nil {
 		tx.Rollback()
 		return err
 	}
@@ -244,23 +152,33 @@ func (c *cartDatabase) ViewCart(ctx context.Context, userID int) (model.ViewCart
 
 	tx := c.DB.Begin()
 	//find cart_id from carts table
-	var cartDetails struct {
-		ID       int
-		CouponID int
-		SubTotal float64
-		Discount float64
-		Total    float64
+	var cartID int
+	err := tx.Raw("SELECT id FROM carts WHERE user_id = $1", userID).Scan(&cartID).Error
+	if err != nil {
+		tx.Rollback()
+		return model.ViewCart{}, err
+	}
+	if cartID == 0 {
+		tx.Rollback()
+		return model.ViewCart{}, nil
 	}
 
-	err := tx.Raw("SELECT id,coupon_id, sub_total,discount, total FROM carts WHERE user_id = $1", userID).Scan(&cartDetails).Error
-
+	//the cart is priced from the current prices of its items every time it is viewed
+	quote, err := priceCart(tx, cartID)
+	if err != nil {
+		tx.Rollback()
+		return model.ViewCart{}, err
+	}
+	var couponRemoved string
+	err = tx.Raw("SELECT COALESCE(coupon_removed, '') FROM carts WHERE id = $1", cartID).Scan(&couponRemoved).Error
 	if err != nil {
 		tx.Rollback()
 		return model.ViewCart{}, err
 	}
 
 	var allItems []model.DisplayCart
-	joinQuery := `	SELECT pi.id as product_item_id, b.brand, p.name, pi.model, ci.quantity, pi.product_item_image, pi.price, (ci.quantity * pi.price) AS total
+	joinQuery := `	SELECT pi.id as product_item_id, b.brand, p.name, pi.model, ci.quantity, pi.product_item_image, pi.price, (ci.quantity * pi.price) AS total,
+						COALESCE(ci.price_when_added, pi.price), pi.qnty_in_stock, pi.max_per_order
 					FROM cart_items ci 
 					JOIN product_items pi
 					ON ci.product_item_id = pi.id
@@ -271,7 +189,7 @@ func (c *cartDatabase) ViewCart(ctx context.Context, userID int) (model.ViewCart
 					WHERE ci.cart_id = $1
 					`
 
-	rows, err := tx.Raw(joinQuery, cartDetails.ID).Rows()
+	rows, err := tx.Raw(joinQuery, cartID).Rows()
 	if err != nil {
 		tx.Rollback()
 		return model.ViewCart{}, err
@@ -280,11 +198,13 @@ func (c *cartDatabase) ViewCart(ctx context.Context, userID int) (model.ViewCart
 
 	for rows.Next() {
 		var item model.DisplayCart
-		err := rows.Scan(&item.ProductItemID, &item.Brand, &item.Name, &item.Model, &item.Quantity, &item.ProductItemImage, &item.Price, &item.Total)
+		err := rows.Scan(&item.ProductItemID, &item.Brand, &item.Name, &item.Model, &item.Quantity, &item.ProductItemImage, &item.Price, &item.Total,
+			&item.PriceWhenAdded, &item.QntyInStock, &item.MaxPerOrder)
 		if err != nil {
 			tx.Rollback()
 			return model.ViewCart{}, err
 		}
+		item.Issue = cartItemIssue(item.Price, item.PriceWhenAdded, item.QntyInStock, item.MaxPerOrder, item.Quantity)
 		allItems = append(allItems, item)
 	}
 
@@ -294,21 +214,243 @@ func (c *cartDatabase) ViewCart(ctx context.Context, userID int) (model.ViewCart
 	}
 	var finalCart model.ViewCart
 
-	finalCart.CouponID = cartDetails.CouponID
-	finalCart.SubTotal = cartDetails.SubTotal
-	finalCart.Discount = cartDetails.Discount
-	finalCart.CartTotal = finalCart.SubTotal - finalCart.Discount
+	finalCart.CouponID = int(quote.CouponID)
+	finalCart.CouponRemoved = couponRemoved
+	finalCart.SubTotal = quote.SubTotal
+	finalCart.Discount = quote.Discount
+	finalCart.Tax = quote.Tax
+	finalCart.CartTotal = quote.Total
 	finalCart.CartItems = allItems
-	fmt.Println(finalCart)
+	//the cart can be checked out only when none of its items have to be fixed first
+	finalCart.CanCheckout = len(allItems) > 0
+	for _, item := range allItems {
+		if item.Issue != "" {
+			finalCart.CanCheckout = false
+		}
+	}
 	return finalCart, nil
 }
 
+func (c *cartDatabase) UpdateCartItem(ctx context.Context, userID, productItemID, quantity int) (model.ViewCart, error) {
+	tx := c.DB.Begin()
+
+	productItem, err := findCartProductItem(tx, productItemID)
+	if err != nil {
+		tx.Rollback()
+		return model.ViewCart{}, err
+	}
+	if quantity > 0 {
+		if err := checkCartQuantity(productItem, quantity); err != nil {
+			tx.Rollback()
+			return model.ViewCart{}, err
+		}
+	}
+
+	var cartID int
+	err = tx.Raw("SELECT id FROM carts WHERE user_id = $1", userID).Scan(&cartID).Error
+	if err != nil {
+		tx.Rollback()
+		return model.ViewCart{}, err
+	}
+	if cartID == 0 {
+		if quantity == 0 {
+			tx.Rollback()
+			return model.ViewCart{}, fmt.Errorf("nothing to remove")
+		}
+		err := tx.Raw("INSERT INTO carts (user_id, sub_total, total) VALUES ($1,0,0) RETURNING id", userID).Scan(&cartID).Error
+		if err != nil {
+			tx.Rollback()
+			return model.ViewCart{}, err
+		}
+	}
+
+	if quantity == 0 {
+		err = tx.Exec("DELETE FROM cart_items WHERE cart_id = $1 AND product_item_id = $2", cartID, productItemID).Error
+		if err != nil {
+			tx.Rollback()
+			return model.ViewCart{}, err
+		}
+	} else {
+		//setting the quantity accepts the current price of the item
+		var cartItemID int
+		updateCartItemQuery := `UPDATE cart_items SET quantity = $1, price_when_added = $2 WHERE cart_id = $3 AND product_item_id = $4 RETURNING id`
+		err = tx.Raw(updateCartItemQuery, quantity, productItem.Price, cartID, productItemID).Scan(&cartItemID).Error
+		if err != nil {
+			tx.Rollback()
+			return model.ViewCart{}, err
+		}
+		if cartItemID == 0 {
+			insertCartItemQuery := `INSERT INTO cart_items (cart_id, product_item_id, quantity, price_when_added) VALUES ($1, $2, $3, $4)`
+			err = tx.Exec(insertCartItemQuery, cartID, productItemID, quantity, productItem.Price).Error
+			if err != nil {
+				tx.Rollback()
+				return model.ViewCart{}, err
+			}
+		}
+	}
+
+	if _, err := priceCart(tx, cartID); err != nil {
+		tx.Rollback()
+		return model.ViewCart{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return model.ViewCart{}, err
+	}
+	return c.ViewCart(ctx, userID)
+}
+
+// findCartProductItem fetches the price, stock and purchase limit of a product item being put in the cart
+func findCartProductItem(tx *gorm.DB, productItemID int) (domain.ProductItem, error) {
+	var productItem domain.ProductItem
+	err := tx.Raw("SELECT id, price, qnty_in_stock, max_per_order FROM product_items WHERE id = $1", productItemID).Scan(&productItem).Error
+	if err != nil {
+		return domain.ProductItem{}, err
+	}
+	if productItem.ID == 0 {
+		return domain.ProductItem{}, fmt.Errorf("no product item found with id %v", productItemID)
+	}
+	return productItem, nil
+}
+
+// checkCartQuantity checks that the quantity of a product item in the cart is in stock and within its purchase limit
+func checkCartQuantity(productItem domain.ProductItem, quantity int) error {
+	if productItem.MaxPerOrder > 0 && quantity > productItem.MaxPerOrder {
+		return fmt.Errorf("only %v of this product item can be bought per order", productItem.MaxPerOrder)
+	}
+	if quantity > productItem.QntyInStock {
+		return &domain.OutOfStockError{ProductItemID: productItem.ID, Requested: quantity}
+	}
+	return nil
+}
+
+// cartItemIssue returns why a cart item cannot be checked out as it is, or an empty string if it can
+func cartItemIssue(price, priceWhenAdded float64, qntyInStock, maxPerOrder int, quantity uint) string {
+	switch {
+	case qntyInStock <= 0:
+		return "out of stock"
+	case int(quantity) > qntyInStock:
+		return fmt.Sprintf("only %v left in stock", qntyInStock)
+	case maxPerOrder > 0 && int(quantity) > maxPerOrder:
+		return fmt.Sprintf("only %v can be bought per order", maxPerOrder)
+	case price != priceWhenAdded:
+		return fmt.Sprintf("price changed from %v to %v", priceWhenAdded, price)
+	}
+	return ""
+}
+
+// priceCart prices the cart from the current prices of its items and the current rules of its coupon for the user of
+// the cart, and stores the totals on the cart. A coupon that no longer applies is removed from the cart and the quote tells why.
+func priceCart(tx *gorm.DB, cartID int) (pricing.Quote, error) {
+	lines, err := fetchCartLines(tx, cartID)
+	if err != nil {
+		return pricing.Quote{}, err
+	}
+
+	var cart struct {
+		UserID   uint
+		CouponID int
+	}
+	err = tx.Raw("SELECT user_id, COALESCE(coupon_id, 0) AS coupon_id FROM carts WHERE id = $1", cartID).Scan(&cart).Error
+	if err != nil {
+		return pricing.Quote{}, err
+	}
+	var coupon *pricing.Coupon
+	if cart.CouponID != 0 {
+		coupon, err = findPricingCoupon(tx, cart.CouponID, cart.UserID)
+		if err != nil {
+			return pricing.Quote{}, err
+		}
+	}
+
+	quote := pricing.Price(lines, cart.UserID, coupon, time.Now())
+
+	updateCartQuery := `UPDATE carts SET coupon_id = $1, sub_total = $2, discount = $3, tax = $4, total = $5 WHERE id = $6`
+	err = tx.Exec(updateCartQuery, quote.CouponID, quote.SubTotal, quote.Discount, quote.Tax, quote.Total, cartID).Error
+	if err != nil {
+		return pricing.Quote{}, err
+	}
+	//the reason is kept on the cart, so that the user is told even when the coupon was removed by a change to the cart
+	if quote.CouponRemoved != "" {
+		err = tx.Exec("UPDATE carts SET coupon_removed = $1 WHERE id = $2", quote.CouponRemoved, cartID).Error
+	}
+	return quote, err
+}
+
+// fetchCartLines fetches the items of the cart at the current prices of their product items and the tax of their categories
+func fetchCartLines(tx *gorm.DB, cartID int) ([]pricing.Line, error) {
+	var lines []pricing.Line
+	fetchLinesQuery := `	SELECT ci.product_item_id, p.product_category_id AS category_id, p.brand_id, ci.quantity, pi.price AS unit_price,
+								COALESCE(pc.tax_percent, 0) AS tax_percent
+							FROM cart_items ci
+							JOIN product_items pi ON pi.id = ci.product_item_id
+							JOIN products p ON p.id = pi.product_id
+							LEFT JOIN product_categories pc ON pc.id = p.product_category_id
+							WHERE ci.cart_id = $1
+							ORDER BY ci.product_item_id`
+	err := tx.Raw(fetchLinesQuery, cartID).Scan(&lines).Error
+	return lines, err
+}
+
+// ApplicableCoupons prices the cart with each coupon the user can still use and lists the ones that give a discount,
+// the largest discount first. Expired coupons, coupons not valid yet, coupons the user used up and the single use
+// codes of campaigns are not tried.
+func (c *cartDatabase) ApplicableCoupons(ctx context.Context, userID int) ([]model.ApplicableCoupon, error) {
+	var cartID int
+	if err := c.DB.Raw("SELECT id FROM carts WHERE user_id = $1", userID).Scan(&cartID).Error; err != nil {
+		return nil, err
+	}
+	if cartID == 0 {
+		return []model.ApplicableCoupon{}, nil
+	}
+	lines, err := fetchCartLines(c.DB, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	var couponIDs []int
+	findCouponsQuery := `	SELECT c.id FROM coupons c
+							WHERE c.campaign_id IS NULL AND c.valid_from <= NOW() AND c.valid_till > NOW()
+								AND (c.per_user_limit = 0 OR c.per_user_limit > (
+									SELECT COUNT(*) FROM coupon_redemptions r
+									WHERE r.coupon_id = c.id AND r.user_id = $1 AND r.released_at IS NULL
+								))
+							ORDER BY c.id`
+	if err := c.DB.Raw(findCouponsQuery, userID).Scan(&couponIDs).Error; err != nil {
+		return nil, err
+	}
+
+	applicable := []model.ApplicableCoupon{}
+	now := time.Now()
+	for _, couponID := range couponIDs {
+		coupon, err := findPricingCoupon(c.DB, couponID, uint(userID))
+		if err != nil {
+			return nil, err
+		}
+		quote := pricing.Price(lines, uint(userID), coupon, now)
+		if quote.CouponRemoved != "" || quote.Discount == 0 {
+			continue
+		}
+		applicable = append(applicable, model.ApplicableCoupon{
+			CouponID:  coupon.ID,
+			Code:      coupon.Code,
+			Discount:  quote.Discount,
+			CartTotal: quote.Total,
+		})
+	}
+	sort.SliceStable(applicable, func(i, j int) bool {
+		return applicable[i].Discount > applicable[j].Discount
+	})
+	return applicable, nil
+}
+
 func (c *cartDatabase) EmptyCart(ctx context.Context, userID int) error {
 	tx := c.DB.Begin()
 
 	//set cart total as 0 and return cart_id
 	var cartID int
-	updateCartQuery := `UPDATE carts SET coupon_id = 0, sub_total = 0, discount = 0, total = 0 WHERE user_id = $1 RETURNING id;`
+	updateCartQuery := `UPDATE carts SET coupon_id = 0, coupon_removed = '', sub_total = 0, discount = 0, tax = 0, total = 0 WHERE user_id = $1 RETURNING id;`
 
 	err := tx.Raw(updateCartQuery, userID).Scan(&cartID).Error
 	if err != nil {
@@ -330,49 +472,39 @@ func (c *cartDatabase) EmptyCart(ctx context.Context, userID int) error {
 }
 
 func (c *cartDatabase) AddCouponToCart(ctx context.Context, userID, couponID int) (model.ViewCart, error) {
-	//fetch coupon details
-	var couponInfo domain.Coupon
-	fetchCouponQuery := `	SELECT * FROM coupons WHERE id = $1;`
-	err := c.DB.Raw(fetchCouponQuery, couponID).Scan(&couponInfo).Error
-	if err != nil {
-		return model.ViewCart{}, err
-	}
-	if couponInfo.ID == 0 {
-		return model.ViewCart{}, fmt.Errorf("no coupon found")
-	}
-
-	//	fetch cart details
-	var cartInfo domain.Cart
-	fetchCartInfo := `	SELECT * FROM carts WHERE user_id = $1;`
+	tx := c.DB.Begin()
 
-	err = c.DB.Raw(fetchCartInfo, userID).Scan(&cartInfo).Error
+	var cartID int
+	err := tx.Raw("SELECT id FROM carts WHERE user_id = $1", userID).Scan(&cartID).Error
 	if err != nil {
+		tx.Rollback()
 		return model.ViewCart{}, err
 	}
-	if cartInfo.ID == 0 {
+	if cartID == 0 {
+		tx.Rollback()
 		return model.ViewCart{}, fmt.Errorf("cannot add coupon to empty cart")
 	}
-	//	check if cart subtotal is enough for coupon
-	if cartInfo.SubTotal < couponInfo.MinOrderValue {
-		return model.ViewCart{}, fmt.Errorf("cannot apply this coupon as cart total doesn't meet minimum required")
-	}
 
-	//	calculate discount amount
-	discount := cartInfo.SubTotal * (couponInfo.DiscountPercent / 100)
-	if discount > couponInfo.DiscountMaxAmount {
-		discount = couponInfo.DiscountMaxAmount
+	err = tx.Exec("UPDATE carts SET coupon_id = $1, coupon_removed = '' WHERE id = $2", couponID, cartID).Error
+	if err != nil {
+		tx.Rollback()
+		return model.ViewCart{}, err
 	}
 
-	total := cartInfo.SubTotal - discount
-
-	//	update cart
-	updateCartQuery := `UPDATE carts SET coupon_id  = $1, discount = $2, total = $3 WHERE user_id = $4`
-	err = c.DB.Exec(updateCartQuery, couponID, discount, total, userID).Error
+	//the coupon is kept only if it applies to the cart as it is priced now
+	quote, err := priceCart(tx, cartID)
 	if err != nil {
+		tx.Rollback()
 		return model.ViewCart{}, err
 	}
+	if quote.CouponRemoved != "" {
+		tx.Rollback()
+		return model.ViewCart{}, fmt.Errorf("cannot apply this coupon: %v", quote.CouponRemoved)
+	}
 
-	cart, err := c.ViewCart(ctx, userID)
-
-	return cart, err
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return model.ViewCart{}, err
+	}
+	return c.ViewCart(ctx, userID)
 }
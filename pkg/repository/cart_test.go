@@ -0,0 +1,297 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func TestUpdateCartItem(t *testing.T) {
+	productItemColumns := []string{"id", "price", "qnty_in_stock", "max_per_order"}
+	lineColumns := []string{"product_item_id", "category_id", "brand_id", "quantity", "unit_price", "tax_percent"}
+	couponColumns := []string{"id", "code", "min_order_value", "discount_type", "discount_percent", "discount_max_amount", "scope", "valid_from", "valid_till", "per_user_limit"}
+	cartItemColumns := []string{"product_item_id", "brand", "name", "model", "quantity", "product_item_image", "price", "total", "price_when_added", "qnty_in_stock", "max_per_order"}
+
+	tests := []struct {
+		name           string
+		quantity       int
+		expectedOutput model.ViewCart
+		buildStub      func(mock sqlmock.Sqlmock)
+		expectedErr    error
+	}{
+		{ //test case for setting the quantity of an item in the cart, the current price of the item is accepted
+			name:     "set quantity",
+			quantity: 2,
+			expectedOutput: model.ViewCart{
+				CartItems: []model.DisplayCart{{ProductItemID: 5, Brand: "dell", Name: "inspiron", Model: "3511", Quantity: 2, Price: 1000,
+					Total: 2000, PriceWhenAdded: 1000, QntyInStock: 10, MaxPerOrder: 3}},
+				SubTotal:    2000,
+				Tax:         360,
+				CartTotal:   2360,
+				CanCheckout: true,
+			},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^SELECT id, price, qnty_in_stock, max_per_order FROM product_items WHERE id = \\$1$").
+					WithArgs(5).
+					WillReturnRows(sqlmock.NewRows(productItemColumns).AddRow(5, 1000, 10, 3))
+				mock.ExpectQuery("^SELECT id FROM carts WHERE user_id = \\$1$").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectQuery("^UPDATE cart_items SET quantity = \\$1, price_when_added = \\$2 (.+)$").
+					WithArgs(2, 1000.0, 2, 5).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+				//priced with the tax of the category of the product
+				mock.ExpectQuery("^SELECT ci.product_item_id, p.product_category_id AS category_id, (.+)$").
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows(lineColumns).AddRow(5, 1, 1, 2, 1000, 18))
+				mock.ExpectQuery("^SELECT user_id, COALESCE\\(coupon_id, 0\\) AS coupon_id FROM carts WHERE id = \\$1$").
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id", "coupon_id"}).AddRow(1, 0))
+				mock.ExpectExec("^UPDATE carts SET coupon_id = \\$1, sub_total = \\$2, discount = \\$3, tax = \\$4, total = \\$5 WHERE id = \\$6$").
+					WithArgs(0, 2000.0, 0.0, 360.0, 2360.0, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				//the cart is priced again when it is viewed
+				mock.ExpectBegin()
+				mock.ExpectQuery("^SELECT id FROM carts WHERE user_id = \\$1$").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectQuery("^SELECT ci.product_item_id, p.product_category_id AS category_id, (.+)$").
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows(lineColumns).AddRow(5, 1, 1, 2, 1000, 18))
+				mock.ExpectQuery("^SELECT user_id, COALESCE\\(coupon_id, 0\\) AS coupon_id FROM carts WHERE id = \\$1$").
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id", "coupon_id"}).AddRow(1, 0))
+				mock.ExpectExec("^UPDATE carts SET coupon_id = \\$1, sub_total = \\$2, discount = \\$3, tax = \\$4, total = \\$5 WHERE id = \\$6$").
+					WithArgs(0, 2000.0, 0.0, 360.0, 2360.0, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("^SELECT COALESCE\\(coupon_removed, ''\\) FROM carts WHERE id = \\$1$").
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"coupon_removed"}).AddRow(""))
+				mock.ExpectQuery("SELECT pi.id as product_item_id").
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows(cartItemColumns).AddRow(5, "dell", "inspiron", "3511", 2, "", 1000, 2000, 1000, 10, 3))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{ //test case for removing an item by setting its quantity to 0, the coupon no longer applies to the cart
+			name:           "remove item",
+			quantity:       0,
+			expectedOutput: model.ViewCart{CouponRemoved: "coupon SAVE10 needs a minimum order value of 500"},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^SELECT id, price, qnty_in_stock, max_per_order FROM product_items WHERE id = \\$1$").
+					WithArgs(5).
+					WillReturnRows(sqlmock.NewRows(productItemColumns).AddRow(5, 1000, 0, 0))
+				mock.ExpectQuery("^SELECT id FROM carts WHERE user_id = \\$1$").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectExec("^DELETE FROM cart_items WHERE cart_id = \\$1 AND product_item_id = \\$2$").
+					WithArgs(2, 5).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("^SELECT ci.product_item_id, p.product_category_id AS category_id, (.+)$").
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows(lineColumns))
+				mock.ExpectQuery("^SELECT user_id, COALESCE\\(coupon_id, 0\\) AS coupon_id FROM carts WHERE id = \\$1$").
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id", "coupon_id"}).AddRow(1, 4))
+				mock.ExpectQuery("^SELECT \\* FROM coupons WHERE id = \\$1;$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows(couponColumns).
+						AddRow(4, "SAVE10", 500, "percent", 10, 100, "cart", time.Now().Add(-24*time.Hour), time.Now().Add(24*time.Hour), 1))
+				mock.ExpectQuery("^SELECT target_id FROM coupon_targets WHERE coupon_id = \\$1$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"target_id"}))
+				mock.ExpectQuery("^SELECT user_id FROM coupon_users WHERE coupon_id = \\$1$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+				mock.ExpectQuery("^SELECT \\(SELECT COUNT\\(\\*\\) FROM coupon_redemptions WHERE coupon_id = \\$1 AND released_at IS NULL\\) AS redemptions, (.+)$").
+					WithArgs(4, 1, domain.OrderCancelledByUser, domain.OrderCancelledByAdmin).
+					WillReturnRows(sqlmock.NewRows([]string{"redemptions", "user_redemptions", "user_orders"}).AddRow(0, 0, 0))
+				mock.ExpectExec("^UPDATE carts SET coupon_id = \\$1, sub_total = \\$2, discount = \\$3, tax = \\$4, total = \\$5 WHERE id = \\$6$").
+					WithArgs(0, 0.0, 0.0, 0.0, 0.0, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("^UPDATE carts SET coupon_removed = \\$1 WHERE id = \\$2$").
+					WithArgs("coupon SAVE10 needs a minimum order value of 500", 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+
+				//the cart is priced again when it is viewed, the coupon is already removed
+				mock.ExpectBegin()
+				mock.ExpectQuery("^SELECT id FROM carts WHERE user_id = \\$1$").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectQuery("^SELECT ci.product_item_id, p.product_category_id AS category_id, (.+)$").
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows(lineColumns))
+				mock.ExpectQuery("^SELECT user_id, COALESCE\\(coupon_id, 0\\) AS coupon_id FROM carts WHERE id = \\$1$").
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id", "coupon_id"}).AddRow(1, 0))
+				mock.ExpectExec("^UPDATE carts SET coupon_id = \\$1, sub_total = \\$2, discount = \\$3, tax = \\$4, total = \\$5 WHERE id = \\$6$").
+					WithArgs(0, 0.0, 0.0, 0.0, 0.0, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("^SELECT COALESCE\\(coupon_removed, ''\\) FROM carts WHERE id = \\$1$").
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"coupon_removed"}).AddRow("coupon SAVE10 needs a minimum order value of 500"))
+				mock.ExpectQuery("SELECT pi.id as product_item_id").
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows(cartItemColumns))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{ //test case for a quantity over the purchase limit of the item
+			name:           "over purchase limit",
+			quantity:       4,
+			expectedOutput: model.ViewCart{},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^SELECT id, price, qnty_in_stock, max_per_order FROM product_items WHERE id = \\$1$").
+					WithArgs(5).
+					WillReturnRows(sqlmock.NewRows(productItemColumns).AddRow(5, 1000, 10, 3))
+				mock.ExpectRollback()
+			},
+			expectedErr: errors.New("only 3 of this product item can be bought per order"),
+		},
+		{ //test case for a quantity more than the stock of the item
+			name:           "more than stock",
+			quantity:       2,
+			expectedOutput: model.ViewCart{},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^SELECT id, price, qnty_in_stock, max_per_order FROM product_items WHERE id = \\$1$").
+					WithArgs(5).
+					WillReturnRows(sqlmock.NewRows(productItemColumns).AddRow(5, 1000, 1, 0))
+				mock.ExpectRollback()
+			},
+			expectedErr: &domain.OutOfStockError{ProductItemID: 5, Requested: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when initializing a mock db session", err)
+			}
+
+			cartRepository := NewCartRepository(gormDB)
+			tt.buildStub(mock)
+
+			actualOutput, actualErr := cartRepository.UpdateCartItem(context.TODO(), 1, 5, tt.quantity)
+			assert.Equal(t, tt.expectedErr, actualErr)
+			assert.Equal(t, tt.expectedOutput, actualOutput)
+
+			err = mock.ExpectationsWereMet()
+			if err != nil {
+				t.Errorf("Unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestApplicableCoupons(t *testing.T) {
+	lineColumns := []string{"product_item_id", "category_id", "brand_id", "quantity", "unit_price", "tax_percent"}
+	couponColumns := []string{"id", "code", "min_order_value", "discount_type", "discount_percent", "discount_max_amount", "scope", "valid_from", "valid_till", "per_user_limit"}
+
+	expectCoupon := func(mock sqlmock.Sqlmock, id int, code string, minOrderValue, percent, maxAmount float64) {
+		mock.ExpectQuery("^SELECT \\* FROM coupons WHERE id = \\$1;$").
+			WithArgs(id).
+			WillReturnRows(sqlmock.NewRows(couponColumns).
+				AddRow(id, code, minOrderValue, "percent", percent, maxAmount, "cart", time.Now().Add(-24*time.Hour), time.Now().Add(24*time.Hour), 1))
+		mock.ExpectQuery("^SELECT target_id FROM coupon_targets WHERE coupon_id = \\$1$").
+			WithArgs(id).
+			WillReturnRows(sqlmock.NewRows([]string{"target_id"}))
+		mock.ExpectQuery("^SELECT user_id FROM coupon_users WHERE coupon_id = \\$1$").
+			WithArgs(id).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+		mock.ExpectQuery("^SELECT \\(SELECT COUNT\\(\\*\\) FROM coupon_redemptions WHERE coupon_id = \\$1 AND released_at IS NULL\\) AS redemptions, (.+)$").
+			WithArgs(id, 1, domain.OrderCancelledByUser, domain.OrderCancelledByAdmin).
+			WillReturnRows(sqlmock.NewRows([]string{"redemptions", "user_redemptions", "user_orders"}).AddRow(0, 0, 0))
+	}
+
+	tests := []struct {
+		name           string
+		expectedOutput []model.ApplicableCoupon
+		buildStub      func(mock sqlmock.Sqlmock)
+		expectedErr    error
+	}{
+		{ //test case for a cart that some of the coupons apply to, the largest discount comes first
+			name: "ranked by discount",
+			expectedOutput: []model.ApplicableCoupon{
+				{CouponID: 6, Code: "SAVE20", Discount: 400, CartTotal: 1888},
+				{CouponID: 4, Code: "SAVE10", Discount: 100, CartTotal: 2242},
+			},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT id FROM carts WHERE user_id = \\$1$").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectQuery("^SELECT ci.product_item_id, p.product_category_id AS category_id, (.+)$").
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows(lineColumns).AddRow(5, 1, 1, 1, 2000, 18))
+				//expired coupons and coupons the user used up are left out by the query
+				mock.ExpectQuery("^SELECT c.id FROM coupons c WHERE c.campaign_id IS NULL (.+)$").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4).AddRow(6).AddRow(7))
+				expectCoupon(mock, 4, "SAVE10", 500, 10, 100)
+				expectCoupon(mock, 6, "SAVE20", 0, 20, 1000)
+				//the cart is below the minimum order value of this coupon
+				expectCoupon(mock, 7, "BIG30", 5000, 30, 3000)
+			},
+			expectedErr: nil,
+		},
+		{ //test case for a user without a cart
+			name:           "no cart",
+			expectedOutput: []model.ApplicableCoupon{},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT id FROM carts WHERE user_id = \\$1$").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			expectedErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when initializing a mock db session", err)
+			}
+
+			cartRepository := NewCartRepository(gormDB)
+			tt.buildStub(mock)
+
+			actualOutput, actualErr := cartRepository.ApplicableCoupons(context.TODO(), 1)
+			assert.Equal(t, tt.expectedErr, actualErr)
+			assert.Equal(t, tt.expectedOutput, actualOutput)
+
+			err = mock.ExpectationsWereMet()
+			if err != nil {
+				t.Errorf("Unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
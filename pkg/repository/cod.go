@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	interfaces "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/interface"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"gorm.io/gorm"
+)
+
+type codDatabase struct {
+	DB *gorm.DB
+}
+
+func NewCODRepository(DB *gorm.DB) interfaces.CODRepository {
+	return &codDatabase{DB}
+}
+
+func (c *codDatabase) ViewCODRules(ctx context.Context) (model.CODRules, error) {
+	var rules model.CODRules
+	fetchRulesQuery := `SELECT * FROM cod_rules WHERE id = 1;`
+	if err := c.DB.Raw(fetchRulesQuery).Scan(&rules.CODRules).Error; err != nil {
+		return model.CODRules{}, err
+	}
+
+	fetchPincodesQuery := `SELECT pincode FROM cod_pincodes ORDER BY pincode;`
+	err := c.DB.Raw(fetchPincodesQuery).Scan(&rules.Pincodes).Error
+	return rules, err
+}
+
+// UpdateCODRules sets the limits for cash on delivery, creating the row of rules the first time
+func (c *codDatabase) UpdateCODRules(ctx context.Context, rules model.UpdateCODRules) (domain.CODRules, error) {
+	var updatedRules domain.CODRules
+	updateRulesQuery := `	INSERT INTO cod_rules (id, max_order_value, max_refused_deliveries, updated_at) VALUES (1, $1, $2, NOW())
+							ON CONFLICT (id) DO UPDATE SET max_order_value = $1, max_refused_deliveries = $2, updated_at = NOW()
+							RETURNING *;`
+	err := c.DB.Raw(updateRulesQuery, rules.MaxOrderValue, rules.MaxRefusedDeliveries).Scan(&updatedRules).Error
+	return updatedRules, err
+}
+
+func (c *codDatabase) AddCODPincode(ctx context.Context, pincode string) (domain.CODPincode, error) {
+	var addedPincode domain.CODPincode
+	addPincodeQuery := `	INSERT INTO cod_pincodes (pincode, created_at) VALUES ($1, NOW())
+							ON CONFLICT (pincode) DO NOTHING RETURNING *;`
+	err := c.DB.Raw(addPincodeQuery, pincode).Scan(&addedPincode).Error
+	if err != nil {
+		return domain.CODPincode{}, err
+	}
+	if addedPincode.ID == 0 {
+		return domain.CODPincode{}, fmt.Errorf("pincode %v is already allowed", pincode)
+	}
+	return addedPincode, nil
+}
+
+func (c *codDatabase) RemoveCODPincode(ctx context.Context, pincode string) error {
+	removePincodeQuery := `DELETE FROM cod_pincodes WHERE pincode = $1;`
+	result := c.DB.Exec(removePincodeQuery, pincode)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("pincode %v is not in the allowlist", pincode)
+	}
+	return nil
+}
+
+// RecordCODCollection records the outcome of a cash on delivery attempt. Collecting the cash completes the payment of the
+// order, which is only allowed for the amount due on the order. A refused delivery leaves the payment pending.
+func (c *codDatabase) RecordCODCollection(ctx context.Context, collection model.CODCollection, adminID int) (domain.CODCollection, error) {
+	tx := c.DB.Begin()
+
+	paymentDetails, err := lockPayment(tx, uint(collection.OrderID))
+	if err != nil {
+		tx.Rollback()
+		return domain.CODCollection{}, err
+	}
+	if paymentDetails.PaymentMethodID != domain.PaymentCOD {
+		tx.Rollback()
+		return domain.CODCollection{}, fmt.Errorf("order is not paid cash on delivery")
+	}
+	if paymentDetails.PaymentStatusID != domain.PaymentPending {
+		tx.Rollback()
+		return domain.CODCollection{}, fmt.Errorf("payment is %v, only a pending payment can be collected", paymentDetails.PaymentStatusID.Name())
+	}
+
+	var orderStatusID domain.OrderStatusID
+	orderStatusQuery := `SELECT order_status_id FROM orders WHERE id = $1;`
+	if err := tx.Raw(orderStatusQuery, collection.OrderID).Scan(&orderStatusID).Error; err != nil {
+		tx.Rollback()
+		return domain.CODCollection{}, err
+	}
+	if orderStatusID == domain.OrderCancelledByUser || orderStatusID == domain.OrderCancelledByAdmin {
+		tx.Rollback()
+		return domain.CODCollection{}, fmt.Errorf("order is %v", orderStatusID.Name())
+	}
+
+	if domain.CODCollectionStatus(collection.Status) == domain.CODCollected && roundToPaise(collection.Amount) != paymentDetails.AmountDue() {
+		tx.Rollback()
+		return domain.CODCollection{}, fmt.Errorf("collected amount %v does not match the amount due %v", collection.Amount, paymentDetails.AmountDue())
+	}
+
+	var recordedCollection domain.CODCollection
+	recordCollectionQuery := `	INSERT INTO cod_collections (order_id, payment_details_id, status, amount, collected_by, admin_id, note, created_at)
+								VALUES ($1, $2, $3, $4, $5, $6, $7, NOW()) RETURNING *;`
+	err = tx.Raw(recordCollectionQuery, collection.OrderID, paymentDetails.ID, collection.Status, collection.Amount, collection.CollectedBy, adminID, collection.Note).Scan(&recordedCollection).Error
+	if err != nil {
+		tx.Rollback()
+		return domain.CODCollection{}, err
+	}
+
+	if recordedCollection.Status == domain.CODCollected {
+		completePaymentQuery := `UPDATE payment_details SET payment_status_id = $1, updated_at = NOW() WHERE id = $2;`
+		if err := tx.Exec(completePaymentQuery, domain.PaymentCompleted, paymentDetails.ID).Error; err != nil {
+			tx.Rollback()
+			return domain.CODCollection{}, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return domain.CODCollection{}, err
+	}
+	return recordedCollection, nil
+}
+
+// checkCODEligibility checks that a new order of the user can be paid cash on delivery to the pincode
+func checkCODEligibility(tx *gorm.DB, userID uint, orderTotal float64, pincode string) error {
+	var rules domain.CODRules
+	fetchRulesQuery := `SELECT * FROM cod_rules WHERE id = 1;`
+	if err := tx.Raw(fetchRulesQuery).Scan(&rules).Error; err != nil {
+		return err
+	}
+	if rules.MaxOrderValue > 0 && orderTotal > rules.MaxOrderValue {
+		return fmt.Errorf("cash on delivery is not available for orders above %v", rules.MaxOrderValue)
+	}
+
+	var pincodeAllowed bool
+	pincodeAllowedQuery := `SELECT NOT EXISTS (SELECT 1 FROM cod_pincodes) OR EXISTS (SELECT 1 FROM cod_pincodes WHERE pincode = $1);`
+	if err := tx.Raw(pincodeAllowedQuery, pincode).Scan(&pincodeAllowed).Error; err != nil {
+		return err
+	}
+	if !pincodeAllowed {
+		return fmt.Errorf("cash on delivery is not available for pincode %v", pincode)
+	}
+
+	if rules.MaxRefusedDeliveries > 0 {
+		var refusedDeliveries int
+		refusedDeliveriesQuery := `	SELECT COUNT(*) FROM cod_collections cc
+									JOIN orders o ON o.id = cc.order_id
+									WHERE o.user_id = $1 AND cc.status = $2;`
+		if err := tx.Raw(refusedDeliveriesQuery, userID, domain.CODRefused).Scan(&refusedDeliveries).Error; err != nil {
+			return err
+		}
+		if refusedDeliveries >= rules.MaxRefusedDeliveries {
+			return fmt.Errorf("cash on delivery is not available as %v deliveries were refused", refusedDeliveries)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func TestRecordCODCollection(t *testing.T) {
+	paymentColumns := []string{"id", "order_id", "order_total", "payment_method_id", "payment_status_id"}
+	collectionColumns := []string{"id", "order_id", "payment_details_id", "status", "amount", "collected_by", "admin_id"}
+
+	tests := []struct {
+		name           string
+		input          model.CODCollection
+		expectedOutput domain.CODCollection
+		buildStub      func(mock sqlmock.Sqlmock)
+		expectedErr    error
+	}{
+		{ //test case for cash collected at the door, the payment of the order is completed
+			name:           "collected",
+			input:          model.CODCollection{OrderID: 4, Status: "collected", Amount: 1200.5, CollectedBy: "ravi"},
+			expectedOutput: domain.CODCollection{ID: 1, OrderID: 4, PaymentDetailsID: 9, Status: domain.CODCollected, Amount: 1200.5, CollectedBy: "ravi", AdminID: 1},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^SELECT \\* FROM payment_details WHERE order_id = \\$1 FOR UPDATE;$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows(paymentColumns).AddRow(9, 4, 1200.5, domain.PaymentCOD, domain.PaymentPending))
+				mock.ExpectQuery("^SELECT order_status_id FROM orders (.+)$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"order_status_id"}).AddRow(domain.OrderPending))
+				mock.ExpectQuery("^INSERT INTO cod_collections (.+)$").
+					WithArgs(4, 9, "collected", 1200.5, "ravi", 1, "").
+					WillReturnRows(sqlmock.NewRows(collectionColumns).AddRow(1, 4, 9, "collected", 1200.5, "ravi", 1))
+				mock.ExpectExec("^UPDATE payment_details SET (.+)$").
+					WithArgs(domain.PaymentCompleted, 9).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{ //test case for a refused delivery, the payment stays pending
+			name:           "refused",
+			input:          model.CODCollection{OrderID: 4, Status: "refused", CollectedBy: "ravi", Note: "not at home"},
+			expectedOutput: domain.CODCollection{ID: 2, OrderID: 4, PaymentDetailsID: 9, Status: domain.CODRefused, CollectedBy: "ravi", AdminID: 1},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^SELECT \\* FROM payment_details WHERE order_id = \\$1 FOR UPDATE;$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows(paymentColumns).AddRow(9, 4, 1200.5, domain.PaymentCOD, domain.PaymentPending))
+				mock.ExpectQuery("^SELECT order_status_id FROM orders (.+)$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"order_status_id"}).AddRow(domain.OrderPending))
+				mock.ExpectQuery("^INSERT INTO cod_collections (.+)$").
+					WithArgs(4, 9, "refused", 0.0, "ravi", 1, "not at home").
+					WillReturnRows(sqlmock.NewRows(collectionColumns).AddRow(2, 4, 9, "refused", 0, "ravi", 1))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{ //test case for collecting less than the amount due
+			name:           "short collection",
+			input:          model.CODCollection{OrderID: 4, Status: "collected", Amount: 1200, CollectedBy: "ravi"},
+			expectedOutput: domain.CODCollection{},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^SELECT \\* FROM payment_details WHERE order_id = \\$1 FOR UPDATE;$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows(paymentColumns).AddRow(9, 4, 1200.5, domain.PaymentCOD, domain.PaymentPending))
+				mock.ExpectQuery("^SELECT order_status_id FROM orders (.+)$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"order_status_id"}).AddRow(domain.OrderPending))
+				mock.ExpectRollback()
+			},
+			expectedErr: errors.New("collected amount 1200 does not match the amount due 1200.5"),
+		},
+		{ //test case for an order paid online
+			name:           "online order",
+			input:          model.CODCollection{OrderID: 4, Status: "collected", Amount: 1200.5, CollectedBy: "ravi"},
+			expectedOutput: domain.CODCollection{},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^SELECT \\* FROM payment_details WHERE order_id = \\$1 FOR UPDATE;$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows(paymentColumns).AddRow(9, 4, 1200.5, domain.PaymentOnline, domain.PaymentPending))
+				mock.ExpectRollback()
+			},
+			expectedErr: errors.New("order is not paid cash on delivery"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when initializing a mock db session", err)
+			}
+
+			codRepository := NewCODRepository(gormDB)
+			tt.buildStub(mock)
+
+			actualOutput, actualErr := codRepository.RecordCODCollection(context.TODO(), tt.input, 1)
+			assert.Equal(t, tt.expectedErr, actualErr)
+			assert.Equal(t, tt.expectedOutput, actualOutput)
+
+			err = mock.ExpectationsWereMet()
+			if err != nil {
+				t.Errorf("Unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
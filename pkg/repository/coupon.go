@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"fmt"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/pricing"
+	"gorm.io/gorm"
+)
+
+// findPricingCoupon fetches a coupon with its targets, its users and how much it was used, for pricing an order of
+// the user. A coupon that no longer exists is returned empty, so that pricing tells why it does not apply.
+func findPricingCoupon(tx *gorm.DB, couponID int, userID uint) (*pricing.Coupon, error) {
+	var coupon pricing.Coupon
+	if err := tx.Raw("SELECT * FROM coupons WHERE id = $1;", couponID).Scan(&coupon.Coupon).Error; err != nil {
+		return nil, err
+	}
+	if coupon.ID == 0 {
+		return &coupon, nil
+	}
+
+	err := tx.Raw("SELECT target_id FROM coupon_targets WHERE coupon_id = $1", couponID).Scan(&coupon.TargetIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	err = tx.Raw("SELECT user_id FROM coupon_users WHERE coupon_id = $1", couponID).Scan(&coupon.UserIDs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var usage struct {
+		Redemptions     int
+		UserRedemptions int
+		UserOrders      int
+	}
+	usageQuery := `	SELECT
+						(SELECT COUNT(*) FROM coupon_redemptions WHERE coupon_id = $1 AND released_at IS NULL) AS redemptions,
+						(SELECT COUNT(*) FROM coupon_redemptions WHERE coupon_id = $1 AND user_id = $2 AND released_at IS NULL) AS user_redemptions,
+						(SELECT COUNT(*) FROM orders WHERE user_id = $2 AND order_status_id NOT IN ($3, $4)) AS user_orders`
+	err = tx.Raw(usageQuery, couponID, userID, domain.OrderCancelledByUser, domain.OrderCancelledByAdmin).Scan(&usage).Error
+	if err != nil {
+		return nil, err
+	}
+	coupon.Redemptions = usage.Redemptions
+	coupon.UserRedemptions = usage.UserRedemptions
+	coupon.UserOrders = usage.UserOrders
+	return &coupon, nil
+}
+
+// saveCouponRules stores the categories, brands or product items the coupon is limited to and the users it is for
+func saveCouponRules(tx *gorm.DB, couponID uint, targetIDs, userIDs []uint) error {
+	for _, targetID := range targetIDs {
+		insertTargetQuery := `INSERT INTO coupon_targets (coupon_id, target_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+		if err := tx.Exec(insertTargetQuery, couponID, targetID).Error; err != nil {
+			return err
+		}
+	}
+	for _, userID := range userIDs {
+		insertUserQuery := `INSERT INTO coupon_users (coupon_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+		if err := tx.Exec(insertUserQuery, couponID, userID).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func deleteCouponRules(tx *gorm.DB, couponID uint) error {
+	if err := tx.Exec("DELETE FROM coupon_targets WHERE coupon_id = $1", couponID).Error; err != nil {
+		return err
+	}
+	return tx.Exec("DELETE FROM coupon_users WHERE coupon_id = $1", couponID).Error
+}
+
+// redeemCoupon records the use of the coupon by the order, in the transaction placing the order. The use takes the
+// lowest free slot under each limit of the coupon. A slot taken by a concurrent order is only seen when the insert
+// conflicts with it, so the coupon is not used more than its limits allow even when the pricing of both orders passed.
+func redeemCoupon(tx *gorm.DB, couponID, userID, orderID uint, discount float64) error {
+	slots, err := findCouponSlots(tx, couponID, userID)
+	if err != nil {
+		return err
+	}
+	if slots.Code == "" {
+		return fmt.Errorf("coupon no longer exists")
+	}
+	if slots.UsageLimit > 0 && slots.UsageSlot == nil {
+		return fmt.Errorf("coupon %v has been fully redeemed", slots.Code)
+	}
+	if slots.PerUserLimit > 0 && slots.UserSlot == nil {
+		return fmt.Errorf("coupon %v was already used", slots.Code)
+	}
+
+	var redemptionID uint
+	redeemQuery := `INSERT INTO coupon_redemptions (coupon_id, user_id, order_id, usage_slot, user_slot, discount, created_at)
+					VALUES ($1, $2, $3, $4, $5, $6, NOW())
+					ON CONFLICT DO NOTHING RETURNING id`
+	err = tx.Raw(redeemQuery, couponID, userID, orderID, slots.UsageSlot, slots.UserSlot, discount).Scan(&redemptionID).Error
+	if err != nil {
+		return err
+	}
+	if redemptionID == 0 {
+		return fmt.Errorf("coupon %v was just used by another order, please try again", slots.Code)
+	}
+	return nil
+}
+
+// couponSlots are the lowest free slots under each limit of a coupon for a user, nil when every slot is taken. The code
+// is empty when the coupon no longer exists.
+type couponSlots struct {
+	Code         string
+	UsageLimit   int
+	PerUserLimit int
+	UsageSlot    *int
+	UserSlot     *int
+}
+
+func findCouponSlots(tx *gorm.DB, couponID, userID uint) (couponSlots, error) {
+	var slots couponSlots
+	findSlotsQuery := `	SELECT c.code, c.usage_limit, c.per_user_limit,
+							(SELECT MIN(s) FROM generate_series(1, c.usage_limit) s
+								WHERE NOT EXISTS (SELECT 1 FROM coupon_redemptions r WHERE r.coupon_id = c.id AND r.usage_slot = s)) AS usage_slot,
+							(SELECT MIN(s) FROM generate_series(1, c.per_user_limit) s
+								WHERE NOT EXISTS (SELECT 1 FROM coupon_redemptions r WHERE r.coupon_id = c.id AND r.user_id = $2 AND r.user_slot = s)) AS user_slot
+						FROM coupons c WHERE c.id = $1`
+	err := tx.Raw(findSlotsQuery, couponID, userID).Scan(&slots).Error
+	return slots, err
+}
+
+// releaseCouponRedemption gives back the use of a coupon by an order that was cancelled or whose payment failed. Its slots are freed and the
+// redemption is kept for the report of the coupon.
+func releaseCouponRedemption(tx *gorm.DB, orderID uint) error {
+	releaseQuery := `	UPDATE coupon_redemptions SET released_at = NOW(), usage_slot = NULL, user_slot = NULL
+						WHERE order_id = $1 AND released_at IS NULL`
+	return tx.Exec(releaseQuery, orderID).Error
+}
+
+// restoreCouponRedemption takes back the use of a coupon released when the payment of the order failed, once the order
+// is paid for after all. The order was priced with the discount, so the use is recorded even when the coupon has no
+// slot left meanwhile, in which case it takes no slot.
+func restoreCouponRedemption(tx *gorm.DB, orderID uint) error {
+	var redemption domain.CouponRedemption
+	findRedemptionQuery := `SELECT * FROM coupon_redemptions WHERE order_id = $1 AND released_at IS NOT NULL`
+	if err := tx.Raw(findRedemptionQuery, orderID).Scan(&redemption).Error; err != nil {
+		return err
+	}
+	if redemption.ID == 0 {
+		return nil
+	}
+
+	slots, err := findCouponSlots(tx, redemption.CouponID, redemption.UserID)
+	if err != nil {
+		return err
+	}
+	restoreQuery := `	UPDATE coupon_redemptions SET released_at = NULL, usage_slot = $1, user_slot = $2
+						WHERE id = $3 AND released_at IS NOT NULL`
+	return tx.Exec(restoreQuery, slots.UsageSlot, slots.UserSlot, redemption.ID).Error
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func TestRedeemCoupon(t *testing.T) {
+	slotColumns := []string{"code", "usage_limit", "per_user_limit", "usage_slot", "user_slot"}
+
+	tests := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{ //test case for a coupon with free slots, the redemption takes the lowest of them
+			name: "redeemed",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT c.code, c.usage_limit, c.per_user_limit, (.+)$").
+					WithArgs(4, 1).
+					WillReturnRows(sqlmock.NewRows(slotColumns).AddRow("SAVE10", 100, 1, 3, 1))
+				mock.ExpectQuery("^INSERT INTO coupon_redemptions (.+) ON CONFLICT DO NOTHING RETURNING id$").
+					WithArgs(4, 1, 8, 3, 1, 150.0).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+			},
+			expectedErr: nil,
+		},
+		{ //test case for a coupon without limits, the redemption takes no slots
+			name: "no limits",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT c.code, c.usage_limit, c.per_user_limit, (.+)$").
+					WithArgs(4, 1).
+					WillReturnRows(sqlmock.NewRows(slotColumns).AddRow("SAVE10", 0, 0, nil, nil))
+				mock.ExpectQuery("^INSERT INTO coupon_redemptions (.+) ON CONFLICT DO NOTHING RETURNING id$").
+					WithArgs(4, 1, 8, nil, nil, 150.0).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+			},
+			expectedErr: nil,
+		},
+		{ //test case for a coupon with every use taken
+			name: "fully redeemed",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT c.code, c.usage_limit, c.per_user_limit, (.+)$").
+					WithArgs(4, 1).
+					WillReturnRows(sqlmock.NewRows(slotColumns).AddRow("SAVE10", 100, 1, nil, 1))
+			},
+			expectedErr: errors.New("coupon SAVE10 has been fully redeemed"),
+		},
+		{ //test case for a user who already used the coupon as many times as a user can
+			name: "already used",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT c.code, c.usage_limit, c.per_user_limit, (.+)$").
+					WithArgs(4, 1).
+					WillReturnRows(sqlmock.NewRows(slotColumns).AddRow("SAVE10", 0, 1, nil, nil))
+			},
+			expectedErr: errors.New("coupon SAVE10 was already used"),
+		},
+		{ //test case for a slot taken by a concurrent order, the insert conflicts with it and inserts nothing
+			name: "taken by concurrent order",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT c.code, c.usage_limit, c.per_user_limit, (.+)$").
+					WithArgs(4, 1).
+					WillReturnRows(sqlmock.NewRows(slotColumns).AddRow("SAVE10", 100, 1, 3, 1))
+				mock.ExpectQuery("^INSERT INTO coupon_redemptions (.+) ON CONFLICT DO NOTHING RETURNING id$").
+					WithArgs(4, 1, 8, 3, 1, 150.0).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			expectedErr: errors.New("coupon SAVE10 was just used by another order, please try again"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when initializing a mock db session", err)
+			}
+
+			tt.buildStub(mock)
+
+			actualErr := redeemCoupon(gormDB, 4, 1, 8, 150)
+			assert.Equal(t, tt.expectedErr, actualErr)
+
+			err = mock.ExpectationsWereMet()
+			if err != nil {
+				t.Errorf("Unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	interfaces "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/interface"
+	"gorm.io/gorm"
+	"time"
+)
+
+type idempotencyDatabase struct {
+	DB *gorm.DB
+}
+
+func NewIdempotencyRepository(DB *gorm.DB) interfaces.IdempotencyRepository {
+	return &idempotencyDatabase{DB}
+}
+
+// ClaimIdempotencyKey stores a new key of the user for the request, and reports whether it was claimed by this call. If the
+// user already sent the key, the stored key is returned instead. A key of the same request that is still in progress but
+// was claimed before staleBefore was abandoned by a request that never finished, and is claimed again by this call. The
+// expired keys of the user are removed first, so that an expired key can be used again.
+func (c *idempotencyDatabase) ClaimIdempotencyKey(ctx context.Context, userID int, key, requestHash string, expiresAt, staleBefore time.Time) (domain.IdempotencyKey, bool, error) {
+	tx := c.DB.Begin()
+
+	removeExpiredQuery := `DELETE FROM idempotency_keys WHERE user_id = $1 AND expires_at < NOW();`
+	if err := tx.Exec(removeExpiredQuery, userID).Error; err != nil {
+		tx.Rollback()
+		return domain.IdempotencyKey{}, false, err
+	}
+
+	//the conflicting row is locked by the insert, so only one retry can take over an abandoned key
+	var claimedKey domain.IdempotencyKey
+	claimKeyQuery := `	INSERT INTO idempotency_keys (user_id, key, request_hash, completed, expires_at, created_at)
+						VALUES ($1, $2, $3, false, $4, NOW())
+						ON CONFLICT (user_id, key) DO UPDATE SET expires_at = EXCLUDED.expires_at, created_at = NOW()
+						WHERE idempotency_keys.completed = false AND idempotency_keys.request_hash = EXCLUDED.request_hash
+						AND idempotency_keys.created_at < $5 RETURNING *;`
+	if err := tx.Raw(claimKeyQuery, userID, key, requestHash, expiresAt, staleBefore).Scan(&claimedKey).Error; err != nil {
+		tx.Rollback()
+		return domain.IdempotencyKey{}, false, err
+	}
+	claimed := claimedKey.ID != 0
+
+	if !claimed {
+		fetchKeyQuery := `SELECT * FROM idempotency_keys WHERE user_id = $1 AND key = $2;`
+		if err := tx.Raw(fetchKeyQuery, userID, key).Scan(&claimedKey).Error; err != nil {
+			tx.Rollback()
+			return domain.IdempotencyKey{}, false, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return domain.IdempotencyKey{}, false, err
+	}
+	return claimedKey, claimed, nil
+}
+
+// SaveIdempotentResponse stores the response a request with the key got, which is replayed for retries. The key is matched
+// on the time it was claimed, so a request that finishes after its key was claimed again does not overwrite it.
+func (c *idempotencyDatabase) SaveIdempotentResponse(ctx context.Context, id uint, claimedAt time.Time, statusCode int, contentType string, body []byte) error {
+	saveResponseQuery := `	UPDATE idempotency_keys SET completed = true, status_code = $1, content_type = $2, response_body = $3
+							WHERE id = $4 AND created_at = $5;`
+	return c.DB.Exec(saveResponseQuery, statusCode, contentType, body, id, claimedAt).Error
+}
+
+// ReleaseIdempotencyKey removes a key whose request failed, so that the request can be retried with it. Like
+// SaveIdempotentResponse, only the key claimed at claimedAt is removed.
+func (c *idempotencyDatabase) ReleaseIdempotencyKey(ctx context.Context, id uint, claimedAt time.Time) error {
+	releaseKeyQuery := `DELETE FROM idempotency_keys WHERE id = $1 AND created_at = $2;`
+	return c.DB.Exec(releaseKeyQuery, id, claimedAt).Error
+}
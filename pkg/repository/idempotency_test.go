@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func TestClaimIdempotencyKey(t *testing.T) {
+	keyColumns := []string{"id", "user_id", "key", "request_hash", "completed", "expires_at", "created_at"}
+	expiresAt := time.Date(2023, 4, 3, 10, 0, 0, 0, time.UTC)
+	staleBefore := time.Date(2023, 4, 2, 9, 59, 0, 0, time.UTC)
+	claimedAt := time.Date(2023, 4, 2, 9, 50, 0, 0, time.UTC)
+	reclaimedAt := time.Date(2023, 4, 2, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		expectedOutput domain.IdempotencyKey
+		expectedClaim  bool
+		buildStub      func(mock sqlmock.Sqlmock)
+		expectedErr    error
+	}{
+		{ //test case for a key sent for the first time
+			name:           "new key",
+			expectedOutput: domain.IdempotencyKey{ID: 5, UserID: 1, Key: "key_1", RequestHash: "hash", ExpiresAt: expiresAt, CreatedAt: reclaimedAt},
+			expectedClaim:  true,
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("^DELETE FROM idempotency_keys WHERE user_id = \\$1 AND expires_at < NOW\\(\\);$").
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("^INSERT INTO idempotency_keys (.+) ON CONFLICT \\(user_id, key\\) DO UPDATE (.+)$").
+					WithArgs(1, "key_1", "hash", expiresAt, staleBefore).
+					WillReturnRows(sqlmock.NewRows(keyColumns).AddRow(5, 1, "key_1", "hash", false, expiresAt, reclaimedAt))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{ //test case for a key still held by a request within its lease, the stored key is returned
+			name:           "in progress",
+			expectedOutput: domain.IdempotencyKey{ID: 5, UserID: 1, Key: "key_1", RequestHash: "hash", ExpiresAt: expiresAt, CreatedAt: claimedAt},
+			expectedClaim:  false,
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("^DELETE FROM idempotency_keys (.+)$").
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("^INSERT INTO idempotency_keys (.+)$").
+					WithArgs(1, "key_1", "hash", expiresAt, staleBefore).
+					WillReturnRows(sqlmock.NewRows(keyColumns))
+				mock.ExpectQuery("^SELECT \\* FROM idempotency_keys WHERE user_id = \\$1 AND key = \\$2;$").
+					WithArgs(1, "key_1").
+					WillReturnRows(sqlmock.NewRows(keyColumns).AddRow(5, 1, "key_1", "hash", false, expiresAt, claimedAt))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{ //test case for a key abandoned by a request that held it past its lease, the key is claimed again
+			name:           "abandoned key",
+			expectedOutput: domain.IdempotencyKey{ID: 5, UserID: 1, Key: "key_1", RequestHash: "hash", ExpiresAt: expiresAt, CreatedAt: reclaimedAt},
+			expectedClaim:  true,
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("^DELETE FROM idempotency_keys (.+)$").
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("^INSERT INTO idempotency_keys (.+) WHERE idempotency_keys.completed = false (.+) AND idempotency_keys.created_at < \\$5 RETURNING \\*;$").
+					WithArgs(1, "key_1", "hash", expiresAt, staleBefore).
+					WillReturnRows(sqlmock.NewRows(keyColumns).AddRow(5, 1, "key_1", "hash", false, expiresAt, reclaimedAt))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when initializing a mock db session", err)
+			}
+
+			idempotencyRepository := NewIdempotencyRepository(gormDB)
+			tt.buildStub(mock)
+
+			actualOutput, actualClaim, actualErr := idempotencyRepository.ClaimIdempotencyKey(context.TODO(), 1, "key_1", "hash", expiresAt, staleBefore)
+			assert.Equal(t, tt.expectedErr, actualErr)
+			assert.Equal(t, tt.expectedClaim, actualClaim)
+			assert.Equal(t, tt.expectedOutput, actualOutput)
+
+			err = mock.ExpectationsWereMet()
+			if err != nil {
+				t.Errorf("Unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
@@ -10,6 +10,8 @@ type CartRepository interface {
 	AddToCart(ctx context.Context, userID int, productItemID int) (domain.CartItems, error)
 	RemoveFromCart(ctx context.Context, userID int, productItemID int) error
 	ViewCart(ctx context.Context, userID int) (model.ViewCart, error)
+	UpdateCartItem(ctx context.Context, userID, productItemID, quantity int) (model.ViewCart, error)
 	EmptyCart(ctx context.Context, userID int) error
 	AddCouponToCart(ctx context.Context, userID, couponID int) (model.ViewCart, error)
+	ApplicableCoupons(ctx context.Context, userID int) ([]model.ApplicableCoupon, error)
 }
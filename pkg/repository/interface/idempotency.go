@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"time"
+)
+
+type IdempotencyRepository interface {
+	ClaimIdempotencyKey(ctx context.Context, userID int, key, requestHash string, expiresAt, staleBefore time.Time) (domain.IdempotencyKey, bool, error)
+	SaveIdempotentResponse(ctx context.Context, id uint, claimedAt time.Time, statusCode int, contentType string, body []byte) error
+	ReleaseIdempotencyKey(ctx context.Context, id uint, claimedAt time.Time) error
+}
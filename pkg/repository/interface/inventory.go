@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+)
+
+type InventoryRepository interface {
+	AdjustStock(ctx context.Context, adjustment model.StockAdjustment, adminID int) (domain.StockMovement, error)
+	ViewStockMovements(ctx context.Context, productItemID int) ([]domain.StockMovement, error)
+	ReconcileStock(ctx context.Context) ([]model.StockReconciliation, error)
+}
@@ -11,7 +11,15 @@ type OrderRepository interface {
 	BuyAll(ctx context.Context, userID int, orderInfo model.PlaceAllOrders) (domain.Order, error)
 	ViewOrderById(ctx context.Context, userID int, orderID int) (domain.Order, error)
 	ViewAllOrders(ctx context.Context, userID int) ([]domain.Order, error)
-	CancelOrder(ctx context.Context, userID int, orderID int) (domain.Order, error)
-	UpdateOrder(ctx context.Context, orderInfo model.UpdateOrder) (domain.Order, error)
+	CancelOrder(ctx context.Context, userID int, orderID int) (domain.Order, domain.Refund, error)
+	UpdateOrder(ctx context.Context, currentOrder domain.Order, orderInfo model.UpdateOrder) (domain.Order, domain.Refund, error)
+	FindOrderByID(ctx context.Context, orderID int) (domain.Order, error)
+	ViewOrderStatusHistory(ctx context.Context, orderID int) ([]domain.OrderStatusHistory, error)
 	ReturnRequest(ctx context.Context, returnRequest model.ReturnRequest) (domain.Order, error)
+	ListReturns(ctx context.Context, status string) ([]domain.Return, error)
+	FindReturnByOrderID(ctx context.Context, orderID int) (domain.Return, error)
+	ApproveReturn(ctx context.Context, returnID int, note string) (domain.Return, domain.Refund, error)
+	RejectReturn(ctx context.Context, returnID int, note string) (domain.Return, error)
+	ReturnPickedUp(ctx context.Context, returnID int) (domain.Return, error)
+	ReturnReceived(ctx context.Context, returnID int) (domain.Return, error)
 }
@@ -3,9 +3,20 @@ package interfaces
 import (
 	"context"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"time"
 )
 
 type PaymentRepository interface {
 	ViewPaymentDetails(ctx context.Context, orderID int) (domain.PaymentDetails, error)
-	UpdatePaymentDetails(ctx context.Context, orderID int, paymentRef string) (domain.PaymentDetails, error)
+	SetGatewayOrderID(ctx context.Context, orderID int, gatewayOrderID string) (string, error)
+	UpdatePaymentDetails(ctx context.Context, orderID int, paymentRef string) (domain.PaymentDetails, domain.Refund, error)
+
+	CapturePayment(ctx context.Context, eventID string, orderID int, paymentRef string) (domain.Refund, error)
+	FailPayment(ctx context.Context, eventID string, orderID int, paymentRef string) error
+	ProcessRefund(ctx context.Context, eventID, paymentRef, refundRef string, amount float64) error
+	FailRefund(ctx context.Context, eventID, paymentRef, refundRef string) error
+
+	FindStalePayments(ctx context.Context, placedBefore time.Time) ([]domain.PaymentDetails, error)
+	MarkPaymentFailed(ctx context.Context, orderID int) (domain.PaymentDetails, error)
+	ExpirePayment(ctx context.Context, orderID int) (domain.Order, domain.Refund, error)
 }
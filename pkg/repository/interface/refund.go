@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+)
+
+type RefundRepository interface {
+	CreateRefund(ctx context.Context, newRefund model.CreateRefund) (domain.Refund, error)
+	ListRefunds(ctx context.Context, status string) ([]domain.Refund, error)
+	ViewOrderRefunds(ctx context.Context, orderID int) ([]domain.Refund, error)
+	RetryRefund(ctx context.Context, refundID int) (domain.Refund, error)
+
+	SetRefundRef(ctx context.Context, refundID uint, refundRef string) error
+	MarkRefundFailed(ctx context.Context, refundID uint, reason string) error
+}
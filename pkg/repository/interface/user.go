@@ -12,8 +12,12 @@ type UserRepository interface {
 	FindByPhone(ctx context.Context, phone string) (model.UserLoginVerifier, error)
 
 	AddAddress(ctx context.Context, userID int, newAddress model.AddressInput) (domain.Address, error)
-	UpdateAddress(ctx context.Context, userID int, address model.AddressInput) (domain.Address, error)
-	ViewAddress(ctx context.Context, userID int) (domain.Address, error)
+	UpdateAddress(ctx context.Context, userID, addressID int, address model.AddressInput) (domain.Address, error)
+	ListAddresses(ctx context.Context, userID int) ([]domain.Address, error)
+	FindAddressByID(ctx context.Context, userID, addressID int) (domain.Address, error)
+	FindDefaultAddress(ctx context.Context, userID int) (domain.Address, error)
+	SetDefaultAddress(ctx context.Context, userID, addressID int) (domain.Address, error)
+	DeleteAddress(ctx context.Context, userID, addressID int) error
 
 	ListAllUsers(ctx context.Context, queryParams model.QueryParams) ([]domain.Users, error)
 	FindUserByID(ctx context.Context, userID int) (domain.Users, error)
@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+)
+
+type WalletRepository interface {
+	ViewWallet(ctx context.Context, userID int) (model.Wallet, error)
+	AdjustWallet(ctx context.Context, adjustment model.WalletAdjustment, adminID int) (domain.WalletEntry, error)
+}
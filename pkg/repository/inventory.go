@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	interfaces "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/interface"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"gorm.io/gorm"
+)
+
+type inventoryDatabase struct {
+	DB *gorm.DB
+}
+
+func NewInventoryRepository(DB *gorm.DB) interfaces.InventoryRepository {
+	return &inventoryDatabase{DB}
+}
+
+func (c *inventoryDatabase) AdjustStock(ctx context.Context, adjustment model.StockAdjustment, adminID int) (domain.StockMovement, error) {
+	tx := c.DB.Begin()
+
+	//lock the product item, so that the check below holds until the adjustment is committed
+	var productItem domain.ProductItem
+	findProductItemQuery := `SELECT * FROM product_items WHERE id = $1 FOR UPDATE`
+	err := tx.Raw(findProductItemQuery, adjustment.ProductItemID).Scan(&productItem).Error
+	if err != nil {
+		tx.Rollback()
+		return domain.StockMovement{}, err
+	}
+	if productItem.ID == 0 {
+		tx.Rollback()
+		return domain.StockMovement{}, fmt.Errorf("no product item found")
+	}
+	if productItem.QntyInStock+adjustment.Quantity < 0 {
+		tx.Rollback()
+		return domain.StockMovement{}, fmt.Errorf("stock cannot go below zero, current stock is %v", productItem.QntyInStock)
+	}
+
+	updateStockQuery := `UPDATE product_items SET qnty_in_stock = qnty_in_stock + $1 WHERE id = $2`
+	err = tx.Exec(updateStockQuery, adjustment.Quantity, adjustment.ProductItemID).Error
+	if err != nil {
+		tx.Rollback()
+		return domain.StockMovement{}, err
+	}
+
+	movement, err := recordStockMovement(tx, domain.StockMovement{
+		ProductItemID: uint(adjustment.ProductItemID),
+		Quantity:      adjustment.Quantity,
+		Reason:        domain.StockMovementReason(adjustment.Reason),
+		AdminID:       uint(adminID),
+		Note:          adjustment.Note,
+	})
+	if err != nil {
+		tx.Rollback()
+		return domain.StockMovement{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return domain.StockMovement{}, err
+	}
+	return movement, nil
+}
+
+func (c *inventoryDatabase) ViewStockMovements(ctx context.Context, productItemID int) ([]domain.StockMovement, error) {
+	var movements []domain.StockMovement
+	fetchMovementsQuery := `SELECT * FROM stock_movements WHERE product_item_id = $1 ORDER BY created_at DESC, id DESC`
+	err := c.DB.Raw(fetchMovementsQuery, productItemID).Scan(&movements).Error
+	return movements, err
+}
+
+// ReconcileStock returns the product items whose stock does not match the sum of their ledger entries.
+func (c *inventoryDatabase) ReconcileStock(ctx context.Context) ([]model.StockReconciliation, error) {
+	var mismatches []model.StockReconciliation
+	reconcileQuery := `	SELECT
+							pi.id AS product_item_id,
+							pi.qnty_in_stock,
+							COALESCE(SUM(sm.quantity), 0) AS ledger_quantity,
+							pi.qnty_in_stock - COALESCE(SUM(sm.quantity), 0) AS difference
+						FROM product_items pi
+						LEFT JOIN stock_movements sm ON sm.product_item_id = pi.id
+						GROUP BY pi.id, pi.qnty_in_stock
+						HAVING pi.qnty_in_stock != COALESCE(SUM(sm.quantity), 0)
+						ORDER BY pi.id;`
+	err := c.DB.Raw(reconcileQuery).Scan(&mismatches).Error
+	return mismatches, err
+}
+
+// recordStockMovement writes an entry to the inventory ledger. It runs inside the transaction that changes the stock,
+// so the ledger and product_items.qnty_in_stock are always committed together.
+func recordStockMovement(tx *gorm.DB, movement domain.StockMovement) (domain.StockMovement, error) {
+	var recordedMovement domain.StockMovement
+	insertMovementQuery := `INSERT INTO stock_movements (product_item_id, quantity, reason, admin_id, order_id, note, created_at)
+							VALUES ($1, $2, $3, $4, $5, $6, NOW()) RETURNING *;`
+	err := tx.Raw(insertMovementQuery, movement.ProductItemID, movement.Quantity, movement.Reason, movement.AdminID, movement.OrderID, movement.Note).Scan(&recordedMovement).Error
+	return recordedMovement, err
+}
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"fmt"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultListingLimit = 10
+	// maxFilterValues is how many values an in filter can take
+	maxFilterValues = 100
+)
+
+type columnKind int
+
+const (
+	textColumn columnKind = iota
+	intColumn
+	floatColumn
+	timeColumn
+)
+
+// listingColumn is a column a listing can be filtered on, and sorted on when it is sortable
+type listingColumn struct {
+	kind     columnKind
+	sortable bool
+}
+
+// the columns each listing can be filtered and sorted on, keyed by the field names used in the query params. Only
+// these names are ever written into a listing query, every value is bound as a parameter.
+var (
+	productColumns = map[string]listingColumn{
+		"id":                  {kind: intColumn, sortable: true},
+		"product_category_id": {kind: intColumn, sortable: true},
+		"name":                {kind: textColumn, sortable: true},
+		"brand_id":            {kind: intColumn, sortable: true},
+		"description":         {kind: textColumn},
+	}
+	productItemColumns = map[string]listingColumn{
+		"id":            {kind: intColumn, sortable: true},
+		"product_id":    {kind: intColumn, sortable: true},
+		"model":         {kind: textColumn, sortable: true},
+		"processor":     {kind: textColumn, sortable: true},
+		"ram":           {kind: textColumn, sortable: true},
+		"storage":       {kind: textColumn, sortable: true},
+		"display_size":  {kind: textColumn, sortable: true},
+		"graphics_card": {kind: textColumn, sortable: true},
+		"os":            {kind: textColumn, sortable: true},
+		"sku":           {kind: textColumn, sortable: true},
+		"qnty_in_stock": {kind: intColumn, sortable: true},
+		"price":         {kind: floatColumn, sortable: true},
+		"max_per_order": {kind: intColumn, sortable: true},
+	}
+	userColumns = map[string]listingColumn{
+		"id":         {kind: intColumn, sortable: true},
+		"f_name":     {kind: textColumn, sortable: true},
+		"l_name":     {kind: textColumn, sortable: true},
+		"email":      {kind: textColumn, sortable: true},
+		"phone":      {kind: textColumn, sortable: true},
+		"created_at": {kind: timeColumn, sortable: true},
+	}
+)
+
+// listingQuery builds the query of a listing from its query params. The conditions are appended to the base query,
+// which should have no WHERE clause of its own, with their values as numbered parameters.
+type listingQuery struct {
+	columns    map[string]listingColumn
+	conditions []string
+	args       []interface{}
+}
+
+// buildListingQuery returns the query and its parameters for the listing of baseQuery with the query params. Fields
+// that are not columns of the listing and filters not valid for their field return a *domain.QueryParamsError.
+func buildListingQuery(baseQuery string, columns map[string]listingColumn, queryParams model.QueryParams) (string, []interface{}, error) {
+	q := &listingQuery{columns: columns}
+
+	if queryParams.Query != "" && queryParams.Filter != "" {
+		if err := q.addFilter(queryParams.Filter, "ilike", queryParams.Query); err != nil {
+			return "", nil, err
+		}
+	}
+	for _, filter := range queryParams.Filters {
+		parts := strings.SplitN(filter, ":", 3)
+		if len(parts) != 3 {
+			return "", nil, &domain.QueryParamsError{Reason: fmt.Sprintf("filter %q should be written as field:operator:value", filter)}
+		}
+		if err := q.addFilter(parts[0], parts[1], parts[2]); err != nil {
+			return "", nil, err
+		}
+	}
+
+	query := baseQuery
+	if len(q.conditions) > 0 {
+		query = fmt.Sprintf("%s WHERE %s", query, strings.Join(q.conditions, " AND "))
+	}
+
+	if queryParams.SortBy != "" {
+		column, ok := columns[queryParams.SortBy]
+		if !ok || !column.sortable {
+			return "", nil, &domain.QueryParamsError{Reason: fmt.Sprintf("cannot sort on %q", queryParams.SortBy)}
+		}
+		direction := "ASC"
+		if queryParams.SortDesc {
+			direction = "DESC"
+		}
+		query = fmt.Sprintf("%s ORDER BY %s %s", query, queryParams.SortBy, direction)
+	}
+
+	if queryParams.Page < 0 || queryParams.Limit < 0 {
+		return "", nil, &domain.QueryParamsError{Reason: "page and limit cannot be negative"}
+	}
+	limit, offset := defaultListingLimit, 0
+	if queryParams.Limit != 0 && queryParams.Page != 0 {
+		limit, offset = queryParams.Limit, (queryParams.Page-1)*queryParams.Limit
+	}
+	query = fmt.Sprintf("%s LIMIT %s OFFSET %s", query, q.bind(limit), q.bind(offset))
+	return query, q.args, nil
+}
+
+// addFilter adds the condition of the filter on the field. The field is written into the query only after it is
+// found among the columns of the listing.
+func (q *listingQuery) addFilter(field, operator, value string) error {
+	column, ok := q.columns[field]
+	if !ok {
+		return &domain.QueryParamsError{Reason: fmt.Sprintf("cannot filter on %q", field)}
+	}
+
+	switch operator {
+	case "eq":
+		arg, err := parseFilterValue(field, column.kind, value)
+		if err != nil {
+			return err
+		}
+		q.conditions = append(q.conditions, fmt.Sprintf("%s = %s", field, q.bind(arg)))
+	case "in":
+		values := strings.Split(value, ",")
+		if len(values) > maxFilterValues {
+			return &domain.QueryParamsError{Reason: fmt.Sprintf("%v can be filtered on at most %v values", field, maxFilterValues)}
+		}
+		placeholders := make([]string, len(values))
+		for i, value := range values {
+			arg, err := parseFilterValue(field, column.kind, value)
+			if err != nil {
+				return err
+			}
+			placeholders[i] = q.bind(arg)
+		}
+		q.conditions = append(q.conditions, fmt.Sprintf("%s IN (%s)", field, strings.Join(placeholders, ", ")))
+	case "range":
+		if column.kind == textColumn {
+			return &domain.QueryParamsError{Reason: fmt.Sprintf("%v cannot be filtered with range", field)}
+		}
+		bounds := strings.Split(value, ",")
+		if len(bounds) != 2 || (bounds[0] == "" && bounds[1] == "") {
+			return &domain.QueryParamsError{Reason: fmt.Sprintf("range of %v should be written as min,max", field)}
+		}
+		for i, operator := range []string{">=", "<="} {
+			if bounds[i] == "" {
+				continue
+			}
+			arg, err := parseFilterValue(field, column.kind, bounds[i])
+			if err != nil {
+				return err
+			}
+			q.conditions = append(q.conditions, fmt.Sprintf("%s %s %s", field, operator, q.bind(arg)))
+		}
+	case "ilike":
+		if column.kind != textColumn {
+			return &domain.QueryParamsError{Reason: fmt.Sprintf("%v cannot be filtered with ilike", field)}
+		}
+		//the value is searched for as it is, wildcards in it match only themselves
+		escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+		q.conditions = append(q.conditions, fmt.Sprintf("%s ILIKE %s", field, q.bind("%"+escaped+"%")))
+	default:
+		return &domain.QueryParamsError{Reason: fmt.Sprintf("unknown filter operator %q, use eq, in, range or ilike", operator)}
+	}
+	return nil
+}
+
+// bind adds the value to the parameters of the query and returns its placeholder
+func (q *listingQuery) bind(value interface{}) string {
+	q.args = append(q.args, value)
+	return fmt.Sprintf("$%d", len(q.args))
+}
+
+func parseFilterValue(field string, kind columnKind, value string) (interface{}, error) {
+	var (
+		arg interface{}
+		err error
+	)
+	switch kind {
+	case intColumn:
+		arg, err = strconv.ParseInt(value, 10, 64)
+	case floatColumn:
+		arg, err = strconv.ParseFloat(value, 64)
+	case timeColumn:
+		arg, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			arg, err = time.Parse("2006-01-02", value)
+		}
+	default:
+		arg = value
+	}
+	if err != nil {
+		return nil, &domain.QueryParamsError{Reason: fmt.Sprintf("invalid value %q for %v", value, field)}
+	}
+	return arg, nil
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func TestBuildListingQuery(t *testing.T) {
+	tests := []struct {
+		name          string
+		queryParams   model.QueryParams
+		expectedQuery string
+		expectedArgs  []interface{}
+		expectedErr   error
+	}{
+		{ //test case for a listing without filters, the default page is used
+			name:          "no filters",
+			queryParams:   model.QueryParams{},
+			expectedQuery: "SELECT * FROM users LIMIT $1 OFFSET $2",
+			expectedArgs:  []interface{}{10, 0},
+		},
+		{ //test case for the search query, searched in the filter field
+			name:          "search",
+			queryParams:   model.QueryParams{Query: "Ann", Filter: "f_name", Page: 3, Limit: 20},
+			expectedQuery: "SELECT * FROM users WHERE f_name ILIKE $1 LIMIT $2 OFFSET $3",
+			expectedArgs:  []interface{}{"%Ann%", 20, 40},
+		},
+		{ //test case for several filters at once, sorted
+			name: "several filters",
+			queryParams: model.QueryParams{
+				Filters:  []string{"id:in:1,2,3", "email:eq:ann@example.com", "created_at:range:2023-01-01,", "l_name:ilike:son"},
+				SortBy:   "created_at",
+				SortDesc: true,
+			},
+			expectedQuery: "SELECT * FROM users WHERE id IN ($1, $2, $3) AND email = $4 AND created_at >= $5 AND l_name ILIKE $6 " +
+				"ORDER BY created_at DESC LIMIT $7 OFFSET $8",
+			expectedArgs: []interface{}{int64(1), int64(2), int64(3), "ann@example.com", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), "%son%", 10, 0},
+		},
+		{ //test case for a value written to break out of its quotes, it is bound as it is
+			name:          "quotes in value",
+			queryParams:   model.QueryParams{Filters: []string{"email:eq:' OR '1'='1"}},
+			expectedQuery: "SELECT * FROM users WHERE email = $1 LIMIT $2 OFFSET $3",
+			expectedArgs:  []interface{}{"' OR '1'='1", 10, 0},
+		},
+		{ //test case for a search with wildcards and a statement in it, the wildcards match only themselves
+			name:          "wildcards in search",
+			queryParams:   model.QueryParams{Query: `%_\'; DROP TABLE users; --`, Filter: "email"},
+			expectedQuery: "SELECT * FROM users WHERE email ILIKE $1 LIMIT $2 OFFSET $3",
+			expectedArgs:  []interface{}{`%\%\_\\'; DROP TABLE users; --%`, 10, 0},
+		},
+		{ //test case for a value with the separator of filters in it, only the first two separate the filter
+			name:          "separator in value",
+			queryParams:   model.QueryParams{Filters: []string{"phone:eq:1:2"}},
+			expectedQuery: "SELECT * FROM users WHERE phone = $1 LIMIT $2 OFFSET $3",
+			expectedArgs:  []interface{}{"1:2", 10, 0},
+		},
+		{ //test case for a statement written in place of the field of the search
+			name:        "statement as search field",
+			queryParams: model.QueryParams{Query: "a", Filter: "email) LIKE '%' OR (1=1"},
+			expectedErr: &domain.QueryParamsError{Reason: `cannot filter on "email) LIKE '%' OR (1=1"`},
+		},
+		{ //test case for a statement written in place of the sort field
+			name:        "statement as sort field",
+			queryParams: model.QueryParams{SortBy: "id; DROP TABLE users"},
+			expectedErr: &domain.QueryParamsError{Reason: `cannot sort on "id; DROP TABLE users"`},
+		},
+		{ //test case for a column of the table that is not listed for filtering
+			name:        "column not listed",
+			queryParams: model.QueryParams{Filters: []string{"password:ilike:a"}},
+			expectedErr: &domain.QueryParamsError{Reason: `cannot filter on "password"`},
+		},
+		{ //test case for a statement written in place of the operator
+			name:        "statement as operator",
+			queryParams: model.QueryParams{Filters: []string{"id:= 1 OR 1=1 --:1"}},
+			expectedErr: &domain.QueryParamsError{Reason: `unknown filter operator "= 1 OR 1=1 --", use eq, in, range or ilike`},
+		},
+		{ //test case for a statement written in place of a number
+			name:        "statement as number",
+			queryParams: model.QueryParams{Filters: []string{"id:in:1,2) OR (1=1"}},
+			expectedErr: &domain.QueryParamsError{Reason: `invalid value "2) OR (1=1" for id`},
+		},
+		{ //test case for a filter without an operator
+			name:        "missing operator",
+			queryParams: model.QueryParams{Filters: []string{"id"}},
+			expectedErr: &domain.QueryParamsError{Reason: `filter "id" should be written as field:operator:value`},
+		},
+		{ //test case for a range on a text field
+			name:        "range on text",
+			queryParams: model.QueryParams{Filters: []string{"email:range:a,z"}},
+			expectedErr: &domain.QueryParamsError{Reason: "email cannot be filtered with range"},
+		},
+		{ //test case for a range without either bound
+			name:        "range without bounds",
+			queryParams: model.QueryParams{Filters: []string{"id:range:,"}},
+			expectedErr: &domain.QueryParamsError{Reason: "range of id should be written as min,max"},
+		},
+		{ //test case for a negative page, which would make the offset negative
+			name:        "negative page",
+			queryParams: model.QueryParams{Page: -1, Limit: 10},
+			expectedErr: &domain.QueryParamsError{Reason: "page and limit cannot be negative"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actualQuery, actualArgs, actualErr := buildListingQuery("SELECT * FROM users", userColumns, tt.queryParams)
+			assert.Equal(t, tt.expectedErr, actualErr)
+			assert.Equal(t, tt.expectedQuery, actualQuery)
+			assert.Equal(t, tt.expectedArgs, actualArgs)
+		})
+	}
+}
+
+func TestViewAllProductItems(t *testing.T) {
+	productItemColumns := []string{"id", "product_id", "model", "processor", "ram", "storage", "display_size", "graphics_card", "os", "sku",
+		"qnty_in_stock", "product_item_image", "price"}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when initializing a mock db session", err)
+	}
+
+	//the filters reach the database only as parameters
+	mock.ExpectQuery("^SELECT id, product_id, (.+) FROM product_items WHERE price >= \\$1 AND price <= \\$2 AND model ILIKE \\$3 ORDER BY price ASC LIMIT \\$4 OFFSET \\$5$").
+		WithArgs(50000.0, 80000.0, `%'; DELETE FROM product\_items; --%`, 10, 0).
+		WillReturnRows(sqlmock.NewRows(productItemColumns).
+			AddRow(5, 1, "3511", "i5", "8GB", "512GB", "15.6", "", "windows", "DL3511", 10, "", 60000))
+
+	productRepository := NewProductRepository(gormDB)
+	actualOutput, actualErr := productRepository.ViewAllProductItems(context.TODO(), model.QueryParams{
+		Filters: []string{"price:range:50000,80000", "model:ilike:'; DELETE FROM product_items; --"},
+		SortBy:  "price",
+	})
+	assert.NoError(t, actualErr)
+	assert.Equal(t, []domain.ProductItem{{ID: 5, ProductID: 1, Model: "3511", Processor: "i5", Ram: "8GB", Storage: "512GB", DisplaySize: "15.6",
+		OS: "windows", SKU: "DL3511", QntyInStock: 10, Price: 60000}}, actualOutput)
+
+	err = mock.ExpectationsWereMet()
+	if err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
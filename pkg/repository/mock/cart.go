@@ -0,0 +1,140 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/interface (interfaces: CartRepository)
+
+// Package mockRepo is a generated GoMock package.
+package mockRepo
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	model "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCartRepository is a mock of CartRepository interface.
+type MockCartRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCartRepositoryMockRecorder
+}
+
+// MockCartRepositoryMockRecorder is the mock recorder for MockCartRepository.
+type MockCartRepositoryMockRecorder struct {
+	mock *MockCartRepository
+}
+
+// NewMockCartRepository creates a new mock instance.
+func NewMockCartRepository(ctrl *gomock.Controller) *MockCartRepository {
+	mock := &MockCartRepository{ctrl: ctrl}
+	mock.recorder = &MockCartRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCartRepository) EXPECT() *MockCartRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddCouponToCart mocks base method.
+func (m *MockCartRepository) AddCouponToCart(arg0 context.Context, arg1, arg2 int) (model.ViewCart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCouponToCart", arg0, arg1, arg2)
+	ret0, _ := ret[0].(model.ViewCart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCouponToCart indicates an expected call of AddCouponToCart.
+func (mr *MockCartRepositoryMockRecorder) AddCouponToCart(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCouponToCart", reflect.TypeOf((*MockCartRepository)(nil).AddCouponToCart), arg0, arg1, arg2)
+}
+
+// AddToCart mocks base method.
+func (m *MockCartRepository) AddToCart(arg0 context.Context, arg1, arg2 int) (domain.CartItems, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddToCart", arg0, arg1, arg2)
+	ret0, _ := ret[0].(domain.CartItems)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddToCart indicates an expected call of AddToCart.
+func (mr *MockCartRepositoryMockRecorder) AddToCart(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToCart", reflect.TypeOf((*MockCartRepository)(nil).AddToCart), arg0, arg1, arg2)
+}
+
+// ApplicableCoupons mocks base method.
+func (m *MockCartRepository) ApplicableCoupons(arg0 context.Context, arg1 int) ([]model.ApplicableCoupon, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplicableCoupons", arg0, arg1)
+	ret0, _ := ret[0].([]model.ApplicableCoupon)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplicableCoupons indicates an expected call of ApplicableCoupons.
+func (mr *MockCartRepositoryMockRecorder) ApplicableCoupons(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicableCoupons", reflect.TypeOf((*MockCartRepository)(nil).ApplicableCoupons), arg0, arg1)
+}
+
+// EmptyCart mocks base method.
+func (m *MockCartRepository) EmptyCart(arg0 context.Context, arg1 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EmptyCart", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EmptyCart indicates an expected call of EmptyCart.
+func (mr *MockCartRepositoryMockRecorder) EmptyCart(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmptyCart", reflect.TypeOf((*MockCartRepository)(nil).EmptyCart), arg0, arg1)
+}
+
+// RemoveFromCart mocks base method.
+func (m *MockCartRepository) RemoveFromCart(arg0 context.Context, arg1, arg2 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFromCart", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveFromCart indicates an expected call of RemoveFromCart.
+func (mr *MockCartRepositoryMockRecorder) RemoveFromCart(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFromCart", reflect.TypeOf((*MockCartRepository)(nil).RemoveFromCart), arg0, arg1, arg2)
+}
+
+// UpdateCartItem mocks base method.
+func (m *MockCartRepository) UpdateCartItem(arg0 context.Context, arg1, arg2, arg3 int) (model.ViewCart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCartItem", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(model.ViewCart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCartItem indicates an expected call of UpdateCartItem.
+func (mr *MockCartRepositoryMockRecorder) UpdateCartItem(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCartItem", reflect.TypeOf((*MockCartRepository)(nil).UpdateCartItem), arg0, arg1, arg2, arg3)
+}
+
+// ViewCart mocks base method.
+func (m *MockCartRepository) ViewCart(arg0 context.Context, arg1 int) (model.ViewCart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewCart", arg0, arg1)
+	ret0, _ := ret[0].(model.ViewCart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewCart indicates an expected call of ViewCart.
+func (mr *MockCartRepositoryMockRecorder) ViewCart(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewCart", reflect.TypeOf((*MockCartRepository)(nil).ViewCart), arg0, arg1)
+}
@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/interface (interfaces: InventoryRepository)
+
+// Package mockRepo is a generated GoMock package.
+package mockRepo
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	model "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockInventoryRepository is a mock of InventoryRepository interface.
+type MockInventoryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockInventoryRepositoryMockRecorder
+}
+
+// MockInventoryRepositoryMockRecorder is the mock recorder for MockInventoryRepository.
+type MockInventoryRepositoryMockRecorder struct {
+	mock *MockInventoryRepository
+}
+
+// NewMockInventoryRepository creates a new mock instance.
+func NewMockInventoryRepository(ctrl *gomock.Controller) *MockInventoryRepository {
+	mock := &MockInventoryRepository{ctrl: ctrl}
+	mock.recorder = &MockInventoryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInventoryRepository) EXPECT() *MockInventoryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AdjustStock mocks base method.
+func (m *MockInventoryRepository) AdjustStock(arg0 context.Context, arg1 model.StockAdjustment, arg2 int) (domain.StockMovement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdjustStock", arg0, arg1, arg2)
+	ret0, _ := ret[0].(domain.StockMovement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdjustStock indicates an expected call of AdjustStock.
+func (mr *MockInventoryRepositoryMockRecorder) AdjustStock(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdjustStock", reflect.TypeOf((*MockInventoryRepository)(nil).AdjustStock), arg0, arg1, arg2)
+}
+
+// ReconcileStock mocks base method.
+func (m *MockInventoryRepository) ReconcileStock(arg0 context.Context) ([]model.StockReconciliation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileStock", arg0)
+	ret0, _ := ret[0].([]model.StockReconciliation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReconcileStock indicates an expected call of ReconcileStock.
+func (mr *MockInventoryRepositoryMockRecorder) ReconcileStock(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileStock", reflect.TypeOf((*MockInventoryRepository)(nil).ReconcileStock), arg0)
+}
+
+// ViewStockMovements mocks base method.
+func (m *MockInventoryRepository) ViewStockMovements(arg0 context.Context, arg1 int) ([]domain.StockMovement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewStockMovements", arg0, arg1)
+	ret0, _ := ret[0].([]domain.StockMovement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewStockMovements indicates an expected call of ViewStockMovements.
+func (mr *MockInventoryRepositoryMockRecorder) ViewStockMovements(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewStockMovements", reflect.TypeOf((*MockInventoryRepository)(nil).ViewStockMovements), arg0, arg1)
+}
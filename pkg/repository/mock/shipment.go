@@ -0,0 +1,112 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/interface (interfaces: ShipmentRepository)
+
+// Package mockRepo is a generated GoMock package.
+package mockRepo
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	model "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockShipmentRepository is a mock of ShipmentRepository interface.
+type MockShipmentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockShipmentRepositoryMockRecorder
+}
+
+// MockShipmentRepositoryMockRecorder is the mock recorder for MockShipmentRepository.
+type MockShipmentRepositoryMockRecorder struct {
+	mock *MockShipmentRepository
+}
+
+// NewMockShipmentRepository creates a new mock instance.
+func NewMockShipmentRepository(ctrl *gomock.Controller) *MockShipmentRepository {
+	mock := &MockShipmentRepository{ctrl: ctrl}
+	mock.recorder = &MockShipmentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShipmentRepository) EXPECT() *MockShipmentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddShipmentEvent mocks base method.
+func (m *MockShipmentRepository) AddShipmentEvent(arg0 context.Context, arg1 model.ShipmentEvent) (domain.ShipmentEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddShipmentEvent", arg0, arg1)
+	ret0, _ := ret[0].(domain.ShipmentEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddShipmentEvent indicates an expected call of AddShipmentEvent.
+func (mr *MockShipmentRepositoryMockRecorder) AddShipmentEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddShipmentEvent", reflect.TypeOf((*MockShipmentRepository)(nil).AddShipmentEvent), arg0, arg1)
+}
+
+// CreateCarrier mocks base method.
+func (m *MockShipmentRepository) CreateCarrier(arg0 context.Context, arg1 model.CreateCarrier) (domain.Carrier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCarrier", arg0, arg1)
+	ret0, _ := ret[0].(domain.Carrier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCarrier indicates an expected call of CreateCarrier.
+func (mr *MockShipmentRepositoryMockRecorder) CreateCarrier(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCarrier", reflect.TypeOf((*MockShipmentRepository)(nil).CreateCarrier), arg0, arg1)
+}
+
+// CreateShipment mocks base method.
+func (m *MockShipmentRepository) CreateShipment(arg0 context.Context, arg1 model.CreateShipment) (model.ShipmentDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateShipment", arg0, arg1)
+	ret0, _ := ret[0].(model.ShipmentDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateShipment indicates an expected call of CreateShipment.
+func (mr *MockShipmentRepositoryMockRecorder) CreateShipment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateShipment", reflect.TypeOf((*MockShipmentRepository)(nil).CreateShipment), arg0, arg1)
+}
+
+// ListCarriers mocks base method.
+func (m *MockShipmentRepository) ListCarriers(arg0 context.Context) ([]domain.Carrier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCarriers", arg0)
+	ret0, _ := ret[0].([]domain.Carrier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCarriers indicates an expected call of ListCarriers.
+func (mr *MockShipmentRepositoryMockRecorder) ListCarriers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCarriers", reflect.TypeOf((*MockShipmentRepository)(nil).ListCarriers), arg0)
+}
+
+// ViewOrderShipments mocks base method.
+func (m *MockShipmentRepository) ViewOrderShipments(arg0 context.Context, arg1 int) ([]model.ShipmentDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewOrderShipments", arg0, arg1)
+	ret0, _ := ret[0].([]model.ShipmentDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewOrderShipments indicates an expected call of ViewOrderShipments.
+func (mr *MockShipmentRepositoryMockRecorder) ViewOrderShipments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewOrderShipments", reflect.TypeOf((*MockShipmentRepository)(nil).ViewOrderShipments), arg0, arg1)
+}
@@ -81,6 +81,35 @@ func (mr *MockUserRepositoryMockRecorder) CreateUser(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserRepository)(nil).CreateUser), arg0, arg1)
 }
 
+// DeleteAddress mocks base method.
+func (m *MockUserRepository) DeleteAddress(arg0 context.Context, arg1, arg2 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAddress", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAddress indicates an expected call of DeleteAddress.
+func (mr *MockUserRepositoryMockRecorder) DeleteAddress(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAddress", reflect.TypeOf((*MockUserRepository)(nil).DeleteAddress), arg0, arg1, arg2)
+}
+
+// FindAddressByID mocks base method.
+func (m *MockUserRepository) FindAddressByID(arg0 context.Context, arg1, arg2 int) (domain.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAddressByID", arg0, arg1, arg2)
+	ret0, _ := ret[0].(domain.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAddressByID indicates an expected call of FindAddressByID.
+func (mr *MockUserRepositoryMockRecorder) FindAddressByID(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAddressByID", reflect.TypeOf((*MockUserRepository)(nil).FindAddressByID), arg0, arg1, arg2)
+}
+
 // FindByEmail mocks base method.
 func (m *MockUserRepository) FindByEmail(arg0 context.Context, arg1 string) (model.UserLoginVerifier, error) {
 	m.ctrl.T.Helper()
@@ -111,6 +140,21 @@ func (mr *MockUserRepositoryMockRecorder) FindByPhone(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByPhone", reflect.TypeOf((*MockUserRepository)(nil).FindByPhone), arg0, arg1)
 }
 
+// FindDefaultAddress mocks base method.
+func (m *MockUserRepository) FindDefaultAddress(arg0 context.Context, arg1 int) (domain.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDefaultAddress", arg0, arg1)
+	ret0, _ := ret[0].(domain.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDefaultAddress indicates an expected call of FindDefaultAddress.
+func (mr *MockUserRepositoryMockRecorder) FindDefaultAddress(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDefaultAddress", reflect.TypeOf((*MockUserRepository)(nil).FindDefaultAddress), arg0, arg1)
+}
+
 // FindUserByID mocks base method.
 func (m *MockUserRepository) FindUserByID(arg0 context.Context, arg1 int) (domain.Users, error) {
 	m.ctrl.T.Helper()
@@ -126,6 +170,21 @@ func (mr *MockUserRepositoryMockRecorder) FindUserByID(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUserByID", reflect.TypeOf((*MockUserRepository)(nil).FindUserByID), arg0, arg1)
 }
 
+// ListAddresses mocks base method.
+func (m *MockUserRepository) ListAddresses(arg0 context.Context, arg1 int) ([]domain.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAddresses", arg0, arg1)
+	ret0, _ := ret[0].([]domain.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAddresses indicates an expected call of ListAddresses.
+func (mr *MockUserRepositoryMockRecorder) ListAddresses(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAddresses", reflect.TypeOf((*MockUserRepository)(nil).ListAddresses), arg0, arg1)
+}
+
 // ListAllUsers mocks base method.
 func (m *MockUserRepository) ListAllUsers(arg0 context.Context, arg1 model.QueryParams) ([]domain.Users, error) {
 	m.ctrl.T.Helper()
@@ -141,6 +200,21 @@ func (mr *MockUserRepositoryMockRecorder) ListAllUsers(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllUsers", reflect.TypeOf((*MockUserRepository)(nil).ListAllUsers), arg0, arg1)
 }
 
+// SetDefaultAddress mocks base method.
+func (m *MockUserRepository) SetDefaultAddress(arg0 context.Context, arg1, arg2 int) (domain.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDefaultAddress", arg0, arg1, arg2)
+	ret0, _ := ret[0].(domain.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetDefaultAddress indicates an expected call of SetDefaultAddress.
+func (mr *MockUserRepositoryMockRecorder) SetDefaultAddress(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDefaultAddress", reflect.TypeOf((*MockUserRepository)(nil).SetDefaultAddress), arg0, arg1, arg2)
+}
+
 // UnblockUser mocks base method.
 func (m *MockUserRepository) UnblockUser(arg0 context.Context, arg1 int) (domain.UserInfo, error) {
 	m.ctrl.T.Helper()
@@ -157,31 +231,16 @@ func (mr *MockUserRepositoryMockRecorder) UnblockUser(arg0, arg1 interface{}) *g
 }
 
 // UpdateAddress mocks base method.
-func (m *MockUserRepository) UpdateAddress(arg0 context.Context, arg1 int, arg2 model.AddressInput) (domain.Address, error) {
+func (m *MockUserRepository) UpdateAddress(arg0 context.Context, arg1, arg2 int, arg3 model.AddressInput) (domain.Address, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateAddress", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "UpdateAddress", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(domain.Address)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateAddress indicates an expected call of UpdateAddress.
-func (mr *MockUserRepositoryMockRecorder) UpdateAddress(arg0, arg1, arg2 interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAddress", reflect.TypeOf((*MockUserRepository)(nil).UpdateAddress), arg0, arg1, arg2)
-}
-
-// ViewAddress mocks base method.
-func (m *MockUserRepository) ViewAddress(arg0 context.Context, arg1 int) (domain.Address, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ViewAddress", arg0, arg1)
-	ret0, _ := ret[0].(domain.Address)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// ViewAddress indicates an expected call of ViewAddress.
-func (mr *MockUserRepositoryMockRecorder) ViewAddress(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockUserRepositoryMockRecorder) UpdateAddress(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewAddress", reflect.TypeOf((*MockUserRepository)(nil).ViewAddress), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAddress", reflect.TypeOf((*MockUserRepository)(nil).UpdateAddress), arg0, arg1, arg2, arg3)
 }
@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/interface (interfaces: CampaignRepository)
+
+// Package mockRepo is a generated GoMock package.
+package mockRepo
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	model "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCampaignRepository is a mockRepo of CampaignRepository interface.
+type MockCampaignRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCampaignRepositoryMockRecorder
+}
+
+// MockCampaignRepositoryMockRecorder is the mockRepo recorder for MockCampaignRepository.
+type MockCampaignRepositoryMockRecorder struct {
+	mock *MockCampaignRepository
+}
+
+// NewMockCampaignRepository creates a new mockRepo instance.
+func NewMockCampaignRepository(ctrl *gomock.Controller) *MockCampaignRepository {
+	mock := &MockCampaignRepository{ctrl: ctrl}
+	mock.recorder = &MockCampaignRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCampaignRepository) EXPECT() *MockCampaignRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateCampaign mockRepo base method.
+func (m *MockCampaignRepository) CreateCampaign(arg0 context.Context, arg1 model.CreateCouponCampaign) (domain.CouponCampaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCampaign", arg0, arg1)
+	ret0, _ := ret[0].(domain.CouponCampaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCampaign indicates an expected call of CreateCampaign.
+func (mr *MockCampaignRepositoryMockRecorder) CreateCampaign(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCampaign", reflect.TypeOf((*MockCampaignRepository)(nil).CreateCampaign), arg0, arg1)
+}
+
+// ViewCampaignCodes mockRepo base method.
+func (m *MockCampaignRepository) ViewCampaignCodes(arg0 context.Context, arg1 int) ([]model.CampaignCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewCampaignCodes", arg0, arg1)
+	ret0, _ := ret[0].([]model.CampaignCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewCampaignCodes indicates an expected call of ViewCampaignCodes.
+func (mr *MockCampaignRepositoryMockRecorder) ViewCampaignCodes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewCampaignCodes", reflect.TypeOf((*MockCampaignRepository)(nil).ViewCampaignCodes), arg0, arg1)
+}
+
+// ViewCampaigns mockRepo base method.
+func (m *MockCampaignRepository) ViewCampaigns(arg0 context.Context) ([]model.CouponCampaignReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewCampaigns", arg0)
+	ret0, _ := ret[0].([]model.CouponCampaignReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewCampaigns indicates an expected call of ViewCampaigns.
+func (mr *MockCampaignRepositoryMockRecorder) ViewCampaigns(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewCampaigns", reflect.TypeOf((*MockCampaignRepository)(nil).ViewCampaigns), arg0)
+}
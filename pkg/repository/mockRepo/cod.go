@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/interface (interfaces: CODRepository)
+
+// Package mockRepo is a generated GoMock package.
+package mockRepo
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	model "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCODRepository is a mockRepo of CODRepository interface.
+type MockCODRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCODRepositoryMockRecorder
+}
+
+// MockCODRepositoryMockRecorder is the mockRepo recorder for MockCODRepository.
+type MockCODRepositoryMockRecorder struct {
+	mock *MockCODRepository
+}
+
+// NewMockCODRepository creates a new mockRepo instance.
+func NewMockCODRepository(ctrl *gomock.Controller) *MockCODRepository {
+	mock := &MockCODRepository{ctrl: ctrl}
+	mock.recorder = &MockCODRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCODRepository) EXPECT() *MockCODRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddCODPincode mockRepo base method.
+func (m *MockCODRepository) AddCODPincode(arg0 context.Context, arg1 string) (domain.CODPincode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCODPincode", arg0, arg1)
+	ret0, _ := ret[0].(domain.CODPincode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCODPincode indicates an expected call of AddCODPincode.
+func (mr *MockCODRepositoryMockRecorder) AddCODPincode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCODPincode", reflect.TypeOf((*MockCODRepository)(nil).AddCODPincode), arg0, arg1)
+}
+
+// RecordCODCollection mockRepo base method.
+func (m *MockCODRepository) RecordCODCollection(arg0 context.Context, arg1 model.CODCollection, arg2 int) (domain.CODCollection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordCODCollection", arg0, arg1, arg2)
+	ret0, _ := ret[0].(domain.CODCollection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordCODCollection indicates an expected call of RecordCODCollection.
+func (mr *MockCODRepositoryMockRecorder) RecordCODCollection(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordCODCollection", reflect.TypeOf((*MockCODRepository)(nil).RecordCODCollection), arg0, arg1, arg2)
+}
+
+// RemoveCODPincode mockRepo base method.
+func (m *MockCODRepository) RemoveCODPincode(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCODPincode", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveCODPincode indicates an expected call of RemoveCODPincode.
+func (mr *MockCODRepositoryMockRecorder) RemoveCODPincode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCODPincode", reflect.TypeOf((*MockCODRepository)(nil).RemoveCODPincode), arg0, arg1)
+}
+
+// UpdateCODRules mockRepo base method.
+func (m *MockCODRepository) UpdateCODRules(arg0 context.Context, arg1 model.UpdateCODRules) (domain.CODRules, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCODRules", arg0, arg1)
+	ret0, _ := ret[0].(domain.CODRules)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCODRules indicates an expected call of UpdateCODRules.
+func (mr *MockCODRepositoryMockRecorder) UpdateCODRules(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCODRules", reflect.TypeOf((*MockCODRepository)(nil).UpdateCODRules), arg0, arg1)
+}
+
+// ViewCODRules mockRepo base method.
+func (m *MockCODRepository) ViewCODRules(arg0 context.Context) (model.CODRules, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewCODRules", arg0)
+	ret0, _ := ret[0].(model.CODRules)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewCODRules indicates an expected call of ViewCODRules.
+func (mr *MockCODRepositoryMockRecorder) ViewCODRules(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewCODRules", reflect.TypeOf((*MockCODRepository)(nil).ViewCODRules), arg0)
+}
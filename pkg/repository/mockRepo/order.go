@@ -36,6 +36,22 @@ func (m *MockOrderRepository) EXPECT() *MockOrderRepositoryMockRecorder {
 	return m.recorder
 }
 
+// ApproveReturn mockRepo base method.
+func (m *MockOrderRepository) ApproveReturn(arg0 context.Context, arg1 int, arg2 string) (domain.Return, domain.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveReturn", arg0, arg1, arg2)
+	ret0, _ := ret[0].(domain.Return)
+	ret1, _ := ret[1].(domain.Refund)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ApproveReturn indicates an expected call of ApproveReturn.
+func (mr *MockOrderRepositoryMockRecorder) ApproveReturn(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveReturn", reflect.TypeOf((*MockOrderRepository)(nil).ApproveReturn), arg0, arg1, arg2)
+}
+
 // BuyAll mockRepo base method.
 func (m *MockOrderRepository) BuyAll(arg0 context.Context, arg1 int, arg2 model.PlaceAllOrders) (domain.Order, error) {
 	m.ctrl.T.Helper()
@@ -67,12 +83,13 @@ func (mr *MockOrderRepositoryMockRecorder) BuyProductItem(arg0, arg1, arg2 inter
 }
 
 // CancelOrder mockRepo base method.
-func (m *MockOrderRepository) CancelOrder(arg0 context.Context, arg1, arg2 int) (domain.Order, error) {
+func (m *MockOrderRepository) CancelOrder(arg0 context.Context, arg1, arg2 int) (domain.Order, domain.Refund, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CancelOrder", arg0, arg1, arg2)
 	ret0, _ := ret[0].(domain.Order)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(domain.Refund)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // CancelOrder indicates an expected call of CancelOrder.
@@ -81,6 +98,96 @@ func (mr *MockOrderRepositoryMockRecorder) CancelOrder(arg0, arg1, arg2 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOrder", reflect.TypeOf((*MockOrderRepository)(nil).CancelOrder), arg0, arg1, arg2)
 }
 
+// FindOrderByID mockRepo base method.
+func (m *MockOrderRepository) FindOrderByID(arg0 context.Context, arg1 int) (domain.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrderByID", arg0, arg1)
+	ret0, _ := ret[0].(domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOrderByID indicates an expected call of FindOrderByID.
+func (mr *MockOrderRepositoryMockRecorder) FindOrderByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrderByID", reflect.TypeOf((*MockOrderRepository)(nil).FindOrderByID), arg0, arg1)
+}
+
+// FindReturnByOrderID mockRepo base method.
+func (m *MockOrderRepository) FindReturnByOrderID(arg0 context.Context, arg1 int) (domain.Return, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindReturnByOrderID", arg0, arg1)
+	ret0, _ := ret[0].(domain.Return)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindReturnByOrderID indicates an expected call of FindReturnByOrderID.
+func (mr *MockOrderRepositoryMockRecorder) FindReturnByOrderID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindReturnByOrderID", reflect.TypeOf((*MockOrderRepository)(nil).FindReturnByOrderID), arg0, arg1)
+}
+
+// ListReturns mockRepo base method.
+func (m *MockOrderRepository) ListReturns(arg0 context.Context, arg1 string) ([]domain.Return, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReturns", arg0, arg1)
+	ret0, _ := ret[0].([]domain.Return)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReturns indicates an expected call of ListReturns.
+func (mr *MockOrderRepositoryMockRecorder) ListReturns(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReturns", reflect.TypeOf((*MockOrderRepository)(nil).ListReturns), arg0, arg1)
+}
+
+// RejectReturn mockRepo base method.
+func (m *MockOrderRepository) RejectReturn(arg0 context.Context, arg1 int, arg2 string) (domain.Return, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectReturn", arg0, arg1, arg2)
+	ret0, _ := ret[0].(domain.Return)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RejectReturn indicates an expected call of RejectReturn.
+func (mr *MockOrderRepositoryMockRecorder) RejectReturn(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectReturn", reflect.TypeOf((*MockOrderRepository)(nil).RejectReturn), arg0, arg1, arg2)
+}
+
+// ReturnPickedUp mockRepo base method.
+func (m *MockOrderRepository) ReturnPickedUp(arg0 context.Context, arg1 int) (domain.Return, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReturnPickedUp", arg0, arg1)
+	ret0, _ := ret[0].(domain.Return)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReturnPickedUp indicates an expected call of ReturnPickedUp.
+func (mr *MockOrderRepositoryMockRecorder) ReturnPickedUp(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReturnPickedUp", reflect.TypeOf((*MockOrderRepository)(nil).ReturnPickedUp), arg0, arg1)
+}
+
+// ReturnReceived mockRepo base method.
+func (m *MockOrderRepository) ReturnReceived(arg0 context.Context, arg1 int) (domain.Return, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReturnReceived", arg0, arg1)
+	ret0, _ := ret[0].(domain.Return)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReturnReceived indicates an expected call of ReturnReceived.
+func (mr *MockOrderRepositoryMockRecorder) ReturnReceived(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReturnReceived", reflect.TypeOf((*MockOrderRepository)(nil).ReturnReceived), arg0, arg1)
+}
+
 // ReturnRequest mockRepo base method.
 func (m *MockOrderRepository) ReturnRequest(arg0 context.Context, arg1 model.ReturnRequest) (domain.Order, error) {
 	m.ctrl.T.Helper()
@@ -97,18 +204,19 @@ func (mr *MockOrderRepositoryMockRecorder) ReturnRequest(arg0, arg1 interface{})
 }
 
 // UpdateOrder mockRepo base method.
-func (m *MockOrderRepository) UpdateOrder(arg0 context.Context, arg1 model.UpdateOrder) (domain.Order, error) {
+func (m *MockOrderRepository) UpdateOrder(arg0 context.Context, arg1 domain.Order, arg2 model.UpdateOrder) (domain.Order, domain.Refund, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateOrder", arg0, arg1)
+	ret := m.ctrl.Call(m, "UpdateOrder", arg0, arg1, arg2)
 	ret0, _ := ret[0].(domain.Order)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(domain.Refund)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // UpdateOrder indicates an expected call of UpdateOrder.
-func (mr *MockOrderRepositoryMockRecorder) UpdateOrder(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockOrderRepositoryMockRecorder) UpdateOrder(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrder", reflect.TypeOf((*MockOrderRepository)(nil).UpdateOrder), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrder", reflect.TypeOf((*MockOrderRepository)(nil).UpdateOrder), arg0, arg1, arg2)
 }
 
 // ViewAllOrders mockRepo base method.
@@ -140,3 +248,18 @@ func (mr *MockOrderRepositoryMockRecorder) ViewOrderById(arg0, arg1, arg2 interf
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewOrderById", reflect.TypeOf((*MockOrderRepository)(nil).ViewOrderById), arg0, arg1, arg2)
 }
+
+// ViewOrderStatusHistory mockRepo base method.
+func (m *MockOrderRepository) ViewOrderStatusHistory(arg0 context.Context, arg1 int) ([]domain.OrderStatusHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewOrderStatusHistory", arg0, arg1)
+	ret0, _ := ret[0].([]domain.OrderStatusHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewOrderStatusHistory indicates an expected call of ViewOrderStatusHistory.
+func (mr *MockOrderRepositoryMockRecorder) ViewOrderStatusHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewOrderStatusHistory", reflect.TypeOf((*MockOrderRepository)(nil).ViewOrderStatusHistory), arg0, arg1)
+}
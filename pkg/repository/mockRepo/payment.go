@@ -0,0 +1,186 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/interface (interfaces: PaymentRepository)
+
+// Package mockRepo is a generated GoMock package.
+package mockRepo
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPaymentRepository is a mockRepo of PaymentRepository interface.
+type MockPaymentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentRepositoryMockRecorder
+}
+
+// MockPaymentRepositoryMockRecorder is the mockRepo recorder for MockPaymentRepository.
+type MockPaymentRepositoryMockRecorder struct {
+	mock *MockPaymentRepository
+}
+
+// NewMockPaymentRepository creates a new mockRepo instance.
+func NewMockPaymentRepository(ctrl *gomock.Controller) *MockPaymentRepository {
+	mock := &MockPaymentRepository{ctrl: ctrl}
+	mock.recorder = &MockPaymentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentRepository) EXPECT() *MockPaymentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CapturePayment mockRepo base method.
+func (m *MockPaymentRepository) CapturePayment(arg0 context.Context, arg1 string, arg2 int, arg3 string) (domain.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CapturePayment", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(domain.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CapturePayment indicates an expected call of CapturePayment.
+func (mr *MockPaymentRepositoryMockRecorder) CapturePayment(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CapturePayment", reflect.TypeOf((*MockPaymentRepository)(nil).CapturePayment), arg0, arg1, arg2, arg3)
+}
+
+// ExpirePayment mockRepo base method.
+func (m *MockPaymentRepository) ExpirePayment(arg0 context.Context, arg1 int) (domain.Order, domain.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpirePayment", arg0, arg1)
+	ret0, _ := ret[0].(domain.Order)
+	ret1, _ := ret[1].(domain.Refund)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ExpirePayment indicates an expected call of ExpirePayment.
+func (mr *MockPaymentRepositoryMockRecorder) ExpirePayment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpirePayment", reflect.TypeOf((*MockPaymentRepository)(nil).ExpirePayment), arg0, arg1)
+}
+
+// FailPayment mockRepo base method.
+func (m *MockPaymentRepository) FailPayment(arg0 context.Context, arg1 string, arg2 int, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailPayment", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailPayment indicates an expected call of FailPayment.
+func (mr *MockPaymentRepositoryMockRecorder) FailPayment(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailPayment", reflect.TypeOf((*MockPaymentRepository)(nil).FailPayment), arg0, arg1, arg2, arg3)
+}
+
+// FailRefund mockRepo base method.
+func (m *MockPaymentRepository) FailRefund(arg0 context.Context, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailRefund", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailRefund indicates an expected call of FailRefund.
+func (mr *MockPaymentRepositoryMockRecorder) FailRefund(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailRefund", reflect.TypeOf((*MockPaymentRepository)(nil).FailRefund), arg0, arg1, arg2, arg3)
+}
+
+// FindStalePayments mockRepo base method.
+func (m *MockPaymentRepository) FindStalePayments(arg0 context.Context, arg1 time.Time) ([]domain.PaymentDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindStalePayments", arg0, arg1)
+	ret0, _ := ret[0].([]domain.PaymentDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindStalePayments indicates an expected call of FindStalePayments.
+func (mr *MockPaymentRepositoryMockRecorder) FindStalePayments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindStalePayments", reflect.TypeOf((*MockPaymentRepository)(nil).FindStalePayments), arg0, arg1)
+}
+
+// MarkPaymentFailed mockRepo base method.
+func (m *MockPaymentRepository) MarkPaymentFailed(arg0 context.Context, arg1 int) (domain.PaymentDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkPaymentFailed", arg0, arg1)
+	ret0, _ := ret[0].(domain.PaymentDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkPaymentFailed indicates an expected call of MarkPaymentFailed.
+func (mr *MockPaymentRepositoryMockRecorder) MarkPaymentFailed(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkPaymentFailed", reflect.TypeOf((*MockPaymentRepository)(nil).MarkPaymentFailed), arg0, arg1)
+}
+
+// ProcessRefund mockRepo base method.
+func (m *MockPaymentRepository) ProcessRefund(arg0 context.Context, arg1, arg2, arg3 string, arg4 float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessRefund", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProcessRefund indicates an expected call of ProcessRefund.
+func (mr *MockPaymentRepositoryMockRecorder) ProcessRefund(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessRefund", reflect.TypeOf((*MockPaymentRepository)(nil).ProcessRefund), arg0, arg1, arg2, arg3, arg4)
+}
+
+// SetGatewayOrderID mockRepo base method.
+func (m *MockPaymentRepository) SetGatewayOrderID(arg0 context.Context, arg1 int, arg2 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetGatewayOrderID", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetGatewayOrderID indicates an expected call of SetGatewayOrderID.
+func (mr *MockPaymentRepositoryMockRecorder) SetGatewayOrderID(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetGatewayOrderID", reflect.TypeOf((*MockPaymentRepository)(nil).SetGatewayOrderID), arg0, arg1, arg2)
+}
+
+// UpdatePaymentDetails mockRepo base method.
+func (m *MockPaymentRepository) UpdatePaymentDetails(arg0 context.Context, arg1 int, arg2 string) (domain.PaymentDetails, domain.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePaymentDetails", arg0, arg1, arg2)
+	ret0, _ := ret[0].(domain.PaymentDetails)
+	ret1, _ := ret[1].(domain.Refund)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// UpdatePaymentDetails indicates an expected call of UpdatePaymentDetails.
+func (mr *MockPaymentRepositoryMockRecorder) UpdatePaymentDetails(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePaymentDetails", reflect.TypeOf((*MockPaymentRepository)(nil).UpdatePaymentDetails), arg0, arg1, arg2)
+}
+
+// ViewPaymentDetails mockRepo base method.
+func (m *MockPaymentRepository) ViewPaymentDetails(arg0 context.Context, arg1 int) (domain.PaymentDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewPaymentDetails", arg0, arg1)
+	ret0, _ := ret[0].(domain.PaymentDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewPaymentDetails indicates an expected call of ViewPaymentDetails.
+func (mr *MockPaymentRepositoryMockRecorder) ViewPaymentDetails(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewPaymentDetails", reflect.TypeOf((*MockPaymentRepository)(nil).ViewPaymentDetails), arg0, arg1)
+}
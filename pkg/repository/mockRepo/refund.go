@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/interface (interfaces: RefundRepository)
+
+// Package mockRepo is a generated GoMock package.
+package mockRepo
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	model "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRefundRepository is a mockRepo of RefundRepository interface.
+type MockRefundRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRefundRepositoryMockRecorder
+}
+
+// MockRefundRepositoryMockRecorder is the mockRepo recorder for MockRefundRepository.
+type MockRefundRepositoryMockRecorder struct {
+	mock *MockRefundRepository
+}
+
+// NewMockRefundRepository creates a new mockRepo instance.
+func NewMockRefundRepository(ctrl *gomock.Controller) *MockRefundRepository {
+	mock := &MockRefundRepository{ctrl: ctrl}
+	mock.recorder = &MockRefundRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRefundRepository) EXPECT() *MockRefundRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateRefund mockRepo base method.
+func (m *MockRefundRepository) CreateRefund(arg0 context.Context, arg1 model.CreateRefund) (domain.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefund", arg0, arg1)
+	ret0, _ := ret[0].(domain.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRefund indicates an expected call of CreateRefund.
+func (mr *MockRefundRepositoryMockRecorder) CreateRefund(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefund", reflect.TypeOf((*MockRefundRepository)(nil).CreateRefund), arg0, arg1)
+}
+
+// ListRefunds mockRepo base method.
+func (m *MockRefundRepository) ListRefunds(arg0 context.Context, arg1 string) ([]domain.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRefunds", arg0, arg1)
+	ret0, _ := ret[0].([]domain.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRefunds indicates an expected call of ListRefunds.
+func (mr *MockRefundRepositoryMockRecorder) ListRefunds(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRefunds", reflect.TypeOf((*MockRefundRepository)(nil).ListRefunds), arg0, arg1)
+}
+
+// MarkRefundFailed mockRepo base method.
+func (m *MockRefundRepository) MarkRefundFailed(arg0 context.Context, arg1 uint, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRefundFailed", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRefundFailed indicates an expected call of MarkRefundFailed.
+func (mr *MockRefundRepositoryMockRecorder) MarkRefundFailed(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRefundFailed", reflect.TypeOf((*MockRefundRepository)(nil).MarkRefundFailed), arg0, arg1, arg2)
+}
+
+// RetryRefund mockRepo base method.
+func (m *MockRefundRepository) RetryRefund(arg0 context.Context, arg1 int) (domain.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryRefund", arg0, arg1)
+	ret0, _ := ret[0].(domain.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetryRefund indicates an expected call of RetryRefund.
+func (mr *MockRefundRepositoryMockRecorder) RetryRefund(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryRefund", reflect.TypeOf((*MockRefundRepository)(nil).RetryRefund), arg0, arg1)
+}
+
+// SetRefundRef mockRepo base method.
+func (m *MockRefundRepository) SetRefundRef(arg0 context.Context, arg1 uint, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRefundRef", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRefundRef indicates an expected call of SetRefundRef.
+func (mr *MockRefundRepositoryMockRecorder) SetRefundRef(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRefundRef", reflect.TypeOf((*MockRefundRepository)(nil).SetRefundRef), arg0, arg1, arg2)
+}
+
+// ViewOrderRefunds mockRepo base method.
+func (m *MockRefundRepository) ViewOrderRefunds(arg0 context.Context, arg1 int) ([]domain.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewOrderRefunds", arg0, arg1)
+	ret0, _ := ret[0].([]domain.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewOrderRefunds indicates an expected call of ViewOrderRefunds.
+func (mr *MockRefundRepositoryMockRecorder) ViewOrderRefunds(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewOrderRefunds", reflect.TypeOf((*MockRefundRepository)(nil).ViewOrderRefunds), arg0, arg1)
+}
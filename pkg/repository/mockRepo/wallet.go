@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/interface (interfaces: WalletRepository)
+
+// Package mockRepo is a generated GoMock package.
+package mockRepo
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	model "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockWalletRepository is a mockRepo of WalletRepository interface.
+type MockWalletRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWalletRepositoryMockRecorder
+}
+
+// MockWalletRepositoryMockRecorder is the mockRepo recorder for MockWalletRepository.
+type MockWalletRepositoryMockRecorder struct {
+	mock *MockWalletRepository
+}
+
+// NewMockWalletRepository creates a new mockRepo instance.
+func NewMockWalletRepository(ctrl *gomock.Controller) *MockWalletRepository {
+	mock := &MockWalletRepository{ctrl: ctrl}
+	mock.recorder = &MockWalletRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWalletRepository) EXPECT() *MockWalletRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AdjustWallet mockRepo base method.
+func (m *MockWalletRepository) AdjustWallet(arg0 context.Context, arg1 model.WalletAdjustment, arg2 int) (domain.WalletEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdjustWallet", arg0, arg1, arg2)
+	ret0, _ := ret[0].(domain.WalletEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdjustWallet indicates an expected call of AdjustWallet.
+func (mr *MockWalletRepositoryMockRecorder) AdjustWallet(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdjustWallet", reflect.TypeOf((*MockWalletRepository)(nil).AdjustWallet), arg0, arg1, arg2)
+}
+
+// ViewWallet mockRepo base method.
+func (m *MockWalletRepository) ViewWallet(arg0 context.Context, arg1 int) (model.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewWallet", arg0, arg1)
+	ret0, _ := ret[0].(model.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewWallet indicates an expected call of ViewWallet.
+func (mr *MockWalletRepositoryMockRecorder) ViewWallet(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewWallet", reflect.TypeOf((*MockWalletRepository)(nil).ViewWallet), arg0, arg1)
+}
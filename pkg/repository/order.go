@@ -4,11 +4,17 @@ import (
 	"context"
 	"fmt"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/pricing"
 	interfaces "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/interface"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
 	"gorm.io/gorm"
+	"sort"
+	"time"
 )
 
+// errOrderStatusChanged is returned when the status of an order changed between reading and updating it
+var errOrderStatusChanged = &domain.OrderStatusError{Reason: "order status was changed by another request, please try again"}
+
 type orderDatabase struct {
 	DB *gorm.DB
 }
@@ -20,82 +26,131 @@ func NewOrderRepository(DB *gorm.DB) interfaces.OrderRepository {
 func (c *orderDatabase) BuyProductItem(ctx context.Context, userID int, orderInfo model.PlaceOrder) (domain.Order, error) {
 
 	tx := c.DB.Begin()
-	// finding product price and qnty
+	// finding product price and qnty. The product item row is locked until the order is placed, so that
+	// concurrent checkouts of the same item wait for this one instead of reading a stale stock count
 	var productItem struct {
 		Price       float64
 		QntyInStock int
+		MaxPerOrder int
+		CategoryID  uint
+		BrandID     uint
+		TaxPercent  float64
 	}
 
-	fetchPriceQuery := `SELECT price, qnty_in_stock FROM product_items WHERE id = $1`
+	fetchPriceQuery := `	SELECT pi.price, pi.qnty_in_stock, pi.max_per_order, p.product_category_id AS category_id, p.brand_id,
+								COALESCE(pc.tax_percent, 0) AS tax_percent
+							FROM product_items pi
+							JOIN products p ON p.id = pi.product_id
+							LEFT JOIN product_categories pc ON pc.id = p.product_category_id
+							WHERE pi.id = $1
+							FOR UPDATE OF pi`
 
-	err := tx.Raw(fetchPriceQuery, orderInfo.ProductItemID).Scan(&productItem).Error
-	if err != nil {
+	result := tx.Raw(fetchPriceQuery, orderInfo.ProductItemID).Scan(&productItem)
+	if result.Error != nil {
 		tx.Rollback()
-		return domain.Order{}, err
+		return domain.Order{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return domain.Order{}, fmt.Errorf("no such product item found")
 	}
 
-	//if stock is empty
-	if productItem.QntyInStock < 1 {
+	//if stock is not enough for the ordered quantity
+	if productItem.QntyInStock < orderInfo.Quantity {
+		tx.Rollback()
+		return domain.Order{}, &domain.OutOfStockError{ProductItemID: uint(orderInfo.ProductItemID), Requested: orderInfo.Quantity}
+	}
+	if productItem.MaxPerOrder > 0 && orderInfo.Quantity > productItem.MaxPerOrder {
 		tx.Rollback()
-		return domain.Order{}, fmt.Errorf("product item out of stock")
+		return domain.Order{}, fmt.Errorf("only %v of this product item can be bought per order", productItem.MaxPerOrder)
 	}
 
 	//fetch coupon details
-	var couponInfo domain.Coupon
-	fetchCouponQuery := `SELECT * FROM coupons WHERE id = $1;`
-	err = tx.Raw(fetchCouponQuery, orderInfo.CouponID).Scan(&couponInfo).Error
+	var coupon *pricing.Coupon
+	if orderInfo.CouponID != 0 {
+		var err error
+		coupon, err = findPricingCoupon(tx, orderInfo.CouponID, uint(userID))
+		if err != nil {
+			tx.Rollback()
+			return domain.Order{}, err
+		}
+	}
 
+	//the order line is priced the same way as a cart with the one item
+	quote := pricing.Price([]pricing.Line{{
+		ProductItemID: uint(orderInfo.ProductItemID),
+		CategoryID:    productItem.CategoryID,
+		BrandID:       productItem.BrandID,
+		Quantity:      uint(orderInfo.Quantity),
+		UnitPrice:     productItem.Price,
+		TaxPercent:    productItem.TaxPercent,
+	}}, uint(userID), coupon, time.Now())
+	if quote.CouponRemoved != "" {
+		tx.Rollback()
+		return domain.Order{}, fmt.Errorf("cannot apply coupon: %v", quote.CouponRemoved)
+	}
+	orderTotal := quote.Total
+	lineTotal := quote.Lines[0].LineTotal
+
+	//take a snapshot of the shipping address
+	shippingAddress, err := findShippingAddress(tx, userID, orderInfo.ShippingAddressID)
 	if err != nil {
 		tx.Rollback()
 		return domain.Order{}, err
 	}
 
-	if productItem.Price < couponInfo.MinOrderValue {
+	paymentMethodID, walletAmount, err := walletPayment(tx, uint(userID), orderTotal, orderInfo.PaymentMethodID, orderInfo.UseWallet)
+	if err != nil {
 		tx.Rollback()
-		return domain.Order{}, fmt.Errorf("cannot apply coupon as order values is less than required")
+		return domain.Order{}, err
 	}
-
-	discountAmount := productItem.Price * (couponInfo.DiscountPercent / 100)
-	if discountAmount > couponInfo.DiscountMaxAmount {
-		discountAmount = couponInfo.DiscountMaxAmount
+	if paymentMethodID == domain.PaymentCOD {
+		if err := checkCODEligibility(tx, uint(userID), orderTotal, shippingAddress.Pincode); err != nil {
+			tx.Rollback()
+			return domain.Order{}, err
+		}
 	}
-	orderTotal := productItem.Price - discountAmount
 
 	var orderDetails domain.Order
 
-	createOrderQuery := `	INSERT INTO orders (user_id,order_date,payment_method_id,shipping_address_id,order_total,order_status_id, coupon_id)
-							VALUES($1, NOW(), $2, $3, $4, 1, $5) RETURNING *;`
+	createOrderQuery := `	INSERT INTO orders (user_id,order_date,payment_method_id,shipping_address_id,order_total,order_status_id, delivery_status_id, coupon_id,
+								shipping_house_number, shipping_street, shipping_city, shipping_district, shipping_pincode, shipping_landmark, shipping_label)
+							VALUES($1, NOW(), $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING *;`
 
-	err = tx.Raw(createOrderQuery, userID, orderInfo.PaymentMethodID, orderInfo.ShippingAddressID, orderTotal, orderInfo.CouponID).Scan(&orderDetails).Error
+	err = tx.Raw(createOrderQuery, userID, paymentMethodID, orderInfo.ShippingAddressID, orderTotal, domain.OrderPending, domain.DeliveryPending, orderInfo.CouponID,
+		shippingAddress.HouseNumber, shippingAddress.Street, shippingAddress.City, shippingAddress.District, shippingAddress.Pincode, shippingAddress.Landmark, shippingAddress.Label).Scan(&orderDetails).Error
 	if err != nil {
 		tx.Rollback()
 		return domain.Order{}, err
 	}
+	if err := recordOrderStatus(tx, orderDetails, "user"); err != nil {
+		tx.Rollback()
+		return domain.Order{}, err
+	}
+	if quote.CouponID != 0 {
+		if err := redeemCoupon(tx, quote.CouponID, uint(userID), orderDetails.ID, quote.Discount); err != nil {
+			tx.Rollback()
+			return domain.Order{}, err
+		}
+	}
 
-	createOrderLineQuery := `	INSERT INTO order_lines (product_item_id,order_id,quantity,price)
-								VALUES ($1, $2, 1, $3);`
+	createOrderLineQuery := `	INSERT INTO order_lines (product_item_id, order_id, quantity, unit_price, line_total)
+								VALUES ($1, $2, $3, $4, $5);`
 
-	err = tx.Exec(createOrderLineQuery, orderInfo.ProductItemID, orderDetails.ID, orderDetails.OrderTotal).Error
+	err = tx.Exec(createOrderLineQuery, orderInfo.ProductItemID, orderDetails.ID, orderInfo.Quantity, productItem.Price, lineTotal).Error
 	if err != nil {
 		tx.Rollback()
 		return domain.Order{}, err
 	}
-	//reduce the stock quantity of the product item by 1
-	updateQtyQuery := `	UPDATE product_items
-						SET qnty_in_stock = qnty_in_stock -1 
-						WHERE qnty_in_stock > 0 
-						AND id = $1`
-	err = tx.Exec(updateQtyQuery, orderInfo.ProductItemID).Error
+	//reduce the stock quantity of the product item by the ordered quantity
+	err = reserveStock(tx, orderInfo.ProductItemID, orderInfo.Quantity, orderDetails.ID)
 	if err != nil {
 		tx.Rollback()
 		return domain.Order{}, err
 	}
 
 	//create an entry in the payment_details table
-	createPaymentEntry := `	INSERT INTO payment_details (order_id, order_total,payment_method_id, payment_status_id, updated_at) 	
-							VALUES ($1, $2,$3, 1,NOW());`
-	err = tx.Exec(createPaymentEntry, orderDetails.ID, orderDetails.OrderTotal, orderDetails.PaymentMethodID).Error
-	if err != nil {
+	if err := createPayment(tx, orderDetails, walletAmount); err != nil {
 		tx.Rollback()
 		return domain.Order{}, err
 	}
@@ -106,14 +161,11 @@ func (c *orderDatabase) BuyProductItem(ctx context.Context, userID int, orderInf
 
 func (c *orderDatabase) BuyAll(ctx context.Context, userID int, orderInfo model.PlaceAllOrders) (domain.Order, error) {
 	tx := c.DB.Begin()
-	var cartDetails struct {
-		ID    int
-		Total float64
-	}
-	findCart := `SELECT id, total FROM carts WHERE user_id = $1`
-	err := tx.Raw(findCart, userID).Scan(&cartDetails).Error
+	var cartID int
+	findCart := `SELECT id FROM carts WHERE user_id = $1`
+	err := tx.Raw(findCart, userID).Scan(&cartID).Error
 
-	if cartDetails.ID == 0 {
+	if cartID == 0 {
 		tx.Rollback()
 		return domain.Order{}, fmt.Errorf("no items in cart")
 	}
@@ -123,24 +175,98 @@ func (c *orderDatabase) BuyAll(ctx context.Context, userID int, orderInfo model.
 	}
 	var cartItems []domain.CartItems
 	fetchCartItemsQuery := `SELECT * FROM cart_items WHERE cart_id = $1`
-	err = tx.Raw(fetchCartItemsQuery, cartDetails.ID).Scan(&cartItems).Error
+	err = tx.Raw(fetchCartItemsQuery, cartID).Scan(&cartItems).Error
 
 	if len(cartItems) == 0 {
 		tx.Rollback()
 		return domain.Order{}, fmt.Errorf("nothing in cart")
 	}
 
+	// product items are locked in the order of their ids, so that two carts sharing items cannot deadlock
+	sort.Slice(cartItems, func(i, j int) bool {
+		return cartItems[i].ProductItemID < cartItems[j].ProductItemID
+	})
+
+	for i := range cartItems {
+		//check if product is in stock and fetch product
+		var productDetails struct {
+			QntyInStock int
+			Price       float64
+			MaxPerOrder int
+		}
+
+		fetchDetailsQuery := ` SELECT qnty_in_stock, price, max_per_order FROM product_items WHERE id = $1 FOR UPDATE`
+		err := tx.Raw(fetchDetailsQuery, cartItems[i].ProductItemID).Scan(&productDetails).Error
+		if err != nil {
+			tx.Rollback()
+			return domain.Order{}, err
+		}
+
+		//if product is out of stock
+		if productDetails.QntyInStock < int(cartItems[i].Quantity) {
+			tx.Rollback()
+			return domain.Order{}, &domain.OutOfStockError{ProductItemID: cartItems[i].ProductItemID, Requested: int(cartItems[i].Quantity)}
+		}
+		//a changed price has to be accepted in the cart first, so that the user is not charged a price they have not seen
+		if reason := cartItemIssue(productDetails.Price, cartItems[i].PriceWhenAdded, productDetails.QntyInStock, productDetails.MaxPerOrder, cartItems[i].Quantity); reason != "" {
+			tx.Rollback()
+			return domain.Order{}, &domain.CartItemError{ProductItemID: cartItems[i].ProductItemID, Reason: reason}
+		}
+	}
+
+	//the order is priced from the locked product items and the current rules of the coupon
+	quote, err := priceCart(tx, cartID)
+	if err != nil {
+		tx.Rollback()
+		return domain.Order{}, err
+	}
+	if quote.CouponRemoved != "" {
+		tx.Rollback()
+		return domain.Order{}, fmt.Errorf("coupon of the cart no longer applies: %v", quote.CouponRemoved)
+	}
+
+	//take a snapshot of the shipping address
+	shippingAddress, err := findShippingAddress(tx, userID, orderInfo.ShippingAddressID)
+	if err != nil {
+		tx.Rollback()
+		return domain.Order{}, err
+	}
+
+	paymentMethodID, walletAmount, err := walletPayment(tx, uint(userID), quote.Total, orderInfo.PaymentMethodID, orderInfo.UseWallet)
+	if err != nil {
+		tx.Rollback()
+		return domain.Order{}, err
+	}
+	if paymentMethodID == domain.PaymentCOD {
+		if err := checkCODEligibility(tx, uint(userID), quote.Total, shippingAddress.Pincode); err != nil {
+			tx.Rollback()
+			return domain.Order{}, err
+		}
+	}
+
 	var createdOrder domain.Order
-	createOrderQuery := `	INSERT INTO orders (user_id, order_date, payment_method_id, shipping_address_id, order_total, order_status_id)
-							VALUES($1, NOW(), $2, $3, $4,1) RETURNING *;`
-	err = tx.Raw(createOrderQuery, userID, orderInfo.PaymentMethodID, orderInfo.ShippingAddressID, cartDetails.Total).Scan(&createdOrder).Error
+	createOrderQuery := `	INSERT INTO orders (user_id, order_date, payment_method_id, shipping_address_id, order_total, order_status_id, delivery_status_id, coupon_id,
+								shipping_house_number, shipping_street, shipping_city, shipping_district, shipping_pincode, shipping_landmark, shipping_label)
+							VALUES($1, NOW(), $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING *;`
+	err = tx.Raw(createOrderQuery, userID, paymentMethodID, orderInfo.ShippingAddressID, quote.Total, domain.OrderPending, domain.DeliveryPending, quote.CouponID,
+		shippingAddress.HouseNumber, shippingAddress.Street, shippingAddress.City, shippingAddress.District, shippingAddress.Pincode, shippingAddress.Landmark, shippingAddress.Label).Scan(&createdOrder).Error
 	if err != nil {
 		tx.Rollback()
 		return domain.Order{}, err
 	}
+	if err := recordOrderStatus(tx, createdOrder, "user"); err != nil {
+		tx.Rollback()
+		return domain.Order{}, err
+	}
+	if quote.CouponID != 0 {
+		if err := redeemCoupon(tx, quote.CouponID, uint(userID), createdOrder.ID, quote.Discount); err != nil {
+			tx.Rollback()
+			return domain.Order{}, err
+		}
+	}
 
 	//update carts table
-	updateCartQuery := `UPDATE carts SET coupon_id = 0, sub_total = 0, discount = 0, total = 0 WHERE user_id = $1`
+	updateCartQuery := `UPDATE carts SET coupon_id = 0, coupon_removed = '', sub_total = 0, discount = 0, tax = 0, total = 0 WHERE user_id = $1`
 	err = tx.Exec(updateCartQuery, userID).Error
 	if err != nil {
 		tx.Rollback()
@@ -149,54 +275,29 @@ func (c *orderDatabase) BuyAll(ctx context.Context, userID int, orderInfo model.
 
 	//update cart_items table
 	deleteCartItemRowsQuery := `DELETE FROM cart_items WHERE cart_id = $1;`
-	err = tx.Exec(deleteCartItemRowsQuery, cartDetails.ID).Error
+	err = tx.Exec(deleteCartItemRowsQuery, cartID).Error
 	if err != nil {
 		tx.Rollback()
 		return domain.Order{}, err
 	}
 
 	//create an entry in the payment_details table
-	createPaymentEntry := `	INSERT INTO payment_details (order_id, order_total,payment_method_id, payment_status_id, updated_at) 	
-							VALUES ($1, $2,$3, 1,NOW());`
-	err = tx.Exec(createPaymentEntry, createdOrder.ID, createdOrder.OrderTotal, createdOrder.PaymentMethodID).Error
-	if err != nil {
+	if err := createPayment(tx, createdOrder, walletAmount); err != nil {
 		tx.Rollback()
 		return domain.Order{}, err
 	}
 
-	createOrderLineQuery := `	INSERT INTO order_lines (product_item_id, order_id, quantity, price) VALUES($1, $2, $3, $4);`
-
-	for i := range cartItems {
-		//check if product is in stock and fetch product
-		var productDetails struct {
-			QntyInStock int
-			Price       float64
-		}
-
-		fetchDetailsQuery := ` SELECT qnty_in_stock, price FROM product_items WHERE id = $1`
-		err := tx.Raw(fetchDetailsQuery, cartItems[i].ProductItemID).Scan(&productDetails).Error
-		if err != nil {
-			tx.Rollback()
-			return domain.Order{}, err
-		}
-
-		//if product is out of stock
-		if productDetails.QntyInStock < int(cartItems[i].Quantity) {
-			tx.Rollback()
-			return domain.Order{}, fmt.Errorf("product item out of stock for id : %v ", cartItems[i].ProductItemID)
-		}
-
+	createOrderLineQuery := `	INSERT INTO order_lines (product_item_id, order_id, quantity, unit_price, line_total) VALUES($1, $2, $3, $4, $5);`
+	for _, line := range quote.Lines {
 		// creating order line
-		productTotal := productDetails.Price * float64(cartItems[i].Quantity)
-		err = tx.Exec(createOrderLineQuery, cartItems[i].ProductItemID, createdOrder.ID, cartItems[i].Quantity, productTotal).Error
+		err = tx.Exec(createOrderLineQuery, line.ProductItemID, createdOrder.ID, line.Quantity, line.UnitPrice, line.LineTotal).Error
 		if err != nil {
 			tx.Rollback()
 			return domain.Order{}, err
 		}
 
 		//	reducing quantity in stock
-		reduceQuantityQuery := ` 	UPDATE product_items SET qnty_in_stock = qnty_in_stock - $1 WHERE id = $2`
-		err = tx.Exec(reduceQuantityQuery, cartItems[i].Quantity, cartItems[i].ProductItemID).Error
+		err = reserveStock(tx, int(line.ProductItemID), int(line.Quantity), createdOrder.ID)
 		if err != nil {
 			tx.Rollback()
 			return domain.Order{}, err
@@ -206,6 +307,40 @@ func (c *orderDatabase) BuyAll(ctx context.Context, userID int, orderInfo model.
 	return createdOrder, nil
 }
 
+// reserveStock reduces the stock of a product item inside an order transaction. The decrement only happens if
+// enough stock is left, so a checkout that loses the race for the last units fails instead of driving stock negative.
+func reserveStock(tx *gorm.DB, productItemID, quantity int, orderID uint) error {
+	reduceQuantityQuery := `UPDATE product_items SET qnty_in_stock = qnty_in_stock - $1 WHERE id = $2 AND qnty_in_stock >= $1`
+	result := tx.Exec(reduceQuantityQuery, quantity, productItemID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return &domain.OutOfStockError{ProductItemID: uint(productItemID), Requested: quantity}
+	}
+	_, err := recordStockMovement(tx, domain.StockMovement{
+		ProductItemID: uint(productItemID),
+		Quantity:      -quantity,
+		Reason:        domain.StockSale,
+		OrderID:       orderID,
+	})
+	return err
+}
+
+// findShippingAddress fetches the address an order is being placed with, inside the order transaction.
+func findShippingAddress(tx *gorm.DB, userID, addressID int) (domain.Address, error) {
+	var address domain.Address
+	findAddressQuery := `SELECT * FROM addresses WHERE id = $1 AND user_id = $2 AND is_deleted = false;`
+	err := tx.Raw(findAddressQuery, addressID, userID).Scan(&address).Error
+	if err != nil {
+		return domain.Address{}, err
+	}
+	if address.ID == 0 {
+		return domain.Address{}, fmt.Errorf("invalid shipping address")
+	}
+	return address, nil
+}
+
 func (c *orderDatabase) ViewOrderById(ctx context.Context, userID int, orderID int) (domain.Order, error) {
 	var order domain.Order
 	viewOrderQuery := `SELECT * FROM orders WHERE user_id = $1 AND id = $2;`
@@ -225,74 +360,124 @@ func (c *orderDatabase) ViewAllOrders(ctx context.Context, userID int) ([]domain
 	return orders, err
 }
 
-func (c *orderDatabase) CancelOrder(ctx context.Context, userID int, orderID int) (domain.Order, error) {
+// CancelOrder cancels a pending order of the user. The order is only updated if it is still pending, so a concurrent
+// status change by an admin is not overwritten. A refund is initiated if the order was paid for.
+func (c *orderDatabase) CancelOrder(ctx context.Context, userID int, orderID int) (domain.Order, domain.Refund, error) {
 	tx := c.DB.Begin()
 
-	//find order details. If order is in pending status, user can cancel the order. If order is not in pending status, user cannot cancel the order
-	var orderStatus int
-	viewStatusQuery := `SELECT order_status_id FROM orders WHERE user_id = $1 AND id = $2`
-	err := tx.Raw(viewStatusQuery, userID, orderID).Scan(&orderStatus).Error
+	var cancelledOrder domain.Order
+	cancelOrderQuery := `UPDATE orders SET order_status_id = $1 WHERE user_id = $2 AND id = $3 AND order_status_id = $4 RETURNING *;`
+	err := tx.Raw(cancelOrderQuery, domain.OrderCancelledByUser, userID, orderID, domain.OrderPending).Scan(&cancelledOrder).Error
 	if err != nil {
 		tx.Rollback()
-		return domain.Order{}, err
+		return domain.Order{}, domain.Refund{}, err
+	}
+	if cancelledOrder.ID == 0 {
+		tx.Rollback()
+		return domain.Order{}, domain.Refund{}, errOrderStatusChanged
+	}
+	if err := recordOrderStatus(tx, cancelledOrder, "user"); err != nil {
+		tx.Rollback()
+		return domain.Order{}, domain.Refund{}, err
 	}
-	//If no order if found
-	if orderStatus == 0 {
-		return domain.Order{}, fmt.Errorf("no such order found")
+	if err := checkOrderNotShipped(tx, cancelledOrder.ID); err != nil {
+		tx.Rollback()
+		return domain.Order{}, domain.Refund{}, err
 	}
 
-	//if order is in pending status
-	if orderStatus == 1 {
+	//put the units of the order back into stock and give back the coupon it used
+	if err := restockOrder(tx, cancelledOrder.ID, domain.StockCancellation); err != nil {
+		tx.Rollback()
+		return domain.Order{}, domain.Refund{}, err
+	}
+	if err := releaseCouponRedemption(tx, cancelledOrder.ID); err != nil {
+		tx.Rollback()
+		return domain.Order{}, domain.Refund{}, err
+	}
 
-		var cancelledOrder domain.Order
-		cancelOrderQuery := `UPDATE orders SET order_status_id = 2 WHERE user_id = $1 AND id = $2 RETURNING *;`
-		err := tx.Raw(cancelOrderQuery, userID, orderID).Scan(&cancelledOrder).Error
-		if err != nil {
+	refund, err := initiateRefund(tx, cancelledOrder.ID, 0, 0, "cancelled by user")
+	if err != nil {
+		tx.Rollback()
+		return domain.Order{}, domain.Refund{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return domain.Order{}, domain.Refund{}, err
+	}
+	return cancelledOrder, refund, nil
+}
+
+// UpdateOrder moves an order from its current statuses to the ones given by the admin. The order is only updated if it
+// is still in the statuses it was read in, so a transition checked against a stale order is not written. Cancelling an
+// order that was paid for initiates a refund.
+func (c *orderDatabase) UpdateOrder(ctx context.Context, currentOrder domain.Order, orderInfo model.UpdateOrder) (domain.Order, domain.Refund, error) {
+	tx := c.DB.Begin()
+
+	var updatedOrder domain.Order
+	updateStatusQuery := `	UPDATE orders SET order_status_id = $1, delivery_status_id = $2, delivery_updated_at = NOW()
+							WHERE id = $3 AND order_status_id = $4 AND delivery_status_id = $5 RETURNING *`
+	err := tx.Raw(updateStatusQuery, orderInfo.OrderStatusID, orderInfo.DeliveryStatusID, orderInfo.OrderID, currentOrder.OrderStatusID, currentOrder.DeliveryStatusID).Scan(&updatedOrder).Error
+	if err != nil {
+		tx.Rollback()
+		return domain.Order{}, domain.Refund{}, err
+	}
+	if updatedOrder.ID == 0 {
+		tx.Rollback()
+		return domain.Order{}, domain.Refund{}, errOrderStatusChanged
+	}
+	if err := recordOrderStatus(tx, updatedOrder, "admin"); err != nil {
+		tx.Rollback()
+		return domain.Order{}, domain.Refund{}, err
+	}
+
+	//units already handed over to a carrier cannot be put back into stock
+	if updatedOrder.OrderStatusID == domain.OrderCancelledByAdmin && currentOrder.OrderStatusID != domain.OrderCancelledByAdmin {
+		if err := checkOrderNotShipped(tx, updatedOrder.ID); err != nil {
 			tx.Rollback()
-			return domain.Order{}, err
+			return domain.Order{}, domain.Refund{}, err
 		}
+	}
 
-		//increase the quantity in product items table
-		var orderLineItems []domain.OrderLine
-		findOrderLineQuery := `SELECT * FROM order_lines WHERE order_id = $1;`
-		err = tx.Raw(findOrderLineQuery, orderID).Scan(&orderLineItems).Error
-		if err != nil {
+	//orders cancelled by admin are put back into stock and refunded, and give back the coupon they used
+	var refund domain.Refund
+	if updatedOrder.OrderStatusID == domain.OrderCancelledByAdmin {
+		if err := restockOrder(tx, updatedOrder.ID, domain.StockCancellation); err != nil {
 			tx.Rollback()
-			return domain.Order{}, err
+			return domain.Order{}, domain.Refund{}, err
 		}
-
-		qntyUpdateQuery := `UPDATE product_items SET qnty_in_stock = qnty_in_stock + $1 WHERE id = $2`
-		for i := range orderLineItems {
-			err := tx.Exec(qntyUpdateQuery, orderLineItems[i].Quantity, orderLineItems[i].ProductItemID).Error
-			if err != nil {
-				return domain.Order{}, err
-			}
+		if err := releaseCouponRedemption(tx, updatedOrder.ID); err != nil {
+			tx.Rollback()
+			return domain.Order{}, domain.Refund{}, err
 		}
-
-		tx.Commit()
-		return cancelledOrder, nil
 	}
-	//if order is already cancelled
-	if orderStatus == 2 {
+	if updatedOrder.OrderStatusID == domain.OrderCancelledByAdmin && currentOrder.OrderStatusID != domain.OrderCancelledByAdmin {
+		refund, err = initiateRefund(tx, updatedOrder.ID, 0, 0, "cancelled by admin")
+		if err != nil {
+			tx.Rollback()
+			return domain.Order{}, domain.Refund{}, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
 		tx.Rollback()
-		return domain.Order{}, fmt.Errorf("order already cancelled")
+		return domain.Order{}, domain.Refund{}, err
 	}
-	tx.Rollback()
-	return domain.Order{}, fmt.Errorf("order processed, cannot cancel")
+	return updatedOrder, refund, nil
 }
 
-func (c *orderDatabase) UpdateOrder(ctx context.Context, orderInfo model.UpdateOrder) (domain.Order, error) {
-
-	var updatedOrder domain.Order
-
-	updateStatusQuery := `UPDATE orders SET order_status_id = $1, delivery_status_id = $2, delivery_updated_at = NOW() WHERE id = $3 RETURNING *`
-	err := c.DB.Raw(updateStatusQuery, orderInfo.OrderStatusID, orderInfo.DeliveryStatusID, orderInfo.OrderID).Scan(&updatedOrder).Error
-
-	if updatedOrder.ID == 0 {
-		return domain.Order{}, fmt.Errorf("no order found")
-	}
+func (c *orderDatabase) FindOrderByID(ctx context.Context, orderID int) (domain.Order, error) {
+	var order domain.Order
+	findOrderQuery := `SELECT * FROM orders WHERE id = $1;`
+	err := c.DB.Raw(findOrderQuery, orderID).Scan(&order).Error
+	return order, err
+}
 
-	return updatedOrder, err
+func (c *orderDatabase) ViewOrderStatusHistory(ctx context.Context, orderID int) ([]domain.OrderStatusHistory, error) {
+	var history []domain.OrderStatusHistory
+	fetchHistoryQuery := `SELECT * FROM order_status_histories WHERE order_id = $1 ORDER BY created_at, id;`
+	err := c.DB.Raw(fetchHistoryQuery, orderID).Scan(&history).Error
+	return history, err
 }
 
 func (c *orderDatabase) ReturnRequest(ctx context.Context, returnRequest model.ReturnRequest) (domain.Order, error) {
@@ -300,8 +485,8 @@ func (c *orderDatabase) ReturnRequest(ctx context.Context, returnRequest model.R
 	fmt.Println("checkpoint 1")
 	tx := c.DB.Begin()
 	var orderDetails domain.Order
-	updateOrdersQuery := `	UPDATE orders SET order_status_id = 5 WHERE id = $1 RETURNING *; `
-	if err := tx.Raw(updateOrdersQuery, returnRequest.OrderID).Scan(&orderDetails).Error; err != nil {
+	updateOrdersQuery := `	UPDATE orders SET order_status_id = $1 WHERE id = $2 AND order_status_id = $3 RETURNING *; `
+	if err := tx.Raw(updateOrdersQuery, domain.OrderReturnRequested, returnRequest.OrderID, domain.OrderCompleted).Scan(&orderDetails).Error; err != nil {
 		fmt.Println("checkpoint 2")
 
 		tx.Rollback()
@@ -312,11 +497,15 @@ func (c *orderDatabase) ReturnRequest(ctx context.Context, returnRequest model.R
 		fmt.Println("checkpoint 4")
 
 		tx.Rollback()
-		return domain.Order{}, fmt.Errorf("no such order found")
+		return domain.Order{}, errOrderStatusChanged
+	}
+	if err := recordOrderStatus(tx, orderDetails, "user"); err != nil {
+		tx.Rollback()
+		return domain.Order{}, err
 	}
 
-	updateReturnsQuery := `INSERT INTO returns(order_id, reason, approved) VALUES($1, $2, false);`
-	if err := tx.Exec(updateReturnsQuery, returnRequest.OrderID, returnRequest.Reason).Error; err != nil {
+	updateReturnsQuery := `INSERT INTO returns(order_id, reason, status, created_at, updated_at) VALUES($1, $2, $3, NOW(), NOW());`
+	if err := tx.Exec(updateReturnsQuery, returnRequest.OrderID, returnRequest.Reason, domain.ReturnRequested).Error; err != nil {
 		tx.Rollback()
 		fmt.Println("checkpoint 5")
 		return domain.Order{}, err
@@ -325,3 +514,198 @@ func (c *orderDatabase) ReturnRequest(ctx context.Context, returnRequest model.R
 	tx.Commit()
 	return orderDetails, nil
 }
+
+func (c *orderDatabase) ListReturns(ctx context.Context, status string) ([]domain.Return, error) {
+	var returns []domain.Return
+	listReturnsQuery := `SELECT * FROM returns WHERE status = $1 ORDER BY created_at, id;`
+	err := c.DB.Raw(listReturnsQuery, status).Scan(&returns).Error
+	return returns, err
+}
+
+func (c *orderDatabase) FindReturnByOrderID(ctx context.Context, orderID int) (domain.Return, error) {
+	var returnDetails domain.Return
+	findReturnQuery := `SELECT * FROM returns WHERE order_id = $1 ORDER BY id DESC LIMIT 1;`
+	err := c.DB.Raw(findReturnQuery, orderID).Scan(&returnDetails).Error
+	return returnDetails, err
+}
+
+// ApproveReturn approves a return request and initiates a refund of the amount left to refund for the order.
+func (c *orderDatabase) ApproveReturn(ctx context.Context, returnID int, note string) (domain.Return, domain.Refund, error) {
+	tx := c.DB.Begin()
+
+	returnDetails, err := updateReturnStatus(tx, returnID, domain.ReturnRequested, domain.ReturnApproved, note)
+	if err != nil {
+		tx.Rollback()
+		return domain.Return{}, domain.Refund{}, err
+	}
+
+	refund, err := initiateRefund(tx, uint(returnDetails.OrderID), returnDetails.ID, 0, "returned")
+	if err != nil {
+		tx.Rollback()
+		return domain.Return{}, domain.Refund{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return domain.Return{}, domain.Refund{}, err
+	}
+	return returnDetails, refund, nil
+}
+
+// RejectReturn rejects a return request and moves the order back to completed.
+func (c *orderDatabase) RejectReturn(ctx context.Context, returnID int, note string) (domain.Return, error) {
+	tx := c.DB.Begin()
+
+	returnDetails, err := updateReturnStatus(tx, returnID, domain.ReturnRequested, domain.ReturnRejected, note)
+	if err != nil {
+		tx.Rollback()
+		return domain.Return{}, err
+	}
+
+	var order domain.Order
+	updateOrderQuery := `UPDATE orders SET order_status_id = $1 WHERE id = $2 AND order_status_id = $3 RETURNING *;`
+	if err := tx.Raw(updateOrderQuery, domain.OrderCompleted, returnDetails.OrderID, domain.OrderReturnRequested).Scan(&order).Error; err != nil {
+		tx.Rollback()
+		return domain.Return{}, err
+	}
+	if order.ID == 0 {
+		tx.Rollback()
+		return domain.Return{}, errOrderStatusChanged
+	}
+	if err := recordOrderStatus(tx, order, "admin"); err != nil {
+		tx.Rollback()
+		return domain.Return{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return domain.Return{}, err
+	}
+	return returnDetails, nil
+}
+
+func (c *orderDatabase) ReturnPickedUp(ctx context.Context, returnID int) (domain.Return, error) {
+	returnDetails, err := updateReturnStatus(c.DB, returnID, domain.ReturnApproved, domain.ReturnPickedUp, "")
+	return returnDetails, err
+}
+
+// ReturnReceived marks the returned products as received back in the warehouse and puts them back into stock.
+func (c *orderDatabase) ReturnReceived(ctx context.Context, returnID int) (domain.Return, error) {
+	tx := c.DB.Begin()
+
+	returnDetails, err := updateReturnStatus(tx, returnID, domain.ReturnPickedUp, domain.ReturnReceived, "")
+	if err != nil {
+		tx.Rollback()
+		return domain.Return{}, err
+	}
+
+	if err := restockOrder(tx, uint(returnDetails.OrderID), domain.StockReturn); err != nil {
+		tx.Rollback()
+		return domain.Return{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return domain.Return{}, err
+	}
+	return returnDetails, nil
+}
+
+// updateReturnStatus moves a return request from one status to the next. The update only matches a return in the
+// expected status, so a return cannot skip a step or be moved twice by concurrent requests.
+func updateReturnStatus(tx *gorm.DB, returnID int, from, to domain.ReturnStatus, note string) (domain.Return, error) {
+	var returnDetails domain.Return
+	updateReturnQuery := `	UPDATE returns
+							SET status = $1,
+								admin_note = CASE WHEN $2 = '' THEN admin_note ELSE $2 END,
+								picked_up_at = CASE WHEN $1 = 'picked_up' THEN NOW() ELSE picked_up_at END,
+								received_at = CASE WHEN $1 = 'received' THEN NOW() ELSE received_at END,
+								updated_at = NOW()
+							WHERE id = $3 AND status = $4
+							RETURNING *;`
+	if err := tx.Raw(updateReturnQuery, to, note, returnID, from).Scan(&returnDetails).Error; err != nil {
+		return domain.Return{}, err
+	}
+	if returnDetails.ID != 0 {
+		return returnDetails, nil
+	}
+
+	//find out why the return was not updated
+	var currentStatus domain.ReturnStatus
+	findStatusQuery := `SELECT status FROM returns WHERE id = $1;`
+	if err := tx.Raw(findStatusQuery, returnID).Scan(&currentStatus).Error; err != nil {
+		return domain.Return{}, err
+	}
+	if currentStatus == "" {
+		return domain.Return{}, fmt.Errorf("no return request found")
+	}
+	return domain.Return{}, fmt.Errorf("return is %v, only a return that is %v can be %v", currentStatus, from, to)
+}
+
+// recordOrderStatus adds the current statuses of an order to its status history
+func recordOrderStatus(tx *gorm.DB, order domain.Order, changedBy string) error {
+	recordStatusQuery := `	INSERT INTO order_status_histories (order_id, order_status_id, delivery_status_id, changed_by, created_at)
+							VALUES ($1, $2, $3, $4, NOW());`
+	return tx.Exec(recordStatusQuery, order.ID, order.OrderStatusID, order.DeliveryStatusID, changedBy).Error
+}
+
+// checkOrderNotShipped fails when any part of the order was handed over to a carrier, as the order can then no longer
+// be cancelled. It is called with the order row locked by the cancellation, which CreateShipment also locks, so a
+// shipment cannot be created for the order until the cancellation is committed.
+func checkOrderNotShipped(tx *gorm.DB, orderID uint) error {
+	var shipments int
+	if err := tx.Raw("SELECT COUNT(*) FROM shipments WHERE order_id = $1;", orderID).Scan(&shipments).Error; err != nil {
+		return err
+	}
+	if shipments > 0 {
+		return &domain.OrderStatusError{Reason: "cannot cancel an order that has been shipped"}
+	}
+	return nil
+}
+
+// restockOrder puts the units of an order back into stock and records them in the inventory ledger. An order is restocked
+// only once, calling it again for an order that was already cancelled or returned into stock does nothing. The order row is
+// locked first, so that two transactions restocking the same order cannot both pass the check.
+func restockOrder(tx *gorm.DB, orderID uint, reason domain.StockMovementReason) error {
+	var lockedOrderID uint
+	lockOrderQuery := `SELECT id FROM orders WHERE id = $1 FOR UPDATE`
+	if err := tx.Raw(lockOrderQuery, orderID).Scan(&lockedOrderID).Error; err != nil {
+		return err
+	}
+	if lockedOrderID == 0 {
+		return fmt.Errorf("no order found")
+	}
+
+	var restocked bool
+	restockedQuery := `SELECT EXISTS (SELECT 1 FROM stock_movements WHERE order_id = $1 AND reason IN ($2, $3))`
+	if err := tx.Raw(restockedQuery, orderID, domain.StockCancellation, domain.StockReturn).Scan(&restocked).Error; err != nil {
+		return err
+	}
+	if restocked {
+		return nil
+	}
+
+	//product items are restocked in the same order they are reserved in, to avoid deadlocks with checkouts
+	var orderLineItems []domain.OrderLine
+	findOrderLineQuery := `SELECT * FROM order_lines WHERE order_id = $1 ORDER BY product_item_id;`
+	if err := tx.Raw(findOrderLineQuery, orderID).Scan(&orderLineItems).Error; err != nil {
+		return err
+	}
+
+	qntyUpdateQuery := `UPDATE product_items SET qnty_in_stock = qnty_in_stock + $1 WHERE id = $2`
+	for i := range orderLineItems {
+		if err := tx.Exec(qntyUpdateQuery, orderLineItems[i].Quantity, orderLineItems[i].ProductItemID).Error; err != nil {
+			return err
+		}
+		_, err := recordStockMovement(tx, domain.StockMovement{
+			ProductItemID: orderLineItems[i].ProductItemID,
+			Quantity:      orderLineItems[i].Quantity,
+			Reason:        reason,
+			OrderID:       orderID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/config"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/db"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// connectTestDatabase connects to the postgres test instance configured through TEST_DB_* environment variables.
+// Tests that need a real database are skipped when no test instance is configured.
+func connectTestDatabase(t *testing.T) *gorm.DB {
+	if os.Getenv("TEST_DB_HOST") == "" {
+		t.Skip("TEST_DB_HOST not set, skipping test against postgres")
+	}
+	gormDB, err := db.ConnectDatabase(config.Config{
+		DBHost:     os.Getenv("TEST_DB_HOST"),
+		DBName:     os.Getenv("TEST_DB_NAME"),
+		DBUser:     os.Getenv("TEST_DB_USER"),
+		DBPort:     os.Getenv("TEST_DB_PORT"),
+		DBPassword: os.Getenv("TEST_DB_PASSWORD"),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %s", err)
+	}
+	return gormDB
+}
+
+func TestBuyProductItemConcurrentStock(t *testing.T) {
+	gormDB := connectTestDatabase(t)
+
+	const stock, buyers = 5, 20
+	suffix := time.Now().UnixNano()
+
+	//seed a user with an address and a product item with limited stock
+	seed := func(query string, dest *int, args ...interface{}) {
+		if err := gormDB.Raw(query, args...).Scan(dest).Error; err != nil {
+			t.Fatalf("failed to seed test data: %s", err)
+		}
+	}
+	var userID, addressID, categoryID, brandID, productID, productItemID int
+	seed(`INSERT INTO users (f_name, email, phone, password, created_at) VALUES ('Stock', $1, $2, 'password', NOW()) RETURNING id`,
+		&userID, fmt.Sprintf("stock%d@test.com", suffix), fmt.Sprintf("%d", suffix))
+	seed(`INSERT INTO addresses (user_id, house_number, pincode, is_default, is_deleted) VALUES ($1, '1', '682016', true, false) RETURNING id`,
+		&addressID, userID)
+	seed(`INSERT INTO product_categories (category_name) VALUES ($1) RETURNING id`, &categoryID, fmt.Sprintf("category-%d", suffix))
+	seed(`INSERT INTO product_brands (brand) VALUES ($1) RETURNING id`, &brandID, fmt.Sprintf("brand-%d", suffix))
+	seed(`INSERT INTO products (product_category_id, name, brand_id) VALUES ($1, $2, $3) RETURNING id`,
+		&productID, categoryID, fmt.Sprintf("product-%d", suffix), brandID)
+	seed(`INSERT INTO product_items (product_id, model, processor, ram, storage, display_size, os, sku, qnty_in_stock, price)
+			VALUES ($1, 'model', 'i5', '8GB', '512GB', '14', 'linux', $2, $3, 50000) RETURNING id`,
+		&productItemID, productID, fmt.Sprintf("sku-%d", suffix), stock)
+
+	orderRepository := NewOrderRepository(gormDB)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	placed, outOfStock := 0, 0
+	for i := 0; i < buyers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := orderRepository.BuyProductItem(context.TODO(), userID, model.PlaceOrder{
+				ProductItemID:     productItemID,
+				Quantity:          1,
+				PaymentMethodID:   1,
+				ShippingAddressID: addressID,
+			})
+			mu.Lock()
+			defer mu.Unlock()
+			var stockErr *domain.OutOfStockError
+			switch {
+			case err == nil:
+				placed++
+			case errors.As(err, &stockErr):
+				outOfStock++
+			default:
+				t.Errorf("unexpected error: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var remaining int
+	err := gormDB.Raw(`SELECT qnty_in_stock FROM product_items WHERE id = $1`, productItemID).Scan(&remaining).Error
+	assert.NoError(t, err)
+	assert.Equal(t, stock, placed)
+	assert.Equal(t, buyers-stock, outOfStock)
+	assert.Equal(t, 0, remaining)
+
+	//every reserved unit is recorded as a sale in the inventory ledger
+	var sold int
+	err = gormDB.Raw(`SELECT COALESCE(SUM(quantity), 0) FROM stock_movements WHERE product_item_id = $1 AND reason = 'sale'`, productItemID).Scan(&sold).Error
+	assert.NoError(t, err)
+	assert.Equal(t, -stock, sold)
+}
+
+func TestBuyProductItemConcurrentCoupon(t *testing.T) {
+	gormDB := connectTestDatabase(t)
+
+	const buyers = 10
+	suffix := time.Now().UnixNano()
+
+	//seed a user with an address, a product item for each checkout and a coupon the user can use once
+	seed := func(query string, dest *int, args ...interface{}) {
+		if err := gormDB.Raw(query, args...).Scan(dest).Error; err != nil {
+			t.Fatalf("failed to seed test data: %s", err)
+		}
+	}
+	var userID, addressID, categoryID, brandID, productID, couponID int
+	seed(`INSERT INTO users (f_name, email, phone, password, created_at) VALUES ('Coupon', $1, $2, 'password', NOW()) RETURNING id`,
+		&userID, fmt.Sprintf("coupon%d@test.com", suffix), fmt.Sprintf("%d", suffix))
+	seed(`INSERT INTO addresses (user_id, house_number, pincode, is_default, is_deleted) VALUES ($1, '1', '682016', true, false) RETURNING id`,
+		&addressID, userID)
+	seed(`INSERT INTO product_categories (category_name) VALUES ($1) RETURNING id`, &categoryID, fmt.Sprintf("category-%d", suffix))
+	seed(`INSERT INTO product_brands (brand) VALUES ($1) RETURNING id`, &brandID, fmt.Sprintf("brand-%d", suffix))
+	seed(`INSERT INTO products (product_category_id, name, brand_id) VALUES ($1, $2, $3) RETURNING id`,
+		&productID, categoryID, fmt.Sprintf("product-%d", suffix), brandID)
+	seed(`INSERT INTO coupons (code, discount_type, discount_percent, discount_max_amount, scope, valid_from, valid_till, usage_limit, per_user_limit)
+			VALUES ($1, 'percent', 10, 1000, 'cart', NOW(), NOW() + INTERVAL '1 day', 0, 1) RETURNING id`,
+		&couponID, fmt.Sprintf("ONCE%d", suffix))
+	productItemIDs := make([]int, buyers)
+	for i := range productItemIDs {
+		seed(`INSERT INTO product_items (product_id, model, processor, ram, storage, display_size, os, sku, qnty_in_stock, price)
+				VALUES ($1, 'model', 'i5', '8GB', '512GB', '14', 'linux', $2, 5, 50000) RETURNING id`,
+			&productItemIDs[i], productID, fmt.Sprintf("sku-%d-%d", suffix, i))
+	}
+
+	orderRepository := NewOrderRepository(gormDB)
+
+	//checkouts of different items do not wait for each other on the product item, only the coupon can stop them
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	placed := 0
+	for i := 0; i < buyers; i++ {
+		wg.Add(1)
+		go func(productItemID int) {
+			defer wg.Done()
+			_, err := orderRepository.BuyProductItem(context.TODO(), userID, model.PlaceOrder{
+				ProductItemID:     productItemID,
+				Quantity:          1,
+				PaymentMethodID:   1,
+				ShippingAddressID: addressID,
+				CouponID:          couponID,
+			})
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil {
+				placed++
+			}
+		}(productItemIDs[i])
+	}
+	wg.Wait()
+
+	var redemptions int
+	err := gormDB.Raw(`SELECT COUNT(*) FROM coupon_redemptions WHERE coupon_id = $1 AND released_at IS NULL`, couponID).Scan(&redemptions).Error
+	assert.NoError(t, err)
+	assert.Equal(t, 1, placed)
+	assert.Equal(t, 1, redemptions)
+}
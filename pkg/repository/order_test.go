@@ -0,0 +1,540 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func TestViewOrderById(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         int
+		orderID        int
+		expectedOutput domain.Order
+		buildStub      func(mock sqlmock.Sqlmock)
+		expectedErr    error
+	}{
+		{ //test case for an order placed with an address snapshot
+			name:    "order with shipping address snapshot",
+			userID:  1,
+			orderID: 4,
+			expectedOutput: domain.Order{
+				ID:                4,
+				UserID:            1,
+				ShippingAddressID: 2,
+				OrderTotal:        54000,
+				ShippingAddress: domain.OrderAddress{
+					HouseNumber: "12B",
+					Street:      "MG Road",
+					City:        "Kochi",
+					District:    "Ernakulam",
+					Pincode:     "682016",
+					Landmark:    "Near metro",
+					Label:       "office",
+				},
+			},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "shipping_address_id", "order_total", "shipping_house_number", "shipping_street", "shipping_city", "shipping_district", "shipping_pincode", "shipping_landmark", "shipping_label"}).
+					AddRow(4, 1, 2, 54000, "12B", "MG Road", "Kochi", "Ernakulam", "682016", "Near metro", "office")
+
+				mock.ExpectQuery("^SELECT \\* FROM orders (.+)$").
+					WithArgs(1, 4).
+					WillReturnRows(rows)
+			},
+			expectedErr: nil,
+		},
+		{ //test case for an order of another user
+			name:           "no order found",
+			userID:         1,
+			orderID:        5,
+			expectedOutput: domain.Order{},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT \\* FROM orders (.+)$").
+					WithArgs(1, 5).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			expectedErr: errors.New("no order found"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when initializing a mock db session", err)
+			}
+
+			orderRepository := NewOrderRepository(gormDB)
+			tt.buildStub(mock)
+
+			actualOutput, actualErr := orderRepository.ViewOrderById(context.TODO(), tt.userID, tt.orderID)
+			assert.Equal(t, tt.expectedErr, actualErr)
+			assert.Equal(t, tt.expectedOutput, actualOutput)
+
+			err = mock.ExpectationsWereMet()
+			if err != nil {
+				t.Errorf("Unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestBuyProductItem(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       model.PlaceOrder
+		buildStub   func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{ //test case for a product item that does not exist, it is not reported as out of stock
+			name:  "unknown product item",
+			input: model.PlaceOrder{ProductItemID: 99, Quantity: 1, PaymentMethodID: 1, ShippingAddressID: 3},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^SELECT pi.price, pi.qnty_in_stock, (.+) FOR UPDATE OF pi$").
+					WithArgs(99).
+					WillReturnRows(sqlmock.NewRows([]string{"price", "qnty_in_stock", "max_per_order", "category_id", "brand_id", "tax_percent"}))
+				mock.ExpectRollback()
+			},
+			expectedErr: errors.New("no such product item found"),
+		},
+		{ //test case for a product item with fewer units in stock than ordered
+			name:  "out of stock",
+			input: model.PlaceOrder{ProductItemID: 5, Quantity: 3, PaymentMethodID: 1, ShippingAddressID: 3},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^SELECT pi.price, pi.qnty_in_stock, (.+) FOR UPDATE OF pi$").
+					WithArgs(5).
+					WillReturnRows(sqlmock.NewRows([]string{"price", "qnty_in_stock", "max_per_order", "category_id", "brand_id", "tax_percent"}).
+						AddRow(50000, 2, 0, 1, 1, 18))
+				mock.ExpectRollback()
+			},
+			expectedErr: &domain.OutOfStockError{ProductItemID: 5, Requested: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when initializing a mock db session", err)
+			}
+
+			orderRepository := NewOrderRepository(gormDB)
+			tt.buildStub(mock)
+
+			actualOutput, actualErr := orderRepository.BuyProductItem(context.TODO(), 1, tt.input)
+			assert.Equal(t, tt.expectedErr, actualErr)
+			assert.Equal(t, domain.Order{}, actualOutput)
+
+			err = mock.ExpectationsWereMet()
+			if err != nil {
+				t.Errorf("Unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestUpdateOrder(t *testing.T) {
+	tests := []struct {
+		name           string
+		currentOrder   domain.Order
+		input          model.UpdateOrder
+		expectedOutput domain.Order
+		buildStub      func(mock sqlmock.Sqlmock)
+		expectedErr    error
+	}{
+		{ //test case for admin cancelling an order, units are put back into stock
+			name:           "cancelled by admin",
+			currentOrder:   domain.Order{ID: 4, OrderStatusID: 1, DeliveryStatusID: 2},
+			input:          model.UpdateOrder{OrderID: 4, OrderStatusID: 3, DeliveryStatusID: 2},
+			expectedOutput: domain.Order{ID: 4, OrderStatusID: 3, DeliveryStatusID: 2},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^UPDATE orders SET (.+)$").
+					WithArgs(3, 2, 4, 1, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "order_status_id", "delivery_status_id"}).AddRow(4, 3, 2))
+				mock.ExpectExec("^INSERT INTO order_status_histories (.+)$").
+					WithArgs(4, 3, 2, "admin").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery("^SELECT COUNT\\(\\*\\) FROM shipments WHERE order_id = \\$1;$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectQuery("^SELECT id FROM orders WHERE id = \\$1 FOR UPDATE$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+				mock.ExpectQuery("^SELECT EXISTS (.+)$").
+					WithArgs(4, domain.StockCancellation, domain.StockReturn).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				mock.ExpectQuery("^SELECT \\* FROM order_lines (.+)$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "product_item_id", "order_id", "quantity"}).AddRow(1, 7, 4, 2))
+				mock.ExpectExec("^UPDATE product_items SET qnty_in_stock = qnty_in_stock \\+ \\$1 WHERE id = \\$2$").
+					WithArgs(2, 7).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("^INSERT INTO stock_movements (.+)$").
+					WithArgs(7, 2, domain.StockCancellation, 0, 4, "").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec("^UPDATE coupon_redemptions SET released_at = NOW\\(\\), (.+)$").
+					WithArgs(4).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("^SELECT \\* FROM payment_details WHERE order_id = \\$1 FOR UPDATE;$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "order_total", "payment_method_id", "payment_status_id"}).AddRow(9, 4, 54000, 1, 1))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{ //test case for admin cancelling an order that was partly shipped, the shipped units are with the carrier and
+			//cannot be put back into stock
+			name:           "cancelled after shipping",
+			currentOrder:   domain.Order{ID: 4, OrderStatusID: 1, DeliveryStatusID: 2},
+			input:          model.UpdateOrder{OrderID: 4, OrderStatusID: 3, DeliveryStatusID: 2},
+			expectedOutput: domain.Order{},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^UPDATE orders SET (.+)$").
+					WithArgs(3, 2, 4, 1, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "order_status_id", "delivery_status_id"}).AddRow(4, 3, 2))
+				mock.ExpectExec("^INSERT INTO order_status_histories (.+)$").
+					WithArgs(4, 3, 2, "admin").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery("^SELECT COUNT\\(\\*\\) FROM shipments WHERE order_id = \\$1;$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectRollback()
+			},
+			expectedErr: &domain.OrderStatusError{Reason: "cannot cancel an order that has been shipped"},
+		},
+		{ //test case for setting cancelled status again, order is not restocked twice
+			name:           "cancelled by admin again",
+			currentOrder:   domain.Order{ID: 4, OrderStatusID: 3, DeliveryStatusID: 2},
+			input:          model.UpdateOrder{OrderID: 4, OrderStatusID: 3, DeliveryStatusID: 2},
+			expectedOutput: domain.Order{ID: 4, OrderStatusID: 3, DeliveryStatusID: 2},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^UPDATE orders SET (.+)$").
+					WithArgs(3, 2, 4, 3, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "order_status_id", "delivery_status_id"}).AddRow(4, 3, 2))
+				mock.ExpectExec("^INSERT INTO order_status_histories (.+)$").
+					WithArgs(4, 3, 2, "admin").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery("^SELECT id FROM orders WHERE id = \\$1 FOR UPDATE$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+				mock.ExpectQuery("^SELECT EXISTS (.+)$").
+					WithArgs(4, domain.StockCancellation, domain.StockReturn).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectExec("^UPDATE coupon_redemptions SET released_at = NOW\\(\\), (.+)$").
+					WithArgs(4).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{ //test case for a status change that does not affect stock
+			name:           "completed order",
+			currentOrder:   domain.Order{ID: 4, OrderStatusID: 1, DeliveryStatusID: 2},
+			input:          model.UpdateOrder{OrderID: 4, OrderStatusID: 4, DeliveryStatusID: 1},
+			expectedOutput: domain.Order{ID: 4, OrderStatusID: 4, DeliveryStatusID: 1},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^UPDATE orders SET (.+)$").
+					WithArgs(4, 1, 4, 1, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "order_status_id", "delivery_status_id"}).AddRow(4, 4, 1))
+				mock.ExpectExec("^INSERT INTO order_status_histories (.+)$").
+					WithArgs(4, 4, 1, "admin").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{ //test case for an order whose status was changed after it was read
+			name:           "order status changed concurrently",
+			currentOrder:   domain.Order{ID: 9, OrderStatusID: 1, DeliveryStatusID: 2},
+			input:          model.UpdateOrder{OrderID: 9, OrderStatusID: 3, DeliveryStatusID: 2},
+			expectedOutput: domain.Order{},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^UPDATE orders SET (.+)$").
+					WithArgs(3, 2, 9, 1, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectRollback()
+			},
+			expectedErr: &domain.OrderStatusError{Reason: "order status was changed by another request, please try again"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when initializing a mock db session", err)
+			}
+
+			orderRepository := NewOrderRepository(gormDB)
+			tt.buildStub(mock)
+
+			actualOutput, _, actualErr := orderRepository.UpdateOrder(context.TODO(), tt.currentOrder, tt.input)
+			assert.Equal(t, tt.expectedErr, actualErr)
+			assert.Equal(t, tt.expectedOutput, actualOutput)
+
+			err = mock.ExpectationsWereMet()
+			if err != nil {
+				t.Errorf("Unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestCancelOrder(t *testing.T) {
+	tests := []struct {
+		name           string
+		expectedOutput domain.Order
+		buildStub      func(mock sqlmock.Sqlmock)
+		expectedErr    error
+	}{
+		{ //test case for a pending order that was not shipped, units are put back into stock
+			name:           "not shipped",
+			expectedOutput: domain.Order{ID: 4, UserID: 1, OrderStatusID: 2, DeliveryStatusID: 2},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^UPDATE orders SET order_status_id = \\$1 WHERE user_id = \\$2 (.+)$").
+					WithArgs(domain.OrderCancelledByUser, 1, 4, domain.OrderPending).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "order_status_id", "delivery_status_id"}).AddRow(4, 1, 2, 2))
+				mock.ExpectExec("^INSERT INTO order_status_histories (.+)$").
+					WithArgs(4, 2, 2, "user").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery("^SELECT COUNT\\(\\*\\) FROM shipments WHERE order_id = \\$1;$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectQuery("^SELECT id FROM orders WHERE id = \\$1 FOR UPDATE$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+				mock.ExpectQuery("^SELECT EXISTS (.+)$").
+					WithArgs(4, domain.StockCancellation, domain.StockReturn).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				mock.ExpectQuery("^SELECT \\* FROM order_lines (.+)$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "product_item_id", "order_id", "quantity"}).AddRow(1, 7, 4, 2))
+				mock.ExpectExec("^UPDATE product_items SET qnty_in_stock = qnty_in_stock \\+ \\$1 WHERE id = \\$2$").
+					WithArgs(2, 7).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("^INSERT INTO stock_movements (.+)$").
+					WithArgs(7, 2, domain.StockCancellation, 0, 4, "").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec("^UPDATE coupon_redemptions SET released_at = NOW\\(\\), (.+)$").
+					WithArgs(4).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("^SELECT \\* FROM payment_details WHERE order_id = \\$1 FOR UPDATE;$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "order_total", "payment_method_id", "payment_status_id"}).AddRow(9, 4, 54000, 1, 1))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{ //test case for a pending order with a dispatched shipment, nothing is restocked
+			name:           "shipped",
+			expectedOutput: domain.Order{},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^UPDATE orders SET order_status_id = \\$1 WHERE user_id = \\$2 (.+)$").
+					WithArgs(domain.OrderCancelledByUser, 1, 4, domain.OrderPending).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "order_status_id", "delivery_status_id"}).AddRow(4, 1, 2, 2))
+				mock.ExpectExec("^INSERT INTO order_status_histories (.+)$").
+					WithArgs(4, 2, 2, "user").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery("^SELECT COUNT\\(\\*\\) FROM shipments WHERE order_id = \\$1;$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectRollback()
+			},
+			expectedErr: &domain.OrderStatusError{Reason: "cannot cancel an order that has been shipped"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when initializing a mock db session", err)
+			}
+
+			orderRepository := NewOrderRepository(gormDB)
+			tt.buildStub(mock)
+
+			actualOutput, _, actualErr := orderRepository.CancelOrder(context.TODO(), 1, 4)
+			assert.Equal(t, tt.expectedErr, actualErr)
+			assert.Equal(t, tt.expectedOutput, actualOutput)
+
+			err = mock.ExpectationsWereMet()
+			if err != nil {
+				t.Errorf("Unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestApproveReturn(t *testing.T) {
+	tests := []struct {
+		name           string
+		returnID       int
+		expectedOutput domain.Return
+		expectedRefund domain.Refund
+		buildStub      func(mock sqlmock.Sqlmock)
+		expectedErr    error
+	}{
+		{ //test case for approving a pending return, a refund of the order payment is initiated
+			name:           "pending return",
+			returnID:       2,
+			expectedOutput: domain.Return{ID: 2, OrderID: 4, Status: domain.ReturnApproved},
+			expectedRefund: domain.Refund{ID: 5, OrderID: 4, PaymentDetailsID: 9, ReturnID: 2, Amount: 54000, Destination: domain.RefundToGateway, Status: domain.RefundInitiated},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^UPDATE returns (.+)$").
+					WithArgs(domain.ReturnApproved, "", 2, domain.ReturnRequested).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "status"}).AddRow(2, 4, "approved"))
+				mock.ExpectQuery("^SELECT \\* FROM payment_details (.+) FOR UPDATE;$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "order_total", "payment_method_id", "payment_status_id"}).AddRow(9, 4, 54000, 2, 2))
+				mock.ExpectQuery("^SELECT COALESCE\\(SUM\\(amount\\), 0\\) AS total, (.+) FROM refunds (.+)$").
+					WithArgs(9, domain.RefundFailed, domain.RefundToGateway).
+					WillReturnRows(sqlmock.NewRows([]string{"total", "gateway"}).AddRow(0, 0))
+				mock.ExpectQuery("^INSERT INTO refunds (.+)$").
+					WithArgs(4, 9, 2, float64(54000), "returned", domain.RefundToGateway, domain.RefundInitiated).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "payment_details_id", "return_id", "amount", "destination", "status"}).AddRow(5, 4, 9, 2, 54000, "gateway", "initiated"))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{ //test case for approving the return of an order paid in cash on delivery, which is credited to the wallet
+			name:           "return of cash on delivery order",
+			returnID:       4,
+			expectedOutput: domain.Return{ID: 4, OrderID: 6, Status: domain.ReturnApproved},
+			expectedRefund: domain.Refund{ID: 8, OrderID: 6, PaymentDetailsID: 11, ReturnID: 4, Amount: 1200.5, Destination: domain.RefundToWallet, Status: domain.RefundProcessed},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^UPDATE returns (.+)$").
+					WithArgs(domain.ReturnApproved, "", 4, domain.ReturnRequested).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "status"}).AddRow(4, 6, "approved"))
+				mock.ExpectQuery("^SELECT \\* FROM payment_details (.+) FOR UPDATE;$").
+					WithArgs(6).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "order_total", "payment_method_id", "payment_status_id"}).AddRow(11, 6, 1200.5, 1, 1))
+				mock.ExpectQuery("^SELECT COALESCE\\(SUM\\(amount\\), 0\\) AS total, (.+) FROM refunds (.+)$").
+					WithArgs(11, domain.RefundFailed, domain.RefundToGateway).
+					WillReturnRows(sqlmock.NewRows([]string{"total", "gateway"}).AddRow(0, 0))
+				mock.ExpectQuery("^SELECT user_id FROM orders WHERE id = \\$1;$").
+					WithArgs(6).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(3))
+				mock.ExpectQuery("^INSERT INTO refunds (.+)$").
+					WithArgs(6, 11, 4, 1200.5, "returned", domain.RefundToWallet, domain.RefundProcessed).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "payment_details_id", "return_id", "amount", "destination", "status"}).AddRow(8, 6, 11, 4, 1200.5, "wallet", "processed"))
+				mock.ExpectQuery("^SELECT id FROM users WHERE id = \\$1 FOR UPDATE;$").
+					WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+				mock.ExpectQuery("^SELECT COALESCE\\(SUM\\(amount\\), 0\\) FROM wallet_entries (.+)$").
+					WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(100))
+				mock.ExpectQuery("^INSERT INTO wallet_entries (.+)$").
+					WithArgs(3, 1200.5, 1300.5, domain.WalletRefund, 6, 8, 0, "returned").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec("^UPDATE payment_details (.+)$").
+					WithArgs(domain.PaymentRefunded, 11, domain.PaymentCompleted, domain.RefundProcessed).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{ //test case for approving a return that was already rejected
+			name:           "rejected return",
+			returnID:       3,
+			expectedOutput: domain.Return{},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^UPDATE returns (.+)$").
+					WithArgs(domain.ReturnApproved, "", 3, domain.ReturnRequested).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectQuery("^SELECT status FROM returns (.+)$").
+					WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("rejected"))
+				mock.ExpectRollback()
+			},
+			expectedErr: errors.New("return is rejected, only a return that is requested can be approved"),
+		},
+		{ //test case for a return that does not exist
+			name:           "no return found",
+			returnID:       7,
+			expectedOutput: domain.Return{},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^UPDATE returns (.+)$").
+					WithArgs(domain.ReturnApproved, "", 7, domain.ReturnRequested).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectQuery("^SELECT status FROM returns (.+)$").
+					WithArgs(7).
+					WillReturnRows(sqlmock.NewRows([]string{"status"}))
+				mock.ExpectRollback()
+			},
+			expectedErr: errors.New("no return request found"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when initializing a mock db session", err)
+			}
+
+			orderRepository := NewOrderRepository(gormDB)
+			tt.buildStub(mock)
+
+			actualOutput, actualRefund, actualErr := orderRepository.ApproveReturn(context.TODO(), tt.returnID, "")
+			assert.Equal(t, tt.expectedErr, actualErr)
+			assert.Equal(t, tt.expectedOutput, actualOutput)
+			assert.Equal(t, tt.expectedRefund, actualRefund)
+
+			err = mock.ExpectationsWereMet()
+			if err != nil {
+				t.Errorf("Unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
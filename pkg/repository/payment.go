@@ -2,9 +2,12 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
 	interfaces "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/interface"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/webhook"
 	"gorm.io/gorm"
+	"time"
 )
 
 type paymentDatabase struct {
@@ -22,10 +25,278 @@ func (c *paymentDatabase) ViewPaymentDetails(ctx context.Context, orderID int) (
 	return paymentDetails, err
 }
 
-func (c *paymentDatabase) UpdatePaymentDetails(ctx context.Context, orderID int, paymentRef string) (domain.PaymentDetails, error) {
-	var updatedPayment domain.PaymentDetails
-	updatePaymentQuery := `	UPDATE payment_details SET payment_method_id = 2, payment_status_id = 2, payment_ref = $1, updated_at = NOW()
-							WHERE order_id = $2 RETURNING *;`
-	err := c.DB.Raw(updatePaymentQuery, paymentRef, orderID).Scan(&updatedPayment).Error
-	return updatedPayment, err
+// SetGatewayOrderID stores the id of the order created at the payment gateway for paying an order, and returns the id stored.
+// If an id was already stored by a concurrent checkout, it is kept, so that every checkout of an order pays the same gateway order.
+func (c *paymentDatabase) SetGatewayOrderID(ctx context.Context, orderID int, gatewayOrderID string) (string, error) {
+	var storedID string
+	setGatewayOrderQuery := `	UPDATE payment_details SET gateway_order_id = COALESCE(NULLIF(gateway_order_id, ''), $1), updated_at = NOW()
+								WHERE order_id = $2 RETURNING gateway_order_id;`
+	err := c.DB.Raw(setGatewayOrderQuery, gatewayOrderID, orderID).Scan(&storedID).Error
+	if err != nil {
+		return "", err
+	}
+	if storedID == "" {
+		return "", fmt.Errorf("no payment details found")
+	}
+	return storedID, nil
+}
+
+// UpdatePaymentDetails completes the payment of an order with the payment made through the checkout. A payment made
+// for an order that is no longer waiting for it is refunded, and the refund to send to the gateway is returned.
+func (c *paymentDatabase) UpdatePaymentDetails(ctx context.Context, orderID int, paymentRef string) (domain.PaymentDetails, domain.Refund, error) {
+	tx := c.DB.Begin()
+
+	updatedPayment, refund, err := completePayment(tx, orderID, paymentRef)
+	if err != nil {
+		tx.Rollback()
+		return domain.PaymentDetails{}, domain.Refund{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return domain.PaymentDetails{}, domain.Refund{}, err
+	}
+	return updatedPayment, refund, nil
+}
+
+// CapturePayment marks the payment of an order as completed when the gateway reports it captured.
+// A payment already completed through the checkout callback is left as it is. A payment captured for an order that is
+// no longer waiting for it is refunded, and the refund to send to the gateway is returned.
+func (c *paymentDatabase) CapturePayment(ctx context.Context, eventID string, orderID int, paymentRef string) (domain.Refund, error) {
+	var refund domain.Refund
+	err := c.applyPaymentEvent(eventID, webhook.PaymentCaptured, paymentRef, func(tx *gorm.DB) error {
+		var err error
+		_, refund, err = completePayment(tx, orderID, paymentRef)
+		return err
+	})
+	return refund, err
+}
+
+// FailPayment marks a pending payment as failed. The user can still pay for the order again. The coupon used by the
+// order is given back meanwhile, and taken back if the order is paid for after all.
+func (c *paymentDatabase) FailPayment(ctx context.Context, eventID string, orderID int, paymentRef string) error {
+	return c.applyPaymentEvent(eventID, webhook.PaymentFailed, paymentRef, func(tx *gorm.DB) error {
+		_, err := failPayment(tx, orderID)
+		return err
+	})
+}
+
+// ProcessRefund marks a refund of the payment as processed. The refund is found by the id the gateway gave it, refunds
+// initiated before the id was stored are matched by the oldest initiated refund for the amount. The payment is marked
+// refunded once everything paid for it is refunded.
+func (c *paymentDatabase) ProcessRefund(ctx context.Context, eventID, paymentRef, refundRef string, amount float64) error {
+	return c.applyPaymentEvent(eventID, webhook.RefundProcessed, paymentRef, func(tx *gorm.DB) error {
+		processRefundQuery := `	UPDATE refunds SET status = $1, refund_ref = $2, updated_at = NOW()
+								WHERE id = (
+									SELECT r.id FROM refunds r
+									JOIN payment_details pd ON pd.id = r.payment_details_id
+									WHERE pd.payment_ref = $3 AND r.status = $4 AND (r.refund_ref = $2
+										OR (COALESCE(r.refund_ref, '') = '' AND ROUND(r.amount * 100) = ROUND($5 * 100)))
+									ORDER BY r.refund_ref = $2 DESC, r.id
+									LIMIT 1
+								);`
+		err := tx.Exec(processRefundQuery, domain.RefundProcessed, refundRef, paymentRef, domain.RefundInitiated, amount).Error
+		if err != nil {
+			return err
+		}
+
+		refundPaymentQuery := `	UPDATE payment_details pd SET payment_status_id = $1, updated_at = NOW()
+								WHERE pd.payment_ref = $2 AND pd.payment_status_id = $3 AND ROUND(pd.order_total * 100) <= (
+									SELECT ROUND(COALESCE(SUM(r.amount), 0) * 100) FROM refunds r
+									WHERE r.payment_details_id = pd.id AND r.status = $4
+								);`
+		return tx.Exec(refundPaymentQuery, domain.PaymentRefunded, paymentRef, domain.PaymentCompleted, domain.RefundProcessed).Error
+	})
+}
+
+// FailRefund marks an initiated refund the gateway could not process as failed, so that the admin can retry it
+func (c *paymentDatabase) FailRefund(ctx context.Context, eventID, paymentRef, refundRef string) error {
+	return c.applyPaymentEvent(eventID, webhook.RefundFailed, paymentRef, func(tx *gorm.DB) error {
+		failRefundQuery := `	UPDATE refunds SET status = $1, failure_reason = $2, updated_at = NOW()
+								WHERE refund_ref = $3 AND status = $4;`
+		return tx.Exec(failRefundQuery, domain.RefundFailed, "refund failed at the payment gateway", refundRef, domain.RefundInitiated).Error
+	})
+}
+
+// FindStalePayments lists the payments of pending orders placed before the given time that are still waiting to be paid online
+func (c *paymentDatabase) FindStalePayments(ctx context.Context, placedBefore time.Time) ([]domain.PaymentDetails, error) {
+	var stalePayments []domain.PaymentDetails
+	findStalePaymentsQuery := `	SELECT pd.* FROM payment_details pd
+								JOIN orders o ON o.id = pd.order_id
+								WHERE pd.payment_method_id = $1 AND pd.payment_status_id IN ($2, $3)
+									AND o.order_status_id = $4 AND o.order_date < $5
+								ORDER BY pd.order_id;`
+	err := c.DB.Raw(findStalePaymentsQuery, domain.PaymentOnline, domain.PaymentPending, domain.PaymentFailed, domain.OrderPending, placedBefore).Scan(&stalePayments).Error
+	return stalePayments, err
+}
+
+// MarkPaymentFailed marks a pending payment as failed, when every attempt to pay for the order failed at the gateway.
+// The coupon used by the order is given back, as with FailPayment.
+func (c *paymentDatabase) MarkPaymentFailed(ctx context.Context, orderID int) (domain.PaymentDetails, error) {
+	tx := c.DB.Begin()
+
+	failedPayment, err := failPayment(tx, orderID)
+	if err != nil {
+		tx.Rollback()
+		return domain.PaymentDetails{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return domain.PaymentDetails{}, err
+	}
+	return failedPayment, nil
+}
+
+// ExpirePayment gives up on the payment of an order that was never paid for. The order is cancelled, its units are put back
+// into stock, the coupon it used is given back and the part paid from the wallet is credited back. Nothing is changed if the order was paid for or cancelled meanwhile.
+func (c *paymentDatabase) ExpirePayment(ctx context.Context, orderID int) (domain.Order, domain.Refund, error) {
+	tx := c.DB.Begin()
+
+	var expiredPaymentID uint
+	expirePaymentQuery := `	UPDATE payment_details SET payment_status_id = $1, updated_at = NOW()
+							WHERE order_id = $2 AND payment_status_id IN ($3, $4) RETURNING id;`
+	err := tx.Raw(expirePaymentQuery, domain.PaymentExpired, orderID, domain.PaymentPending, domain.PaymentFailed).Scan(&expiredPaymentID).Error
+	if err != nil {
+		tx.Rollback()
+		return domain.Order{}, domain.Refund{}, err
+	}
+	if expiredPaymentID == 0 {
+		tx.Rollback()
+		return domain.Order{}, domain.Refund{}, fmt.Errorf("payment is no longer pending")
+	}
+
+	var cancelledOrder domain.Order
+	cancelOrderQuery := `UPDATE orders SET order_status_id = $1 WHERE id = $2 AND order_status_id = $3 RETURNING *;`
+	err = tx.Raw(cancelOrderQuery, domain.OrderCancelledByAdmin, orderID, domain.OrderPending).Scan(&cancelledOrder).Error
+	if err != nil {
+		tx.Rollback()
+		return domain.Order{}, domain.Refund{}, err
+	}
+	if cancelledOrder.ID == 0 {
+		tx.Rollback()
+		return domain.Order{}, domain.Refund{}, errOrderStatusChanged
+	}
+	if err := recordOrderStatus(tx, cancelledOrder, "reconciler"); err != nil {
+		tx.Rollback()
+		return domain.Order{}, domain.Refund{}, err
+	}
+	if err := checkOrderNotShipped(tx, cancelledOrder.ID); err != nil {
+		tx.Rollback()
+		return domain.Order{}, domain.Refund{}, err
+	}
+
+	//release the stock and the coupon reserved for the order
+	if err := restockOrder(tx, cancelledOrder.ID, domain.StockCancellation); err != nil {
+		tx.Rollback()
+		return domain.Order{}, domain.Refund{}, err
+	}
+	if err := releaseCouponRedemption(tx, cancelledOrder.ID); err != nil {
+		tx.Rollback()
+		return domain.Order{}, domain.Refund{}, err
+	}
+
+	refund, err := initiateRefund(tx, cancelledOrder.ID, 0, 0, "payment expired")
+	if err != nil {
+		tx.Rollback()
+		return domain.Order{}, domain.Refund{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return domain.Order{}, domain.Refund{}, err
+	}
+	return cancelledOrder, refund, nil
+}
+
+// failPayment marks a pending payment as failed and releases the coupon redemption of the order. A payment that is not
+// pending is left as it is and returned empty.
+func failPayment(tx *gorm.DB, orderID int) (domain.PaymentDetails, error) {
+	var failedPayment domain.PaymentDetails
+	failPaymentQuery := `	UPDATE payment_details SET payment_status_id = $1, updated_at = NOW()
+							WHERE order_id = $2 AND payment_status_id = $3 RETURNING *;`
+	if err := tx.Raw(failPaymentQuery, domain.PaymentFailed, orderID, domain.PaymentPending).Scan(&failedPayment).Error; err != nil {
+		return domain.PaymentDetails{}, err
+	}
+	if failedPayment.ID == 0 {
+		return failedPayment, nil
+	}
+	if err := releaseCouponRedemption(tx, uint(orderID)); err != nil {
+		return domain.PaymentDetails{}, err
+	}
+	return failedPayment, nil
+}
+
+// completePayment marks the payment of an order as completed with the payment made at the gateway. Only a pending or
+// failed payment of a pending order is waiting to be paid. A payment made for an order that was cancelled or whose
+// payment expired meanwhile is still recorded, so that it can be refunded, and everything left to refund of the order
+// is refunded through the gateway. A failed payment paid after all takes back the coupon given back when it failed. A
+// payment already completed is left as it is. The payment row is locked, so that
+// the payment cannot be expired while it is completed.
+func completePayment(tx *gorm.DB, orderID int, paymentRef string) (domain.PaymentDetails, domain.Refund, error) {
+	paymentDetails, err := lockPayment(tx, uint(orderID))
+	if err != nil {
+		return domain.PaymentDetails{}, domain.Refund{}, err
+	}
+	if paymentDetails.PaymentStatusID == domain.PaymentCompleted || paymentDetails.PaymentStatusID == domain.PaymentRefunded {
+		return paymentDetails, domain.Refund{}, nil
+	}
+
+	var orderStatusID domain.OrderStatusID
+	if err := tx.Raw("SELECT order_status_id FROM orders WHERE id = $1;", orderID).Scan(&orderStatusID).Error; err != nil {
+		return domain.PaymentDetails{}, domain.Refund{}, err
+	}
+	awaitingPayment := orderStatusID == domain.OrderPending &&
+		(paymentDetails.PaymentStatusID == domain.PaymentPending || paymentDetails.PaymentStatusID == domain.PaymentFailed)
+
+	var completedPayment domain.PaymentDetails
+	completePaymentQuery := `	UPDATE payment_details SET payment_method_id = $1, payment_status_id = $2, payment_ref = $3, updated_at = NOW()
+								WHERE id = $4 RETURNING *;`
+	err = tx.Raw(completePaymentQuery, domain.PaymentOnline, domain.PaymentCompleted, paymentRef, paymentDetails.ID).Scan(&completedPayment).Error
+	if err != nil {
+		return domain.PaymentDetails{}, domain.Refund{}, err
+	}
+	if awaitingPayment {
+		if paymentDetails.PaymentStatusID == domain.PaymentFailed {
+			if err := restoreCouponRedemption(tx, uint(orderID)); err != nil {
+				return domain.PaymentDetails{}, domain.Refund{}, err
+			}
+		}
+		return completedPayment, domain.Refund{}, nil
+	}
+
+	refund, err := initiateRefund(tx, uint(orderID), 0, 0, "paid after the order was cancelled")
+	if err != nil {
+		return domain.PaymentDetails{}, domain.Refund{}, err
+	}
+	return completedPayment, refund, nil
+}
+
+// applyPaymentEvent records a webhook event and applies it in the same transaction. An event that is already recorded
+// is not applied again. A redelivery of an event being applied waits on the unique event id until the first one commits.
+func (c *paymentDatabase) applyPaymentEvent(eventID, event, paymentRef string, apply func(tx *gorm.DB) error) error {
+	tx := c.DB.Begin()
+
+	var recordedID uint
+	recordEventQuery := `	INSERT INTO payment_events (event_id, event, payment_ref, created_at) VALUES ($1, $2, $3, NOW())
+							ON CONFLICT (event_id) DO NOTHING RETURNING id;`
+	if err := tx.Raw(recordEventQuery, eventID, event, paymentRef).Scan(&recordedID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if recordedID == 0 {
+		tx.Rollback()
+		return nil
+	}
+
+	if err := apply(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
 }
@@ -0,0 +1,331 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func TestCapturePayment(t *testing.T) {
+	paymentColumns := []string{"id", "order_id", "order_total", "wallet_amount", "payment_method_id", "payment_status_id", "payment_ref"}
+
+	tests := []struct {
+		name           string
+		eventID        string
+		expectedOutput domain.Refund
+		buildStub      func(mock sqlmock.Sqlmock)
+		expectedErr    error
+	}{
+		{ //test case for an event received for the first time, the pending order is paid
+			name:           "new event",
+			eventID:        "evt_1",
+			expectedOutput: domain.Refund{},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^INSERT INTO payment_events (.+) ON CONFLICT (.+)$").
+					WithArgs("evt_1", "payment.captured", "pay_1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectQuery("^SELECT \\* FROM payment_details WHERE order_id = \\$1 FOR UPDATE;$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows(paymentColumns).AddRow(9, 4, 54000, 4000, 2, 1, ""))
+				mock.ExpectQuery("^SELECT order_status_id FROM orders WHERE id = \\$1;$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"order_status_id"}).AddRow(1))
+				mock.ExpectQuery("^UPDATE payment_details SET (.+) WHERE id = \\$4 RETURNING \\*;$").
+					WithArgs(domain.PaymentOnline, domain.PaymentCompleted, "pay_1", 9).
+					WillReturnRows(sqlmock.NewRows(paymentColumns).AddRow(9, 4, 54000, 4000, 2, 2, "pay_1"))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{ //test case for an event delivered again, the payment is not updated a second time
+			name:           "redelivered event",
+			eventID:        "evt_1",
+			expectedOutput: domain.Refund{},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^INSERT INTO payment_events (.+) ON CONFLICT (.+)$").
+					WithArgs("evt_1", "payment.captured", "pay_1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectRollback()
+			},
+			expectedErr: nil,
+		},
+		{ //test case for a payment already completed through the checkout callback, it is left as it is
+			name:           "completed by checkout",
+			eventID:        "evt_2",
+			expectedOutput: domain.Refund{},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^INSERT INTO payment_events (.+) ON CONFLICT (.+)$").
+					WithArgs("evt_2", "payment.captured", "pay_1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectQuery("^SELECT \\* FROM payment_details WHERE order_id = \\$1 FOR UPDATE;$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows(paymentColumns).AddRow(9, 4, 54000, 4000, 2, 2, "pay_1"))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{ //test case for a retry that is paid after the first attempt failed, the coupon given back on failure is taken
+			//back with the slots that are free now
+			name:           "paid after failure",
+			eventID:        "evt_4",
+			expectedOutput: domain.Refund{},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^INSERT INTO payment_events (.+) ON CONFLICT (.+)$").
+					WithArgs("evt_4", "payment.captured", "pay_1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+				mock.ExpectQuery("^SELECT \\* FROM payment_details WHERE order_id = \\$1 FOR UPDATE;$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows(paymentColumns).AddRow(9, 4, 54000, 4000, 2, 3, ""))
+				mock.ExpectQuery("^SELECT order_status_id FROM orders WHERE id = \\$1;$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"order_status_id"}).AddRow(1))
+				mock.ExpectQuery("^UPDATE payment_details SET (.+) WHERE id = \\$4 RETURNING \\*;$").
+					WithArgs(domain.PaymentOnline, domain.PaymentCompleted, "pay_1", 9).
+					WillReturnRows(sqlmock.NewRows(paymentColumns).AddRow(9, 4, 54000, 4000, 2, 2, "pay_1"))
+				mock.ExpectQuery("^SELECT \\* FROM coupon_redemptions WHERE order_id = \\$1 AND released_at IS NOT NULL$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "coupon_id", "user_id", "order_id", "discount", "released_at"}).
+						AddRow(6, 2, 1, 4, 6000, time.Now()))
+				mock.ExpectQuery("^SELECT c.code, c.usage_limit, c.per_user_limit, (.+)$").
+					WithArgs(2, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"code", "usage_limit", "per_user_limit", "usage_slot", "user_slot"}).
+						AddRow("SAVE10", 100, 1, 8, 1))
+				mock.ExpectExec("^UPDATE coupon_redemptions SET released_at = NULL, usage_slot = \\$1, user_slot = \\$2 (.+)$").
+					WithArgs(8, 1, 6).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{ //test case for a capture that arrives after the payment expired and the order was cancelled, the capture is
+			//recorded and what was paid through the gateway is refunded, the wallet part was credited back on expiry
+			name:    "captured after expiry",
+			eventID: "evt_3",
+			expectedOutput: domain.Refund{ID: 12, OrderID: 4, PaymentDetailsID: 9, Amount: 50000, Reason: "paid after the order was cancelled",
+				Destination: domain.RefundToGateway, Status: domain.RefundInitiated},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^INSERT INTO payment_events (.+) ON CONFLICT (.+)$").
+					WithArgs("evt_3", "payment.captured", "pay_1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+				mock.ExpectQuery("^SELECT \\* FROM payment_details WHERE order_id = \\$1 FOR UPDATE;$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows(paymentColumns).AddRow(9, 4, 54000, 4000, 2, 5, ""))
+				mock.ExpectQuery("^SELECT order_status_id FROM orders WHERE id = \\$1;$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"order_status_id"}).AddRow(3))
+				mock.ExpectQuery("^UPDATE payment_details SET (.+) WHERE id = \\$4 RETURNING \\*;$").
+					WithArgs(domain.PaymentOnline, domain.PaymentCompleted, "pay_1", 9).
+					WillReturnRows(sqlmock.NewRows(paymentColumns).AddRow(9, 4, 54000, 4000, 2, 2, "pay_1"))
+				mock.ExpectQuery("^SELECT \\* FROM payment_details WHERE order_id = \\$1 FOR UPDATE;$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows(paymentColumns).AddRow(9, 4, 54000, 4000, 2, 2, "pay_1"))
+				mock.ExpectQuery("^SELECT COALESCE\\(SUM\\(amount\\), 0\\) AS total, (.+)$").
+					WithArgs(9, domain.RefundFailed, domain.RefundToGateway).
+					WillReturnRows(sqlmock.NewRows([]string{"total", "gateway"}).AddRow(4000, 0))
+				mock.ExpectQuery("^INSERT INTO refunds (.+)$").
+					WithArgs(4, 9, 0, 50000.0, "paid after the order was cancelled", domain.RefundToGateway, domain.RefundInitiated).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "payment_details_id", "amount", "reason", "destination", "status"}).
+						AddRow(12, 4, 9, 50000, "paid after the order was cancelled", "gateway", "initiated"))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when initializing a mock db session", err)
+			}
+
+			paymentRepository := NewPaymentRepository(gormDB)
+			tt.buildStub(mock)
+
+			actualOutput, actualErr := paymentRepository.CapturePayment(context.TODO(), tt.eventID, 4, "pay_1")
+			assert.Equal(t, tt.expectedErr, actualErr)
+			assert.Equal(t, tt.expectedOutput, actualOutput)
+
+			err = mock.ExpectationsWereMet()
+			if err != nil {
+				t.Errorf("Unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestFailPayment(t *testing.T) {
+	paymentColumns := []string{"id", "order_id", "order_total", "payment_method_id", "payment_status_id"}
+
+	tests := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{ //test case for a pending payment that failed, the coupon slots of the order are freed
+			name: "pending payment",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^INSERT INTO payment_events (.+) ON CONFLICT (.+)$").
+					WithArgs("evt_5", "payment.failed", "pay_1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+				mock.ExpectQuery("^UPDATE payment_details SET payment_status_id = \\$1, (.+) RETURNING \\*;$").
+					WithArgs(domain.PaymentFailed, 4, domain.PaymentPending).
+					WillReturnRows(sqlmock.NewRows(paymentColumns).AddRow(9, 4, 54000, 2, 3))
+				mock.ExpectExec("^UPDATE coupon_redemptions SET released_at = NOW\\(\\), usage_slot = NULL, user_slot = NULL (.+)$").
+					WithArgs(4).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{ //test case for a failure reported after the payment was completed, nothing is changed
+			name: "completed payment",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^INSERT INTO payment_events (.+) ON CONFLICT (.+)$").
+					WithArgs("evt_5", "payment.failed", "pay_1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+				mock.ExpectQuery("^UPDATE payment_details SET payment_status_id = \\$1, (.+) RETURNING \\*;$").
+					WithArgs(domain.PaymentFailed, 4, domain.PaymentPending).
+					WillReturnRows(sqlmock.NewRows(paymentColumns))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when initializing a mock db session", err)
+			}
+
+			paymentRepository := NewPaymentRepository(gormDB)
+			tt.buildStub(mock)
+
+			actualErr := paymentRepository.FailPayment(context.TODO(), "evt_5", 4, "pay_1")
+			assert.Equal(t, tt.expectedErr, actualErr)
+
+			err = mock.ExpectationsWereMet()
+			if err != nil {
+				t.Errorf("Unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestExpirePayment(t *testing.T) {
+	tests := []struct {
+		name           string
+		expectedOutput domain.Order
+		buildStub      func(mock sqlmock.Sqlmock)
+		expectedErr    error
+	}{
+		{ //test case for an unpaid order, it is cancelled and its stock is released
+			name:           "unpaid order",
+			expectedOutput: domain.Order{ID: 4, OrderStatusID: 3, DeliveryStatusID: 2},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^UPDATE payment_details SET (.+)$").
+					WithArgs(domain.PaymentExpired, 4, domain.PaymentPending, domain.PaymentFailed).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+				mock.ExpectQuery("^UPDATE orders SET (.+)$").
+					WithArgs(domain.OrderCancelledByAdmin, 4, domain.OrderPending).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "order_status_id", "delivery_status_id"}).AddRow(4, 3, 2))
+				mock.ExpectExec("^INSERT INTO order_status_histories (.+)$").
+					WithArgs(4, 3, 2, "reconciler").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery("^SELECT COUNT\\(\\*\\) FROM shipments WHERE order_id = \\$1;$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectQuery("^SELECT id FROM orders WHERE id = \\$1 FOR UPDATE$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+				mock.ExpectQuery("^SELECT EXISTS (.+)$").
+					WithArgs(4, domain.StockCancellation, domain.StockReturn).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				mock.ExpectQuery("^SELECT \\* FROM order_lines (.+)$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "product_item_id", "order_id", "quantity"}).AddRow(1, 7, 4, 2))
+				mock.ExpectExec("^UPDATE product_items SET qnty_in_stock = qnty_in_stock \\+ \\$1 WHERE id = \\$2$").
+					WithArgs(2, 7).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("^INSERT INTO stock_movements (.+)$").
+					WithArgs(7, 2, domain.StockCancellation, 0, 4, "").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec("^UPDATE coupon_redemptions SET released_at = NOW\\(\\), (.+)$").
+					WithArgs(4).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("^SELECT \\* FROM payment_details WHERE order_id = \\$1 FOR UPDATE;$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "order_total", "payment_method_id", "payment_status_id"}).AddRow(9, 4, 54000, 2, 5))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{ //test case for an order paid for after it was found stale, it is left as it is
+			name:           "paid meanwhile",
+			expectedOutput: domain.Order{},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^UPDATE payment_details SET (.+)$").
+					WithArgs(domain.PaymentExpired, 4, domain.PaymentPending, domain.PaymentFailed).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectRollback()
+			},
+			expectedErr: errors.New("payment is no longer pending"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when initializing a mock db session", err)
+			}
+
+			paymentRepository := NewPaymentRepository(gormDB)
+			tt.buildStub(mock)
+
+			actualOutput, _, actualErr := paymentRepository.ExpirePayment(context.TODO(), 4)
+			assert.Equal(t, tt.expectedErr, actualErr)
+			assert.Equal(t, tt.expectedOutput, actualOutput)
+
+			err = mock.ExpectationsWereMet()
+			if err != nil {
+				t.Errorf("Unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
@@ -7,7 +7,6 @@ import (
 	interfaces "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/interface"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
 	"gorm.io/gorm"
-	"strings"
 )
 
 type productDatabase struct {
@@ -24,7 +23,7 @@ func (c *productDatabase) CreateCategory(ctx context.Context, newCategory string
 	var createdCategory domain.ProductCategory
 	categoryCreateQuery := `INSERT INTO product_categories(category_name)
 							VALUES($1)
-							RETURNING id,category_name`
+							RETURNING id, category_name, tax_percent`
 	err := c.DB.Raw(categoryCreateQuery, newCategory).Scan(&createdCategory).Error
 	return createdCategory, err
 }
@@ -34,7 +33,7 @@ func (c *productDatabase) ViewAllCategories(ctx context.Context) ([]domain.Produ
 	var allCategories []domain.ProductCategory
 
 	// Construct the SQL query to fetch all the categories from the product_categories table.
-	findAllQuery := `SELECT * FROM product_categories;`
+	findAllQuery := `SELECT id, category_name, tax_percent FROM product_categories;`
 
 	// Execute the query and get a reference to the result set.
 	rows, err := c.DB.Raw(findAllQuery).Rows()
@@ -51,7 +50,7 @@ func (c *productDatabase) ViewAllCategories(ctx context.Context) ([]domain.Produ
 		var category domain.ProductCategory
 
 		// Scan the values from the current row into the fields of the ProductCategory struct.
-		err := rows.Scan(&category.ID, &category.CategoryName)
+		err := rows.Scan(&category.ID, &category.CategoryName, &category.TaxPercent)
 		if err != nil {
 			// If an error occurs while scanning the row, return the categories we have so far and the error.
 			return allCategories, err
@@ -74,11 +73,11 @@ func (c *productDatabase) FindCategoryByID(ctx context.Context, id int) (domain.
 func (c *productDatabase) UpdateCategory(ctx context.Context, info domain.ProductCategory) (domain.ProductCategory, error) {
 	var updatedCategory domain.ProductCategory
 	updateCategoryQuery := `UPDATE product_categories
-							SET category_name = $1
-							WHERE id = $2
-							RETURNING id, category_name`
+							SET category_name = $1, tax_percent = $2
+							WHERE id = $3
+							RETURNING id, category_name, tax_percent`
 
-	err := c.DB.Raw(updateCategoryQuery, info.CategoryName, info.ID).Scan(&updatedCategory).Error
+	err := c.DB.Raw(updateCategoryQuery, info.CategoryName, info.TaxPercent, info.ID).Scan(&updatedCategory).Error
 
 	return updatedCategory, err
 }
@@ -150,26 +149,13 @@ func (c *productDatabase) CreateProduct(ctx context.Context, newProduct domain.P
 
 func (c *productDatabase) ViewAllProducts(ctx context.Context, queryParams model.QueryParams) ([]domain.Product, error) {
 
-	findQuery := "SELECT * FROM products"
-	if queryParams.Query != "" && queryParams.Filter != "" {
-		findQuery = fmt.Sprintf("%s WHERE LOWER(%s) LIKE '%%%s%%'", findQuery, queryParams.Filter, strings.ToLower(queryParams.Query))
-	}
-	if queryParams.SortBy != "" {
-		if queryParams.SortDesc {
-			findQuery = fmt.Sprintf("%s ORDER BY %s DESC", findQuery, queryParams.SortBy)
-		} else {
-			findQuery = fmt.Sprintf("%s ORDER BY %s ASC", findQuery, queryParams.SortBy)
-		}
-	}
-	if queryParams.Limit != 0 && queryParams.Page != 0 {
-		findQuery = fmt.Sprintf("%s LIMIT %d OFFSET %d", findQuery, queryParams.Limit, (queryParams.Page-1)*queryParams.Limit)
-	}
-	if queryParams.Limit == 0 || queryParams.Page == 0 {
-		findQuery = fmt.Sprintf("%s LIMIT 10 OFFSET 0", findQuery)
+	findQuery, args, err := buildListingQuery("SELECT id, product_category_id, name, brand_id, description, product_image FROM products", productColumns, queryParams)
+	if err != nil {
+		return nil, err
 	}
 
 	var allProducts []domain.Product
-	rows, err := c.DB.Raw(findQuery).Rows()
+	rows, err := c.DB.Raw(findQuery, args...).Rows()
 	if err != nil {
 		return allProducts, err
 	}
@@ -221,36 +207,51 @@ func (c *productDatabase) DeleteProduct(ctx context.Context, productID int) erro
 //product item management
 
 func (c *productDatabase) CreateProductItem(ctx context.Context, newProductItem domain.ProductItem) (domain.ProductItem, error) {
+	tx := c.DB.Begin()
+
 	var createdProductItem domain.ProductItem
-	productItemCreateQuery := `INSERT INTO product_items(product_id, model, processor, ram, storage, display_size, graphics_card, os, sku, qnty_in_stock, product_item_image, price)
-							VALUES( $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	productItemCreateQuery := `INSERT INTO product_items(product_id, model, processor, ram, storage, display_size, graphics_card, os, sku, qnty_in_stock, product_item_image, price, max_per_order)
+							VALUES( $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 							RETURNING *`
-	err := c.DB.Raw(productItemCreateQuery, newProductItem.ProductID, newProductItem.Model, newProductItem.Processor, newProductItem.Ram, newProductItem.Storage, newProductItem.DisplaySize, newProductItem.GraphicsCard, newProductItem.OS, newProductItem.SKU, newProductItem.QntyInStock, newProductItem.ProductItemImage, newProductItem.Price).Scan(&createdProductItem).Error
-	return createdProductItem, err
-}
+	err := tx.Raw(productItemCreateQuery, newProductItem.ProductID, newProductItem.Model, newProductItem.Processor, newProductItem.Ram, newProductItem.Storage, newProductItem.DisplaySize, newProductItem.GraphicsCard, newProductItem.OS, newProductItem.SKU, newProductItem.QntyInStock, newProductItem.ProductItemImage, newProductItem.Price, newProductItem.MaxPerOrder).Scan(&createdProductItem).Error
+	if err != nil {
+		tx.Rollback()
+		return domain.ProductItem{}, err
+	}
 
-func (c *productDatabase) ViewAllProductItems(ctx context.Context, queryParams model.QueryParams) ([]domain.ProductItem, error) {
-	// Building query based on query params received.
-	findQuery := "SELECT * FROM product_items"
-	if queryParams.Query != "" && queryParams.Filter != "" {
-		findQuery = fmt.Sprintf("%s WHERE LOWER(%s) LIKE '%%%s%%'", findQuery, queryParams.Filter, strings.ToLower(queryParams.Query))
-	}
-	if queryParams.SortBy != "" {
-		if queryParams.SortDesc {
-			findQuery = fmt.Sprintf("%s ORDER BY %s DESC", findQuery, queryParams.SortBy)
-		} else {
-			findQuery = fmt.Sprintf("%s ORDER BY %s ASC", findQuery, queryParams.SortBy)
+	//opening stock of the product item is recorded as a restock in the inventory ledger
+	if createdProductItem.QntyInStock != 0 {
+		_, err = recordStockMovement(tx, domain.StockMovement{
+			ProductItemID: createdProductItem.ID,
+			Quantity:      createdProductItem.QntyInStock,
+			Reason:        domain.StockRestock,
+			Note:          "opening stock",
+		})
+		if err != nil {
+			tx.Rollback()
+			return domain.ProductItem{}, err
 		}
 	}
-	if queryParams.Limit != 0 && queryParams.Page != 0 {
-		findQuery = fmt.Sprintf("%s LIMIT %d OFFSET %d", findQuery, queryParams.Limit, (queryParams.Page-1)*queryParams.Limit)
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return domain.ProductItem{}, err
 	}
-	if queryParams.Limit == 0 || queryParams.Page == 0 {
-		findQuery = fmt.Sprintf("%s LIMIT 10 OFFSET 0", findQuery)
+	return createdProductItem, nil
+}
+
+func (c *productDatabase) ViewAllProductItems(ctx context.Context, queryParams model.QueryParams) ([]domain.ProductItem, error) {
+	// Building query based on query params received.
+	findQuery := `	SELECT id, product_id, model, processor, ram, storage, display_size, graphics_card, os, sku, qnty_in_stock,
+						product_item_image, price
+					FROM product_items`
+	findQuery, args, err := buildListingQuery(findQuery, productItemColumns, queryParams)
+	if err != nil {
+		return nil, err
 	}
 
 	var allProductItems []domain.ProductItem
-	rows, err := c.DB.Raw(findQuery).Rows()
+	rows, err := c.DB.Raw(findQuery, args...).Rows()
 	if err != nil {
 		return nil, err
 	}
@@ -276,6 +277,8 @@ func (c *productDatabase) FindProductItemByID(ctx context.Context, id int) (doma
 	return productItem, err
 }
 
+// UpdateProductItem updates the details of a product item. Stock is not changed here, it can only be changed through
+// the inventory ledger, so that every change to the stock has a recorded reason.
 func (c *productDatabase) UpdateProductItem(ctx context.Context, info domain.ProductItem) (domain.ProductItem, error) {
 	var updatedProductItem domain.ProductItem
 	updateProductItemQuery := `	UPDATE product_items
@@ -289,13 +292,13 @@ func (c *productDatabase) UpdateProductItem(ctx context.Context, info domain.Pro
 									graphics_card = $7, 
 									os = $8,
 									sku = $9, 
-									qnty_in_stock = $10, 
-									product_item_image = $11, 
-									price = $12
+									product_item_image = $10, 
+									price = $11,
+									max_per_order = $12
 								WHERE id = $13
-								RETURNING id, product_id, model, processor, ram, storage, display_size, graphics_card, os, sku, qnty_in_stock, product_item_image, price`
+								RETURNING id, product_id, model, processor, ram, storage, display_size, graphics_card, os, sku, qnty_in_stock, product_item_image, price, max_per_order`
 	//Todo : fix scanning bug
-	err := c.DB.Raw(updateProductItemQuery, info.ProductID, info.Model, info.Processor, info.Ram, info.Storage, info.DisplaySize, info.GraphicsCard, info.OS, info.SKU, info.QntyInStock, info.ProductItemImage, info.Price, info.ID).Scan(&updatedProductItem).Error
+	err := c.DB.Raw(updateProductItemQuery, info.ProductID, info.Model, info.Processor, info.Ram, info.Storage, info.DisplaySize, info.GraphicsCard, info.OS, info.SKU, info.ProductItemImage, info.Price, info.MaxPerOrder, info.ID).Scan(&updatedProductItem).Error
 	return updatedProductItem, err
 }
 
@@ -308,76 +311,149 @@ func (c *productDatabase) DeleteProductItem(ctx context.Context, productItemID i
 
 // coupon management
 
-func (c *productDatabase) CreateCoupon(ctx context.Context, newCoupon model.CreateCoupon) (domain.Coupon, error) {
-	var createdCoupon domain.Coupon
-	createCouponQuery := `	INSERT INTO coupons(code, min_order_value, discount_percent, discount_max_amount, valid_till) 
-							VALUES($1, $2, $3, $4, $5) 
+func (c *productDatabase) CreateCoupon(ctx context.Context, newCoupon model.CreateCoupon) (model.CouponDetails, error) {
+	tx := c.DB.Begin()
+
+	var createdCoupon model.CouponDetails
+	createCouponQuery := `	INSERT INTO coupons(code, min_order_value, discount_type, discount_percent, discount_max_amount, discount_amount,
+								scope, valid_from, valid_till, usage_limit, per_user_limit, first_order_only)
+							VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 							RETURNING *;`
-	err := c.DB.Raw(createCouponQuery, newCoupon.Code, newCoupon.MinOrderValue, newCoupon.DiscountPercent, newCoupon.DiscountMaxAmount, newCoupon.ValidTill).Scan(&createdCoupon).Error
+	err := tx.Raw(createCouponQuery, newCoupon.Code, newCoupon.MinOrderValue, newCoupon.DiscountType, newCoupon.DiscountPercent,
+		newCoupon.DiscountMaxAmount, newCoupon.DiscountAmount, newCoupon.Scope, newCoupon.ValidFrom, newCoupon.ValidTill,
+		newCoupon.UsageLimit, *newCoupon.PerUserLimit, newCoupon.FirstOrderOnly).Scan(&createdCoupon.Coupon).Error
 	if err != nil {
-		return domain.Coupon{}, err
+		tx.Rollback()
+		return model.CouponDetails{}, err
 	}
 	if createdCoupon.ID == 0 {
-		return domain.Coupon{}, fmt.Errorf("failed to create new coupon")
+		tx.Rollback()
+		return model.CouponDetails{}, fmt.Errorf("failed to create new coupon")
+	}
+
+	if err := saveCouponRules(tx, createdCoupon.ID, newCoupon.TargetIDs, newCoupon.UserIDs); err != nil {
+		tx.Rollback()
+		return model.CouponDetails{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return model.CouponDetails{}, err
 	}
+	createdCoupon.TargetIDs = newCoupon.TargetIDs
+	createdCoupon.UserIDs = newCoupon.UserIDs
 	return createdCoupon, nil
 }
 
-func (c *productDatabase) UpdateCoupon(ctx context.Context, couponInfo model.UpdateCoupon) (domain.Coupon, error) {
-	var updatedCoupon domain.Coupon
+func (c *productDatabase) UpdateCoupon(ctx context.Context, couponInfo model.UpdateCoupon) (model.CouponDetails, error) {
+	tx := c.DB.Begin()
+
+	var updatedCoupon model.CouponDetails
 	updateCouponQuery := `	UPDATE coupons SET 
 								code = $1,
 								min_order_value = $2,
-								discount_percent = $3,
-								discount_max_amount = $4,
-								valid_till = $5
-							WHERE id = $6
+								discount_type = $3,
+								discount_percent = $4,
+								discount_max_amount = $5,
+								discount_amount = $6,
+								scope = $7,
+								valid_from = $8,
+								valid_till = $9,
+								usage_limit = $10,
+								per_user_limit = $11,
+								first_order_only = $12
+							WHERE id = $13
 							RETURNING *;`
-	err := c.DB.Raw(updateCouponQuery, couponInfo.Code, couponInfo.MinOrderValue, couponInfo.DiscountPercent, couponInfo.DiscountMaxAmount, couponInfo.ValidTill, couponInfo.ID).Scan(&updatedCoupon).Error
-
+	err := tx.Raw(updateCouponQuery, couponInfo.Code, couponInfo.MinOrderValue, couponInfo.DiscountType, couponInfo.DiscountPercent,
+		couponInfo.DiscountMaxAmount, couponInfo.DiscountAmount, couponInfo.Scope, couponInfo.ValidFrom, couponInfo.ValidTill,
+		couponInfo.UsageLimit, *couponInfo.PerUserLimit, couponInfo.FirstOrderOnly, couponInfo.ID).Scan(&updatedCoupon.Coupon).Error
 	if err != nil {
-		return domain.Coupon{}, err
+		tx.Rollback()
+		return model.CouponDetails{}, err
 	}
-
 	if updatedCoupon.ID == 0 {
-		return domain.Coupon{}, fmt.Errorf("no product found")
+		tx.Rollback()
+		return model.CouponDetails{}, fmt.Errorf("no coupon found")
+	}
+
+	//the targets and users of the coupon are replaced with the given ones
+	if err := deleteCouponRules(tx, updatedCoupon.ID); err != nil {
+		tx.Rollback()
+		return model.CouponDetails{}, err
 	}
+	if err := saveCouponRules(tx, updatedCoupon.ID, couponInfo.TargetIDs, couponInfo.UserIDs); err != nil {
+		tx.Rollback()
+		return model.CouponDetails{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return model.CouponDetails{}, err
+	}
+	updatedCoupon.TargetIDs = couponInfo.TargetIDs
+	updatedCoupon.UserIDs = couponInfo.UserIDs
 	return updatedCoupon, nil
 }
 
 func (c *productDatabase) DeleteCoupon(ctx context.Context, couponID int) error {
-	var fetchedID int
+	tx := c.DB.Begin()
+
+	var fetchedID uint
 	findCouponQuery := `SELECT id FROM coupons WHERE id = $1`
-	err := c.DB.Raw(findCouponQuery, couponID).Scan(&fetchedID).Error
+	err := tx.Raw(findCouponQuery, couponID).Scan(&fetchedID).Error
 	if err != nil {
+		tx.Rollback()
 		return err
 	}
 	if fetchedID == 0 {
+		tx.Rollback()
 		return fmt.Errorf("no such coupon found")
 	}
 
+	if err := deleteCouponRules(tx, fetchedID); err != nil {
+		tx.Rollback()
+		return err
+	}
 	deleteCouponQuery := `DELETE FROM coupons WHERE id = $1;`
-	err = c.DB.Exec(deleteCouponQuery, couponID).Error
-	return err
+	if err := tx.Exec(deleteCouponQuery, couponID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
 
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
 }
 
-func (c *productDatabase) ViewCouponByID(ctx context.Context, couponID int) (domain.Coupon, error) {
-	var coupon domain.Coupon
+func (c *productDatabase) ViewCouponByID(ctx context.Context, couponID int) (model.CouponDetails, error) {
+	var coupon model.CouponDetails
 	fetchCouponQuery := `SELECT * FROM coupons WHERE id = $1;`
-	err := c.DB.Raw(fetchCouponQuery, couponID).Scan(&coupon).Error
+	err := c.DB.Raw(fetchCouponQuery, couponID).Scan(&coupon.Coupon).Error
 	if err != nil {
-		return domain.Coupon{}, err
+		return model.CouponDetails{}, err
 	}
 	if coupon.ID == 0 {
-		return domain.Coupon{}, fmt.Errorf("no coupon found")
+		return model.CouponDetails{}, fmt.Errorf("no coupon found")
+	}
+
+	err = c.DB.Raw("SELECT target_id FROM coupon_targets WHERE coupon_id = $1", couponID).Scan(&coupon.TargetIDs).Error
+	if err != nil {
+		return model.CouponDetails{}, err
+	}
+	err = c.DB.Raw("SELECT user_id FROM coupon_users WHERE coupon_id = $1", couponID).Scan(&coupon.UserIDs).Error
+	if err != nil {
+		return model.CouponDetails{}, err
 	}
 	return coupon, nil
 }
 
+// ViewAllCoupons lists the coupons that are still valid. The single use codes of campaigns are handed out by the campaign
+// and are left out.
 func (c *productDatabase) ViewAllCoupons(ctx context.Context) ([]domain.Coupon, error) {
 	var allCoupons []domain.Coupon
-	fetchAllCouponsQuery := `SELECT * FROM coupons WHERE valid_till > NOW();`
+	fetchAllCouponsQuery := `SELECT * FROM coupons WHERE valid_till > NOW() AND campaign_id IS NULL;`
 	err := c.DB.Raw(fetchAllCouponsQuery).Scan(&allCoupons).Error
 	if err != nil {
 		return allCoupons, err
@@ -388,14 +464,35 @@ func (c *productDatabase) ViewAllCoupons(ctx context.Context) ([]domain.Coupon,
 	return allCoupons, err
 }
 
+// CouponUsed tells if the user has a redemption of the coupon that was not given back by cancelling its order
 func (c *productDatabase) CouponUsed(ctx context.Context, userID, couponID int) (bool, error) {
 	var isUsed bool
 	checkQuery := `	SELECT 
 					EXISTS(
-							SELECT 1 FROM orders 
+							SELECT 1 FROM coupon_redemptions 
 							WHERE user_id = $1 AND 
-							coupon_id = $2
+							coupon_id = $2 AND
+							released_at IS NULL
 					);`
 	err := c.DB.Raw(checkQuery, userID, couponID).Scan(&isUsed).Error
 	return isUsed, err
 }
+
+// CouponRedemptionReport lists how many times each coupon was redeemed and what its discounts cost. Redemptions of
+// coupons that were deleted are listed without a code.
+func (c *productDatabase) CouponRedemptionReport(ctx context.Context) ([]model.CouponRedemptionReport, error) {
+	var report []model.CouponRedemptionReport
+	reportQuery := `	SELECT
+							COALESCE(c.id, r.coupon_id) AS coupon_id,
+							COALESCE(c.code, '') AS code,
+							COALESCE(c.usage_limit, 0) AS usage_limit,
+							COUNT(r.id) FILTER (WHERE r.released_at IS NULL) AS redemptions,
+							COUNT(r.id) FILTER (WHERE r.released_at IS NOT NULL) AS released,
+							COALESCE(SUM(r.discount) FILTER (WHERE r.released_at IS NULL), 0) AS discount_cost
+						FROM coupons c
+						FULL OUTER JOIN coupon_redemptions r ON r.coupon_id = c.id
+						GROUP BY COALESCE(c.id, r.coupon_id), c.code, c.usage_limit
+						ORDER BY discount_cost DESC, coupon_id;`
+	err := c.DB.Raw(reportQuery).Scan(&report).Error
+	return report, err
+}
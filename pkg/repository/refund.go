@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	interfaces "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/interface"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"gorm.io/gorm"
+	"math"
+)
+
+type refundDatabase struct {
+	DB *gorm.DB
+}
+
+func NewRefundRepository(DB *gorm.DB) interfaces.RefundRepository {
+	return &refundDatabase{DB}
+}
+
+// CreateRefund initiates a refund of a part of the amount paid for an order
+func (c *refundDatabase) CreateRefund(ctx context.Context, newRefund model.CreateRefund) (domain.Refund, error) {
+	tx := c.DB.Begin()
+
+	refund, err := initiateRefund(tx, uint(newRefund.OrderID), 0, newRefund.Amount, newRefund.Reason)
+	if err != nil {
+		tx.Rollback()
+		return domain.Refund{}, err
+	}
+	if refund.ID == 0 {
+		tx.Rollback()
+		return domain.Refund{}, fmt.Errorf("order is not paid for or is already refunded")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return domain.Refund{}, err
+	}
+	return refund, nil
+}
+
+func (c *refundDatabase) ListRefunds(ctx context.Context, status string) ([]domain.Refund, error) {
+	var refunds []domain.Refund
+	listRefundsQuery := `SELECT * FROM refunds WHERE $1 = '' OR status = $1 ORDER BY id DESC;`
+	err := c.DB.Raw(listRefundsQuery, status).Scan(&refunds).Error
+	return refunds, err
+}
+
+func (c *refundDatabase) ViewOrderRefunds(ctx context.Context, orderID int) ([]domain.Refund, error) {
+	var refunds []domain.Refund
+	fetchRefundsQuery := `SELECT * FROM refunds WHERE order_id = $1 ORDER BY id;`
+	err := c.DB.Raw(fetchRefundsQuery, orderID).Scan(&refunds).Error
+	return refunds, err
+}
+
+// RetryRefund initiates a failed refund again. The payment is locked as in initiateRefund, so that a refund created
+// while this one had failed and this one together cannot refund more than was paid.
+func (c *refundDatabase) RetryRefund(ctx context.Context, refundID int) (domain.Refund, error) {
+	tx := c.DB.Begin()
+
+	var refund domain.Refund
+	fetchRefundQuery := `SELECT * FROM refunds WHERE id = $1;`
+	if err := tx.Raw(fetchRefundQuery, refundID).Scan(&refund).Error; err != nil {
+		tx.Rollback()
+		return domain.Refund{}, err
+	}
+	if refund.ID == 0 {
+		tx.Rollback()
+		return domain.Refund{}, fmt.Errorf("no refund found")
+	}
+
+	paymentDetails, err := lockPayment(tx, refund.OrderID)
+	if err != nil {
+		tx.Rollback()
+		return domain.Refund{}, err
+	}
+	left, err := amountLeftToRefund(tx, paymentDetails, refund.ReturnID != 0)
+	if err != nil {
+		tx.Rollback()
+		return domain.Refund{}, err
+	}
+	if refund.Status == domain.RefundFailed && refund.Amount > left.Gateway {
+		tx.Rollback()
+		return domain.Refund{}, fmt.Errorf("refund amount is more than the %v left to refund", left.Gateway)
+	}
+
+	var retriedRefund domain.Refund
+	retryRefundQuery := `	UPDATE refunds SET status = $1, refund_ref = '', failure_reason = '', updated_at = NOW()
+							WHERE id = $2 AND status = $3 RETURNING *;`
+	err = tx.Raw(retryRefundQuery, domain.RefundInitiated, refund.ID, domain.RefundFailed).Scan(&retriedRefund).Error
+	if err != nil {
+		tx.Rollback()
+		return domain.Refund{}, err
+	}
+	if retriedRefund.ID == 0 {
+		tx.Rollback()
+		return domain.Refund{}, fmt.Errorf("refund is %v, only a failed refund can be retried", refund.Status)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return domain.Refund{}, err
+	}
+	return retriedRefund, nil
+}
+
+// SetRefundRef stores the id the payment gateway gave an initiated refund
+func (c *refundDatabase) SetRefundRef(ctx context.Context, refundID uint, refundRef string) error {
+	setRefundRefQuery := `UPDATE refunds SET refund_ref = $1, updated_at = NOW() WHERE id = $2 AND status = $3;`
+	return c.DB.Exec(setRefundRefQuery, refundRef, refundID, domain.RefundInitiated).Error
+}
+
+// MarkRefundFailed marks an initiated refund the payment gateway did not accept as failed
+func (c *refundDatabase) MarkRefundFailed(ctx context.Context, refundID uint, reason string) error {
+	failRefundQuery := `UPDATE refunds SET status = $1, failure_reason = $2, updated_at = NOW() WHERE id = $3 AND status = $4;`
+	return c.DB.Exec(failRefundQuery, domain.RefundFailed, reason, refundID, domain.RefundInitiated).Error
+}
+
+// initiateRefund creates a refund against the payment of an order. An amount of 0 refunds everything that is not
+// refunded yet. The part of the order paid online is refunded through the payment gateway first, the rest is credited
+// to the wallet of the user right away. When a refund is split between the two, the refund to be sent to the gateway
+// is returned. A zero refund is returned for an order that is not paid for or has nothing left to refund. The payment
+// row is locked, so that concurrent refunds of an order cannot refund more than was paid.
+func initiateRefund(tx *gorm.DB, orderID, returnID uint, amount float64, reason string) (domain.Refund, error) {
+	paymentDetails, err := lockPayment(tx, orderID)
+	if err != nil {
+		return domain.Refund{}, err
+	}
+	left, err := amountLeftToRefund(tx, paymentDetails, returnID != 0)
+	if err != nil {
+		return domain.Refund{}, err
+	}
+	if amount < 0 {
+		return domain.Refund{}, fmt.Errorf("invalid refund amount")
+	}
+	if left.Total <= 0 {
+		return domain.Refund{}, nil
+	}
+	if amount == 0 {
+		amount = left.Total
+	}
+	if amount > left.Total {
+		return domain.Refund{}, fmt.Errorf("refund amount is more than the %v left to refund", left.Total)
+	}
+
+	gatewayAmount := math.Min(amount, math.Max(left.Gateway, 0))
+	walletAmount := roundToPaise(amount - gatewayAmount)
+
+	var refund domain.Refund
+	if walletAmount > 0 {
+		refund, err = refundToWallet(tx, paymentDetails, returnID, walletAmount, reason)
+		if err != nil {
+			return domain.Refund{}, err
+		}
+	}
+	if gatewayAmount > 0 {
+		refund, err = createRefund(tx, domain.Refund{
+			OrderID:          orderID,
+			PaymentDetailsID: paymentDetails.ID,
+			ReturnID:         returnID,
+			Amount:           gatewayAmount,
+			Reason:           reason,
+			Destination:      domain.RefundToGateway,
+			Status:           domain.RefundInitiated,
+		})
+		if err != nil {
+			return domain.Refund{}, err
+		}
+	}
+	return refund, nil
+}
+
+// refundToWallet credits a refund to the wallet of the user who placed the order. The refund is processed as soon
+// as it is credited.
+func refundToWallet(tx *gorm.DB, paymentDetails domain.PaymentDetails, returnID uint, amount float64, reason string) (domain.Refund, error) {
+	var userID uint
+	findUserQuery := `SELECT user_id FROM orders WHERE id = $1;`
+	if err := tx.Raw(findUserQuery, paymentDetails.OrderID).Scan(&userID).Error; err != nil {
+		return domain.Refund{}, err
+	}
+
+	refund, err := createRefund(tx, domain.Refund{
+		OrderID:          paymentDetails.OrderID,
+		PaymentDetailsID: paymentDetails.ID,
+		ReturnID:         returnID,
+		Amount:           amount,
+		Reason:           reason,
+		Destination:      domain.RefundToWallet,
+		Status:           domain.RefundProcessed,
+	})
+	if err != nil {
+		return domain.Refund{}, err
+	}
+	_, err = postWalletEntry(tx, domain.WalletEntry{
+		UserID:   userID,
+		Amount:   amount,
+		Reason:   domain.WalletRefund,
+		OrderID:  paymentDetails.OrderID,
+		RefundID: refund.ID,
+		Note:     reason,
+	})
+	if err != nil {
+		return domain.Refund{}, err
+	}
+	return refund, markPaymentRefunded(tx, paymentDetails.ID)
+}
+
+func createRefund(tx *gorm.DB, refund domain.Refund) (domain.Refund, error) {
+	var createdRefund domain.Refund
+	createRefundQuery := `	INSERT INTO refunds (order_id, payment_details_id, return_id, amount, reason, destination, status, refund_ref, failure_reason, created_at, updated_at)
+							VALUES ($1, $2, $3, $4, $5, $6, $7, '', '', NOW(), NOW()) RETURNING *;`
+	err := tx.Raw(createRefundQuery, refund.OrderID, refund.PaymentDetailsID, refund.ReturnID, refund.Amount, refund.Reason, refund.Destination, refund.Status).Scan(&createdRefund).Error
+	return createdRefund, err
+}
+
+// markPaymentRefunded marks a completed payment as refunded once everything paid for it is refunded
+func markPaymentRefunded(tx *gorm.DB, paymentDetailsID uint) error {
+	refundPaymentQuery := `	UPDATE payment_details pd SET payment_status_id = $1, updated_at = NOW()
+							WHERE pd.id = $2 AND pd.payment_status_id = $3 AND ROUND(pd.order_total * 100) <= (
+								SELECT ROUND(COALESCE(SUM(r.amount), 0) * 100) FROM refunds r
+								WHERE r.payment_details_id = pd.id AND r.status = $4
+							);`
+	return tx.Exec(refundPaymentQuery, domain.PaymentRefunded, paymentDetailsID, domain.PaymentCompleted, domain.RefundProcessed).Error
+}
+
+// lockPayment reads the payment of an order and locks it until the transaction ends
+func lockPayment(tx *gorm.DB, orderID uint) (domain.PaymentDetails, error) {
+	var paymentDetails domain.PaymentDetails
+	lockPaymentQuery := `SELECT * FROM payment_details WHERE order_id = $1 FOR UPDATE;`
+	if err := tx.Raw(lockPaymentQuery, orderID).Scan(&paymentDetails).Error; err != nil {
+		return domain.PaymentDetails{}, err
+	}
+	if paymentDetails.ID == 0 {
+		return domain.PaymentDetails{}, fmt.Errorf("no payment found for the order")
+	}
+	return paymentDetails, nil
+}
+
+// refundable is what is left to refund of the payment of an order, in total and through the payment gateway
+type refundable struct {
+	Total   float64
+	Gateway float64
+}
+
+// amountLeftToRefund works out what is left to refund of a payment, less its refunds that have not failed. Until the
+// payment of an order is completed only the part paid from the wallet is paid, except for returned orders paid in
+// cash on delivery. The part of a completed online payment not paid from the wallet is paid through the gateway.
+func amountLeftToRefund(tx *gorm.DB, paymentDetails domain.PaymentDetails, returned bool) (refundable, error) {
+	paid, paidOnline := paymentDetails.WalletAmount, 0.0
+	switch {
+	case paymentDetails.PaymentStatusID == domain.PaymentCompleted || paymentDetails.PaymentStatusID == domain.PaymentRefunded:
+		paid = paymentDetails.OrderTotal
+		if paymentDetails.PaymentMethodID == domain.PaymentOnline {
+			paidOnline = paymentDetails.AmountDue()
+		}
+	case paymentDetails.PaymentMethodID == domain.PaymentCOD && returned:
+		paid = paymentDetails.OrderTotal
+	}
+	if paid == 0 {
+		return refundable{}, nil
+	}
+
+	var refunded refundable
+	refundedQuery := `	SELECT COALESCE(SUM(amount), 0) AS total, COALESCE(SUM(amount) FILTER (WHERE destination = $3), 0) AS gateway
+						FROM refunds WHERE payment_details_id = $1 AND status != $2;`
+	if err := tx.Raw(refundedQuery, paymentDetails.ID, domain.RefundFailed, domain.RefundToGateway).Scan(&refunded).Error; err != nil {
+		return refundable{}, err
+	}
+	return refundable{
+		Total:   roundToPaise(paid - refunded.Total),
+		Gateway: roundToPaise(paidOnline - refunded.Gateway),
+	}, nil
+}
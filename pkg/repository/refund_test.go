@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func TestCreateRefund(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          model.CreateRefund
+		expectedOutput domain.Refund
+		buildStub      func(mock sqlmock.Sqlmock)
+		expectedErr    error
+	}{
+		{ //test case for refunding a part of a paid order
+			name:           "partial refund",
+			input:          model.CreateRefund{OrderID: 4, Amount: 2000, Reason: "damaged box"},
+			expectedOutput: domain.Refund{ID: 6, OrderID: 4, PaymentDetailsID: 9, Amount: 2000, Reason: "damaged box", Destination: domain.RefundToGateway, Status: domain.RefundInitiated},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^SELECT \\* FROM payment_details WHERE order_id = \\$1 FOR UPDATE;$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "order_total", "payment_method_id", "payment_status_id"}).AddRow(9, 4, 54000, 2, 2))
+				mock.ExpectQuery("^SELECT COALESCE\\(SUM\\(amount\\), 0\\) AS total, (.+) FROM refunds (.+)$").
+					WithArgs(9, domain.RefundFailed, domain.RefundToGateway).
+					WillReturnRows(sqlmock.NewRows([]string{"total", "gateway"}).AddRow(50000, 50000))
+				mock.ExpectQuery("^INSERT INTO refunds (.+)$").
+					WithArgs(4, 9, 0, float64(2000), "damaged box", domain.RefundToGateway, domain.RefundInitiated).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "payment_details_id", "amount", "reason", "destination", "status"}).AddRow(6, 4, 9, 2000, "damaged box", "gateway", "initiated"))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{ //test case for refunding more than is left to refund
+			name:           "more than paid",
+			input:          model.CreateRefund{OrderID: 4, Amount: 5000, Reason: "damaged box"},
+			expectedOutput: domain.Refund{},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^SELECT \\* FROM payment_details WHERE order_id = \\$1 FOR UPDATE;$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "order_total", "payment_method_id", "payment_status_id"}).AddRow(9, 4, 54000, 2, 2))
+				mock.ExpectQuery("^SELECT COALESCE\\(SUM\\(amount\\), 0\\) AS total, (.+) FROM refunds (.+)$").
+					WithArgs(9, domain.RefundFailed, domain.RefundToGateway).
+					WillReturnRows(sqlmock.NewRows([]string{"total", "gateway"}).AddRow(50000, 50000))
+				mock.ExpectRollback()
+			},
+			expectedErr: errors.New("refund amount is more than the 4000 left to refund"),
+		},
+		{ //test case for refunding an order that is not paid for yet
+			name:           "unpaid order",
+			input:          model.CreateRefund{OrderID: 5, Amount: 2000, Reason: "damaged box"},
+			expectedOutput: domain.Refund{},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^SELECT \\* FROM payment_details WHERE order_id = \\$1 FOR UPDATE;$").
+					WithArgs(5).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "order_total", "payment_method_id", "payment_status_id"}).AddRow(10, 5, 54000, 1, 1))
+				mock.ExpectRollback()
+			},
+			expectedErr: errors.New("order is not paid for or is already refunded"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when initializing a mock db session", err)
+			}
+
+			refundRepository := NewRefundRepository(gormDB)
+			tt.buildStub(mock)
+
+			actualOutput, actualErr := refundRepository.CreateRefund(context.TODO(), tt.input)
+			assert.Equal(t, tt.expectedErr, actualErr)
+			assert.Equal(t, tt.expectedOutput, actualOutput)
+
+			err = mock.ExpectationsWereMet()
+			if err != nil {
+				t.Errorf("Unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	interfaces "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/interface"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"gorm.io/gorm"
+	"time"
+)
+
+type shipmentDatabase struct {
+	DB *gorm.DB
+}
+
+func NewShipmentRepository(DB *gorm.DB) interfaces.ShipmentRepository {
+	return &shipmentDatabase{DB}
+}
+
+func (c *shipmentDatabase) CreateCarrier(ctx context.Context, newCarrier model.CreateCarrier) (domain.Carrier, error) {
+	var createdCarrier domain.Carrier
+	createCarrierQuery := `INSERT INTO carriers (name, tracking_url) VALUES ($1, $2) RETURNING *;`
+	err := c.DB.Raw(createCarrierQuery, newCarrier.Name, newCarrier.TrackingURL).Scan(&createdCarrier).Error
+	return createdCarrier, err
+}
+
+func (c *shipmentDatabase) ListCarriers(ctx context.Context) ([]domain.Carrier, error) {
+	var carriers []domain.Carrier
+	listCarriersQuery := `SELECT * FROM carriers ORDER BY name;`
+	err := c.DB.Raw(listCarriersQuery).Scan(&carriers).Error
+	return carriers, err
+}
+
+// CreateShipment hands over order lines of a pending order to a carrier. The order row is locked, so that the quantities
+// still to be shipped cannot change until the shipment is committed. Without lines, everything not yet shipped is sent.
+func (c *shipmentDatabase) CreateShipment(ctx context.Context, newShipment model.CreateShipment) (model.ShipmentDetails, error) {
+	tx := c.DB.Begin()
+
+	order, err := lockOrder(tx, uint(newShipment.OrderID))
+	if err != nil {
+		tx.Rollback()
+		return model.ShipmentDetails{}, err
+	}
+	if order.OrderStatusID != domain.OrderPending {
+		tx.Rollback()
+		return model.ShipmentDetails{}, &domain.OrderStatusError{Reason: fmt.Sprintf("cannot ship an order that is %v", order.OrderStatusID.Name())}
+	}
+
+	var carrier domain.Carrier
+	findCarrierQuery := `SELECT * FROM carriers WHERE id = $1;`
+	if err := tx.Raw(findCarrierQuery, newShipment.CarrierID).Scan(&carrier).Error; err != nil {
+		tx.Rollback()
+		return model.ShipmentDetails{}, err
+	}
+	if carrier.ID == 0 {
+		tx.Rollback()
+		return model.ShipmentDetails{}, fmt.Errorf("no carrier found")
+	}
+
+	var unshipped []model.ShipmentItem
+	unshippedQuery := `	SELECT ol.id AS order_line_id, ol.quantity - COALESCE(SUM(sl.quantity), 0) AS quantity
+						FROM order_lines ol
+						LEFT JOIN shipment_lines sl ON sl.order_line_id = ol.id
+						WHERE ol.order_id = $1
+						GROUP BY ol.id, ol.quantity
+						ORDER BY ol.id;`
+	if err := tx.Raw(unshippedQuery, order.ID).Scan(&unshipped).Error; err != nil {
+		tx.Rollback()
+		return model.ShipmentDetails{}, err
+	}
+	remaining := make(map[int]int, len(unshipped))
+	for _, line := range unshipped {
+		remaining[line.OrderLineID] = line.Quantity
+	}
+
+	lines := newShipment.Lines
+	if len(lines) == 0 {
+		for _, line := range unshipped {
+			if line.Quantity > 0 {
+				lines = append(lines, line)
+			}
+		}
+		if len(lines) == 0 {
+			tx.Rollback()
+			return model.ShipmentDetails{}, fmt.Errorf("all order lines are already shipped")
+		}
+	}
+	for _, line := range lines {
+		quantity, ok := remaining[line.OrderLineID]
+		if !ok {
+			tx.Rollback()
+			return model.ShipmentDetails{}, fmt.Errorf("order line %v is not part of the order", line.OrderLineID)
+		}
+		if line.Quantity > quantity {
+			tx.Rollback()
+			return model.ShipmentDetails{}, fmt.Errorf("quantity left to ship for order line %v is %v", line.OrderLineID, quantity)
+		}
+	}
+
+	var shipment model.ShipmentDetails
+	createShipmentQuery := `	INSERT INTO shipments (order_id, carrier_id, tracking_number, status, created_at, updated_at)
+							VALUES ($1, $2, $3, $4, NOW(), NOW()) RETURNING *;`
+	err = tx.Raw(createShipmentQuery, order.ID, carrier.ID, newShipment.TrackingNumber, domain.ShipmentDispatched).Scan(&shipment.Shipment).Error
+	if err != nil {
+		tx.Rollback()
+		return model.ShipmentDetails{}, err
+	}
+	shipment.CarrierName = carrier.Name
+
+	createLineQuery := `INSERT INTO shipment_lines (shipment_id, order_line_id, quantity) VALUES ($1, $2, $3) RETURNING *;`
+	for _, line := range lines {
+		var shipmentLine domain.ShipmentLine
+		if err := tx.Raw(createLineQuery, shipment.ID, line.OrderLineID, line.Quantity).Scan(&shipmentLine).Error; err != nil {
+			tx.Rollback()
+			return model.ShipmentDetails{}, err
+		}
+		shipment.Lines = append(shipment.Lines, shipmentLine)
+	}
+
+	dispatched, err := recordShipmentEvent(tx, model.ShipmentEvent{ShipmentID: int(shipment.ID), Status: string(domain.ShipmentDispatched)})
+	if err != nil {
+		tx.Rollback()
+		return model.ShipmentDetails{}, err
+	}
+	shipment.Events = []domain.ShipmentEvent{dispatched}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return model.ShipmentDetails{}, err
+	}
+	return shipment, nil
+}
+
+// AddShipmentEvent posts a tracking event to a shipment. Once the last shipment of a fully shipped order is delivered,
+// the order is marked as delivered. The order is locked before the shipment, in the same order CreateShipment locks them,
+// so that two shipments of an order delivered at the same time cannot both miss that the order is delivered.
+func (c *shipmentDatabase) AddShipmentEvent(ctx context.Context, event model.ShipmentEvent) (domain.ShipmentEvent, error) {
+	tx := c.DB.Begin()
+
+	var orderID uint
+	findOrderQuery := `SELECT order_id FROM shipments WHERE id = $1;`
+	if err := tx.Raw(findOrderQuery, event.ShipmentID).Scan(&orderID).Error; err != nil {
+		tx.Rollback()
+		return domain.ShipmentEvent{}, err
+	}
+	if orderID == 0 {
+		tx.Rollback()
+		return domain.ShipmentEvent{}, fmt.Errorf("no shipment found")
+	}
+	if _, err := lockOrder(tx, orderID); err != nil {
+		tx.Rollback()
+		return domain.ShipmentEvent{}, err
+	}
+
+	var shipment domain.Shipment
+	lockShipmentQuery := `SELECT * FROM shipments WHERE id = $1 FOR UPDATE;`
+	if err := tx.Raw(lockShipmentQuery, event.ShipmentID).Scan(&shipment).Error; err != nil {
+		tx.Rollback()
+		return domain.ShipmentEvent{}, err
+	}
+	if err := domain.CheckShipmentTransition(shipment.Status, domain.ShipmentStatus(event.Status)); err != nil {
+		tx.Rollback()
+		return domain.ShipmentEvent{}, err
+	}
+
+	recordedEvent, err := recordShipmentEvent(tx, event)
+	if err != nil {
+		tx.Rollback()
+		return domain.ShipmentEvent{}, err
+	}
+	updateShipmentQuery := `UPDATE shipments SET status = $1, updated_at = NOW() WHERE id = $2;`
+	if err := tx.Exec(updateShipmentQuery, event.Status, shipment.ID).Error; err != nil {
+		tx.Rollback()
+		return domain.ShipmentEvent{}, err
+	}
+
+	if recordedEvent.Status == domain.ShipmentDelivered {
+		if err := deliverShippedOrder(tx, shipment.OrderID, recordedEvent.OccurredAt); err != nil {
+			tx.Rollback()
+			return domain.ShipmentEvent{}, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return domain.ShipmentEvent{}, err
+	}
+	return recordedEvent, nil
+}
+
+// ViewOrderShipments returns the shipments of an order with their lines and tracking events, oldest first
+func (c *shipmentDatabase) ViewOrderShipments(ctx context.Context, orderID int) ([]model.ShipmentDetails, error) {
+	var shipments []model.ShipmentDetails
+	fetchShipmentsQuery := `	SELECT s.*, c.name AS carrier_name, REPLACE(c.tracking_url, '{tracking_number}', s.tracking_number) AS tracking_url
+							FROM shipments s
+							JOIN carriers c ON c.id = s.carrier_id
+							WHERE s.order_id = $1
+							ORDER BY s.created_at, s.id;`
+	if err := c.DB.Raw(fetchShipmentsQuery, orderID).Scan(&shipments).Error; err != nil {
+		return nil, err
+	}
+	if len(shipments) == 0 {
+		return shipments, nil
+	}
+
+	var lines []domain.ShipmentLine
+	fetchLinesQuery := `	SELECT sl.* FROM shipment_lines sl
+						JOIN shipments s ON s.id = sl.shipment_id
+						WHERE s.order_id = $1
+						ORDER BY sl.id;`
+	if err := c.DB.Raw(fetchLinesQuery, orderID).Scan(&lines).Error; err != nil {
+		return nil, err
+	}
+	var events []domain.ShipmentEvent
+	fetchEventsQuery := `	SELECT se.* FROM shipment_events se
+						JOIN shipments s ON s.id = se.shipment_id
+						WHERE s.order_id = $1
+						ORDER BY se.occurred_at, se.id;`
+	if err := c.DB.Raw(fetchEventsQuery, orderID).Scan(&events).Error; err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]*model.ShipmentDetails, len(shipments))
+	for i := range shipments {
+		byID[shipments[i].ID] = &shipments[i]
+	}
+	for _, line := range lines {
+		byID[line.ShipmentID].Lines = append(byID[line.ShipmentID].Lines, line)
+	}
+	for _, event := range events {
+		byID[event.ShipmentID].Events = append(byID[event.ShipmentID].Events, event)
+	}
+	return shipments, nil
+}
+
+// lockOrder locks the order row until the end of the transaction
+func lockOrder(tx *gorm.DB, orderID uint) (domain.Order, error) {
+	var order domain.Order
+	lockOrderQuery := `SELECT * FROM orders WHERE id = $1 FOR UPDATE;`
+	if err := tx.Raw(lockOrderQuery, orderID).Scan(&order).Error; err != nil {
+		return domain.Order{}, err
+	}
+	if order.ID == 0 {
+		return domain.Order{}, fmt.Errorf("no order found")
+	}
+	return order, nil
+}
+
+// recordShipmentEvent adds an event to the tracking timeline of a shipment. Events without a time are taken as happening now.
+func recordShipmentEvent(tx *gorm.DB, event model.ShipmentEvent) (domain.ShipmentEvent, error) {
+	var recordedEvent domain.ShipmentEvent
+	recordEventQuery := `	INSERT INTO shipment_events (shipment_id, status, location, note, occurred_at, created_at)
+							VALUES ($1, $2, $3, $4, COALESCE($5, NOW()), NOW()) RETURNING *;`
+	var occurredAt interface{}
+	if !event.OccurredAt.IsZero() {
+		occurredAt = event.OccurredAt
+	}
+	err := tx.Raw(recordEventQuery, event.ShipmentID, event.Status, event.Location, event.Note, occurredAt).Scan(&recordedEvent).Error
+	return recordedEvent, err
+}
+
+// deliverShippedOrder marks a pending order as delivered when every unit of it is shipped and every shipment is delivered
+func deliverShippedOrder(tx *gorm.DB, orderID uint, deliveredAt time.Time) error {
+	var delivered bool
+	deliveredQuery := `	SELECT NOT EXISTS (SELECT 1 FROM shipments WHERE order_id = $1 AND status != $2)
+							AND NOT EXISTS (
+								SELECT 1 FROM order_lines ol
+								WHERE ol.order_id = $1
+									AND ol.quantity > (SELECT COALESCE(SUM(sl.quantity), 0) FROM shipment_lines sl WHERE sl.order_line_id = ol.id)
+							);`
+	if err := tx.Raw(deliveredQuery, orderID, domain.ShipmentDelivered).Scan(&delivered).Error; err != nil {
+		return err
+	}
+	if !delivered {
+		return nil
+	}
+
+	var deliveredOrder domain.Order
+	updateOrderQuery := `	UPDATE orders SET delivery_status_id = $1, delivery_updated_at = $2
+							WHERE id = $3 AND order_status_id = $4 AND delivery_status_id = $5 RETURNING *;`
+	err := tx.Raw(updateOrderQuery, domain.DeliveryDelivered, deliveredAt, orderID, domain.OrderPending, domain.DeliveryPending).Scan(&deliveredOrder).Error
+	if err != nil {
+		return err
+	}
+	if deliveredOrder.ID == 0 {
+		return nil
+	}
+	return recordOrderStatus(tx, deliveredOrder, "admin")
+}
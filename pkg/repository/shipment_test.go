@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func TestCreateShipment(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          model.CreateShipment
+		expectedOutput model.ShipmentDetails
+		buildStub      func(mock sqlmock.Sqlmock)
+		expectedErr    error
+	}{
+		{ //test case for shipping everything of the order that is not shipped yet
+			name:  "remaining lines",
+			input: model.CreateShipment{OrderID: 4, CarrierID: 1, TrackingNumber: "AWB123"},
+			expectedOutput: model.ShipmentDetails{
+				Shipment:    domain.Shipment{ID: 6, OrderID: 4, CarrierID: 1, TrackingNumber: "AWB123", Status: domain.ShipmentDispatched},
+				CarrierName: "Delhivery",
+				Lines:       []domain.ShipmentLine{{ID: 8, ShipmentID: 6, OrderLineID: 11, Quantity: 1}},
+				Events:      []domain.ShipmentEvent{{ID: 9, ShipmentID: 6, Status: domain.ShipmentDispatched}},
+			},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^SELECT \\* FROM orders (.+) FOR UPDATE;$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "order_status_id"}).AddRow(4, domain.OrderPending))
+				mock.ExpectQuery("^SELECT \\* FROM carriers (.+)$").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Delhivery"))
+				mock.ExpectQuery("^SELECT ol.id AS order_line_id, (.+)$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"order_line_id", "quantity"}).AddRow(10, 0).AddRow(11, 1))
+				mock.ExpectQuery("^INSERT INTO shipments (.+)$").
+					WithArgs(4, 1, "AWB123", domain.ShipmentDispatched).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "carrier_id", "tracking_number", "status"}).AddRow(6, 4, 1, "AWB123", "dispatched"))
+				mock.ExpectQuery("^INSERT INTO shipment_lines (.+)$").
+					WithArgs(6, 11, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "shipment_id", "order_line_id", "quantity"}).AddRow(8, 6, 11, 1))
+				mock.ExpectQuery("^INSERT INTO shipment_events (.+)$").
+					WithArgs(6, "dispatched", "", "", nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "shipment_id", "status"}).AddRow(9, 6, "dispatched"))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{ //test case for shipping more units of an order line than are left to ship
+			name:           "more than ordered",
+			input:          model.CreateShipment{OrderID: 4, CarrierID: 1, TrackingNumber: "AWB124", Lines: []model.ShipmentItem{{OrderLineID: 10, Quantity: 2}}},
+			expectedOutput: model.ShipmentDetails{},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^SELECT \\* FROM orders (.+) FOR UPDATE;$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "order_status_id"}).AddRow(4, domain.OrderPending))
+				mock.ExpectQuery("^SELECT \\* FROM carriers (.+)$").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Delhivery"))
+				mock.ExpectQuery("^SELECT ol.id AS order_line_id, (.+)$").
+					WithArgs(4).
+					WillReturnRows(sqlmock.NewRows([]string{"order_line_id", "quantity"}).AddRow(10, 1))
+				mock.ExpectRollback()
+			},
+			expectedErr: errors.New("quantity left to ship for order line 10 is 1"),
+		},
+		{ //test case for shipping an order that was cancelled
+			name:           "cancelled order",
+			input:          model.CreateShipment{OrderID: 5, CarrierID: 1, TrackingNumber: "AWB125"},
+			expectedOutput: model.ShipmentDetails{},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^SELECT \\* FROM orders (.+) FOR UPDATE;$").
+					WithArgs(5).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "order_status_id"}).AddRow(5, domain.OrderCancelledByUser))
+				mock.ExpectRollback()
+			},
+			expectedErr: &domain.OrderStatusError{Reason: "cannot ship an order that is cancelled by user"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when initializing a mock db session", err)
+			}
+
+			shipmentRepository := NewShipmentRepository(gormDB)
+			tt.buildStub(mock)
+
+			actualOutput, actualErr := shipmentRepository.CreateShipment(context.TODO(), tt.input)
+			assert.Equal(t, tt.expectedErr, actualErr)
+			assert.Equal(t, tt.expectedOutput, actualOutput)
+
+			err = mock.ExpectationsWereMet()
+			if err != nil {
+				t.Errorf("Unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
@@ -7,7 +7,6 @@ import (
 	interfaces "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/interface"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
 	"gorm.io/gorm"
-	"strings"
 )
 
 type userDatabase struct {
@@ -62,70 +61,172 @@ func (c *userDatabase) FindByPhone(ctx context.Context, phone string) (model.Use
 }
 
 func (c *userDatabase) AddAddress(ctx context.Context, userID int, newAddress model.AddressInput) (domain.Address, error) {
-	var existingAddress, addedAddress domain.Address
-	findAddressQuery := `SELECT * FROM addresses WHERE user_id = $1`
-	err := c.DB.Raw(findAddressQuery, userID).Scan(&existingAddress).Error
+	tx := c.DB.Begin()
+
+	//the first address saved by a user becomes the default address
+	var addressCount int
+	countQuery := `SELECT COUNT(*) FROM addresses WHERE user_id = $1 AND is_deleted = false`
+	err := tx.Raw(countQuery, userID).Scan(&addressCount).Error
 	if err != nil {
+		tx.Rollback()
 		return domain.Address{}, err
 	}
-	if existingAddress.ID == 0 || existingAddress.UserID == 0 {
-		//no address is found in user table, so insert query
-		insertAddressQuery := `	INSERT INTO addresses(
-								user_id, house_number, street, city, district, pincode, landmark) 
-								VALUES($1,$2,$3,$4,$5,$6, $7) RETURNING *`
-		err := c.DB.Raw(insertAddressQuery, userID, newAddress.HouseNumber, newAddress.Street, newAddress.City, newAddress.District, newAddress.Pincode, newAddress.Landmark).Scan(&addedAddress).Error
-		return addedAddress, err
-	} else {
-		//	address is already there, update it
-		updateAddressQuery := `	UPDATE addresses SET
-								house_number = $1, street = $2, city = $3, district = $4, pincode = $5, landmark = $6
-								WHERE user_id = $7
-								RETURNING *`
-		err := c.DB.Raw(updateAddressQuery, newAddress.HouseNumber, newAddress.Street, newAddress.City, newAddress.District, newAddress.Pincode, newAddress.Landmark, userID).Scan(&addedAddress).Error
-		return addedAddress, err
-	}
-}
-
-func (c *userDatabase) UpdateAddress(ctx context.Context, userID int, address model.AddressInput) (domain.Address, error) {
-	var updatedAddress domain.Address
-	updateQuery := `UPDATE addresses SET house_number = $1, street = $2, city = $3, district = $4, pincode = $5, landmark = $6 WHERE user_id = $7 RETURNING *`
-	err := c.DB.Raw(updateQuery, address.HouseNumber, address.Street, address.City, address.District, address.Pincode, address.Landmark, userID).Scan(&updatedAddress).Error
+	isDefault := newAddress.IsDefault || addressCount == 0
+
+	//only one address can be the default address of a user
+	if isDefault {
+		clearDefaultQuery := `UPDATE addresses SET is_default = false WHERE user_id = $1`
+		if err := tx.Exec(clearDefaultQuery, userID).Error; err != nil {
+			tx.Rollback()
+			return domain.Address{}, err
+		}
+	}
 
+	var addedAddress domain.Address
+	insertAddressQuery := `	INSERT INTO addresses(
+							user_id, house_number, street, city, district, pincode, landmark, label, is_default, is_deleted) 
+							VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, false) RETURNING *`
+	err = tx.Raw(insertAddressQuery, userID, newAddress.HouseNumber, newAddress.Street, newAddress.City, newAddress.District, newAddress.Pincode, newAddress.Landmark, newAddress.Label, isDefault).Scan(&addedAddress).Error
+	if err != nil {
+		tx.Rollback()
+		return domain.Address{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return domain.Address{}, err
+	}
+	return addedAddress, nil
+}
+
+func (c *userDatabase) UpdateAddress(ctx context.Context, userID, addressID int, address model.AddressInput) (domain.Address, error) {
+	tx := c.DB.Begin()
+
+	if address.IsDefault {
+		clearDefaultQuery := `UPDATE addresses SET is_default = false WHERE user_id = $1 AND id != $2`
+		if err := tx.Exec(clearDefaultQuery, userID, addressID).Error; err != nil {
+			tx.Rollback()
+			return domain.Address{}, err
+		}
+	}
+
+	//an address cannot be un-set as default through update, it can only be replaced by marking another address as default
+	var updatedAddress domain.Address
+	updateQuery := `UPDATE addresses SET house_number = $1, street = $2, city = $3, district = $4, pincode = $5, landmark = $6, label = $7, is_default = (is_default OR $8)
+					WHERE id = $9 AND user_id = $10 AND is_deleted = false RETURNING *`
+	err := tx.Raw(updateQuery, address.HouseNumber, address.Street, address.City, address.District, address.Pincode, address.Landmark, address.Label, address.IsDefault, addressID, userID).Scan(&updatedAddress).Error
+	if err != nil {
+		tx.Rollback()
+		return domain.Address{}, err
+	}
 	if updatedAddress.ID == 0 {
+		tx.Rollback()
 		return domain.Address{}, fmt.Errorf("failed to update address")
 	}
-	return updatedAddress, err
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return domain.Address{}, err
+	}
+	return updatedAddress, nil
+}
+
+func (c *userDatabase) ListAddresses(ctx context.Context, userID int) ([]domain.Address, error) {
+	var addresses []domain.Address
+	fetchAddressesQuery := `SELECT * FROM addresses WHERE user_id = $1 AND is_deleted = false ORDER BY is_default DESC, id ASC`
+	err := c.DB.Raw(fetchAddressesQuery, userID).Scan(&addresses).Error
+	return addresses, err
 }
 
-func (c *userDatabase) ViewAddress(ctx context.Context, userID int) (domain.Address, error) {
+func (c *userDatabase) FindAddressByID(ctx context.Context, userID, addressID int) (domain.Address, error) {
 	var address domain.Address
-	fetchAddressQuery := `SELECT * FROM addresses WHERE user_id = $1`
+	fetchAddressQuery := `SELECT * FROM addresses WHERE id = $1 AND user_id = $2 AND is_deleted = false`
+	err := c.DB.Raw(fetchAddressQuery, addressID, userID).Scan(&address).Error
+	if err != nil {
+		return domain.Address{}, err
+	}
+	if address.ID == 0 {
+		return domain.Address{}, fmt.Errorf("no address found")
+	}
+	return address, nil
+}
+
+func (c *userDatabase) FindDefaultAddress(ctx context.Context, userID int) (domain.Address, error) {
+	var address domain.Address
+	fetchAddressQuery := `SELECT * FROM addresses WHERE user_id = $1 AND is_default = true AND is_deleted = false`
 	err := c.DB.Raw(fetchAddressQuery, userID).Scan(&address).Error
 	return address, err
 }
 
-func (c *userDatabase) ListAllUsers(ctx context.Context, queryParams model.QueryParams) ([]domain.Users, error) {
+func (c *userDatabase) SetDefaultAddress(ctx context.Context, userID, addressID int) (domain.Address, error) {
+	tx := c.DB.Begin()
 
-	findQuery := "SELECT * FROM users"
-	if queryParams.Query != "" && queryParams.Filter != "" {
-		findQuery = fmt.Sprintf("%s WHERE LOWER(%s) LIKE '%%%s%%'", findQuery, queryParams.Filter, strings.ToLower(queryParams.Query))
+	clearDefaultQuery := `UPDATE addresses SET is_default = false WHERE user_id = $1`
+	if err := tx.Exec(clearDefaultQuery, userID).Error; err != nil {
+		tx.Rollback()
+		return domain.Address{}, err
 	}
-	if queryParams.SortBy != "" {
-		if queryParams.SortDesc {
-			findQuery = fmt.Sprintf("%s ORDER BY %s DESC", findQuery, queryParams.SortBy)
-		} else {
-			findQuery = fmt.Sprintf("%s ORDER BY %s ASC", findQuery, queryParams.SortBy)
-		}
+
+	var defaultAddress domain.Address
+	setDefaultQuery := `UPDATE addresses SET is_default = true WHERE id = $1 AND user_id = $2 AND is_deleted = false RETURNING *`
+	err := tx.Raw(setDefaultQuery, addressID, userID).Scan(&defaultAddress).Error
+	if err != nil {
+		tx.Rollback()
+		return domain.Address{}, err
+	}
+	if defaultAddress.ID == 0 {
+		tx.Rollback()
+		return domain.Address{}, fmt.Errorf("no address found")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return domain.Address{}, err
 	}
-	if queryParams.Limit != 0 && queryParams.Page != 0 {
-		findQuery = fmt.Sprintf("%s LIMIT %d OFFSET %d", findQuery, queryParams.Limit, (queryParams.Page-1)*queryParams.Limit)
+	return defaultAddress, nil
+}
+
+// DeleteAddress soft deletes an address, so that orders shipped to it keep a valid reference.
+// If the default address is deleted, the oldest remaining address becomes the default.
+func (c *userDatabase) DeleteAddress(ctx context.Context, userID, addressID int) error {
+	tx := c.DB.Begin()
+
+	var deletedAddress domain.Address
+	deleteQuery := `UPDATE addresses SET is_deleted = true, is_default = false WHERE id = $1 AND user_id = $2 AND is_deleted = false RETURNING *`
+	err := tx.Raw(deleteQuery, addressID, userID).Scan(&deletedAddress).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if deletedAddress.ID == 0 {
+		tx.Rollback()
+		return fmt.Errorf("no address found")
+	}
+
+	promoteDefaultQuery := `UPDATE addresses SET is_default = true
+							WHERE id = (SELECT id FROM addresses WHERE user_id = $1 AND is_deleted = false ORDER BY id LIMIT 1)
+							AND NOT EXISTS (SELECT 1 FROM addresses WHERE user_id = $1 AND is_default = true AND is_deleted = false)`
+	if err := tx.Exec(promoteDefaultQuery, userID).Error; err != nil {
+		tx.Rollback()
+		return err
 	}
-	if queryParams.Limit == 0 || queryParams.Page == 0 {
-		findQuery = fmt.Sprintf("%s LIMIT 10 OFFSET 0", findQuery)
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+func (c *userDatabase) ListAllUsers(ctx context.Context, queryParams model.QueryParams) ([]domain.Users, error) {
+
+	findQuery, args, err := buildListingQuery("SELECT * FROM users", userColumns, queryParams)
+	if err != nil {
+		return nil, err
 	}
 	var users []domain.Users
 
-	err := c.DB.Raw(findQuery).Scan(&users).Error
+	err = c.DB.Raw(findQuery, args...).Scan(&users).Error
 	if len(users) == 0 {
 		return users, fmt.Errorf("no users found")
 	}
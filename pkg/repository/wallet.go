@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	interfaces "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/interface"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"gorm.io/gorm"
+	"math"
+)
+
+type walletDatabase struct {
+	DB *gorm.DB
+}
+
+func NewWalletRepository(DB *gorm.DB) interfaces.WalletRepository {
+	return &walletDatabase{DB}
+}
+
+func (c *walletDatabase) ViewWallet(ctx context.Context, userID int) (model.Wallet, error) {
+	var wallet model.Wallet
+	balanceQuery := `SELECT COALESCE(SUM(amount), 0) FROM wallet_entries WHERE user_id = $1;`
+	if err := c.DB.Raw(balanceQuery, userID).Scan(&wallet.Balance).Error; err != nil {
+		return model.Wallet{}, err
+	}
+	wallet.Balance = roundToPaise(wallet.Balance)
+
+	fetchEntriesQuery := `SELECT * FROM wallet_entries WHERE user_id = $1 ORDER BY created_at DESC, id DESC;`
+	err := c.DB.Raw(fetchEntriesQuery, userID).Scan(&wallet.Entries).Error
+	return wallet, err
+}
+
+// AdjustWallet posts a credit or debit by an admin to the wallet of a user
+func (c *walletDatabase) AdjustWallet(ctx context.Context, adjustment model.WalletAdjustment, adminID int) (domain.WalletEntry, error) {
+	tx := c.DB.Begin()
+
+	entry, err := postWalletEntry(tx, domain.WalletEntry{
+		UserID:  uint(adjustment.UserID),
+		Amount:  adjustment.Amount,
+		Reason:  domain.WalletEntryReason(adjustment.Reason),
+		AdminID: uint(adminID),
+		Note:    adjustment.Note,
+	})
+	if err != nil {
+		tx.Rollback()
+		return domain.WalletEntry{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return domain.WalletEntry{}, err
+	}
+	return entry, nil
+}
+
+// postWalletEntry writes an entry to the wallet ledger of a user. The wallet is locked first, so that the entries of
+// a wallet are written one at a time and a debit cannot take the balance below zero.
+func postWalletEntry(tx *gorm.DB, entry domain.WalletEntry) (domain.WalletEntry, error) {
+	balance, err := lockWallet(tx, entry.UserID)
+	if err != nil {
+		return domain.WalletEntry{}, err
+	}
+	balanceAfter := roundToPaise(balance + entry.Amount)
+	if balanceAfter < 0 {
+		return domain.WalletEntry{}, fmt.Errorf("wallet balance %v is not enough", balance)
+	}
+
+	var postedEntry domain.WalletEntry
+	insertEntryQuery := `	INSERT INTO wallet_entries (user_id, amount, balance_after, reason, order_id, refund_id, admin_id, note, created_at)
+							VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW()) RETURNING *;`
+	err = tx.Raw(insertEntryQuery, entry.UserID, entry.Amount, balanceAfter, entry.Reason, entry.OrderID, entry.RefundID, entry.AdminID, entry.Note).Scan(&postedEntry).Error
+	return postedEntry, err
+}
+
+// lockWallet returns the balance of the wallet of a user and locks the wallet until the transaction ends. Wallets do
+// not have a row of their own, the row of the user is locked instead.
+func lockWallet(tx *gorm.DB, userID uint) (float64, error) {
+	var lockedUserID uint
+	lockUserQuery := `SELECT id FROM users WHERE id = $1 FOR UPDATE;`
+	if err := tx.Raw(lockUserQuery, userID).Scan(&lockedUserID).Error; err != nil {
+		return 0, err
+	}
+	if lockedUserID == 0 {
+		return 0, fmt.Errorf("no user found")
+	}
+
+	var balance float64
+	balanceQuery := `SELECT COALESCE(SUM(amount), 0) FROM wallet_entries WHERE user_id = $1;`
+	if err := tx.Raw(balanceQuery, userID).Scan(&balance).Error; err != nil {
+		return 0, err
+	}
+	return roundToPaise(balance), nil
+}
+
+// walletPayment works out how much of a new order is paid from the wallet and the payment method of the order. The
+// wallet pays as much of the order as it can, an order the wallet cannot pay in full is paid for the rest online.
+// The wallet stays locked until the order is placed. An order that costs nothing, like one discounted in full, is paid
+// without touching the wallet.
+func walletPayment(tx *gorm.DB, userID uint, orderTotal float64, paymentMethodID domain.PaymentMethodID, useWallet bool) (domain.PaymentMethodID, float64, error) {
+	if paymentMethodID != domain.PaymentWallet && !useWallet {
+		return paymentMethodID, 0, nil
+	}
+	if orderTotal <= 0 {
+		return domain.PaymentWallet, 0, nil
+	}
+	balance, err := lockWallet(tx, userID)
+	if err != nil {
+		return 0, 0, err
+	}
+	if balance <= 0 {
+		return 0, 0, fmt.Errorf("wallet is empty")
+	}
+	if balance >= orderTotal {
+		return domain.PaymentWallet, orderTotal, nil
+	}
+	if paymentMethodID != domain.PaymentOnline {
+		return 0, 0, fmt.Errorf("wallet balance %v is not enough, the rest of the order can only be paid online", balance)
+	}
+	return domain.PaymentOnline, balance, nil
+}
+
+// createPayment records the payment of a new order. The part paid from the wallet is debited here, an order paid
+// entirely from the wallet is paid once it is placed.
+func createPayment(tx *gorm.DB, order domain.Order, walletAmount float64) error {
+	paymentStatusID := domain.PaymentPending
+	if order.PaymentMethodID == domain.PaymentWallet {
+		paymentStatusID = domain.PaymentCompleted
+	}
+	createPaymentEntry := `	INSERT INTO payment_details (order_id, order_total, wallet_amount, payment_method_id, payment_status_id, updated_at)
+							VALUES ($1, $2, $3, $4, $5, NOW());`
+	err := tx.Exec(createPaymentEntry, order.ID, order.OrderTotal, walletAmount, order.PaymentMethodID, paymentStatusID).Error
+	if err != nil {
+		return err
+	}
+	if walletAmount == 0 {
+		return nil
+	}
+	_, err = postWalletEntry(tx, domain.WalletEntry{
+		UserID:  order.UserID,
+		Amount:  -walletAmount,
+		Reason:  domain.WalletPurchase,
+		OrderID: order.ID,
+	})
+	return err
+}
+
+func roundToPaise(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
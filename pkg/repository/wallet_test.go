@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func TestWalletPayment(t *testing.T) {
+	tests := []struct {
+		name                  string
+		orderTotal            float64
+		paymentMethodID       domain.PaymentMethodID
+		buildStub             func(mock sqlmock.Sqlmock)
+		expectedPaymentMethod domain.PaymentMethodID
+		expectedWalletAmount  float64
+		expectedErr           error
+	}{
+		{ //test case for an order discounted in full, it is paid even though the wallet is empty
+			name:                  "nothing to pay",
+			orderTotal:            0,
+			paymentMethodID:       domain.PaymentWallet,
+			buildStub:             func(mock sqlmock.Sqlmock) {},
+			expectedPaymentMethod: domain.PaymentWallet,
+			expectedWalletAmount:  0,
+			expectedErr:           nil,
+		},
+		{ //test case for an order the wallet can pay in full
+			name:            "paid from wallet",
+			orderTotal:      1200.5,
+			paymentMethodID: domain.PaymentWallet,
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT id FROM users WHERE id = \\$1 FOR UPDATE;$").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectQuery("^SELECT COALESCE\\(SUM\\(amount\\), 0\\) FROM wallet_entries (.+)$").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(5000))
+			},
+			expectedPaymentMethod: domain.PaymentWallet,
+			expectedWalletAmount:  1200.5,
+			expectedErr:           nil,
+		},
+		{ //test case for an order to be paid from an empty wallet
+			name:            "empty wallet",
+			orderTotal:      1200.5,
+			paymentMethodID: domain.PaymentWallet,
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT id FROM users WHERE id = \\$1 FOR UPDATE;$").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectQuery("^SELECT COALESCE\\(SUM\\(amount\\), 0\\) FROM wallet_entries (.+)$").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(0))
+			},
+			expectedErr: errors.New("wallet is empty"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when initializing a mock db session", err)
+			}
+			tt.buildStub(mock)
+
+			actualPaymentMethod, actualWalletAmount, actualErr := walletPayment(gormDB, 1, tt.orderTotal, tt.paymentMethodID, false)
+			assert.Equal(t, tt.expectedErr, actualErr)
+			assert.Equal(t, tt.expectedPaymentMethod, actualPaymentMethod)
+			assert.Equal(t, tt.expectedWalletAmount, actualWalletAmount)
+
+			err = mock.ExpectationsWereMet()
+			if err != nil {
+				t.Errorf("Unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
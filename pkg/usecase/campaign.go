@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	interfaces "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/interface"
+	services "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/usecase/interface"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/couponcode"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"strings"
+)
+
+const (
+	maxCampaignCodes = 10000
+	// a campaign needs this many times more possible codes than it generates, so that its codes are hard to guess
+	// and rarely collide with codes already taken
+	campaignCodeSpace = 1000
+)
+
+type campaignUseCase struct {
+	campaignRepo interfaces.CampaignRepository
+}
+
+func NewCampaignUseCase(campaignRepo interfaces.CampaignRepository) services.CampaignUseCase {
+	return &campaignUseCase{
+		campaignRepo: campaignRepo,
+	}
+}
+
+func (c *campaignUseCase) CreateCampaign(ctx context.Context, campaign model.CreateCouponCampaign) (domain.CouponCampaign, error) {
+	campaign.Name = strings.TrimSpace(campaign.Name)
+	if campaign.Name == "" {
+		return domain.CouponCampaign{}, fmt.Errorf("campaign name is required")
+	}
+	if campaign.CodeCount < 1 || campaign.CodeCount > maxCampaignCodes {
+		return domain.CouponCampaign{}, fmt.Errorf("code count should be between 1 and %v", maxCampaignCodes)
+	}
+
+	if campaign.Alphabet == "" {
+		campaign.Alphabet = couponcode.DefaultAlphabet
+	}
+	if campaign.CodeLength == 0 {
+		campaign.CodeLength = couponcode.DefaultLength
+	}
+	generator := couponcode.Generator{Prefix: campaign.Prefix, Alphabet: campaign.Alphabet, Length: campaign.CodeLength}
+	if err := generator.Validate(); err != nil {
+		return domain.CouponCampaign{}, err
+	}
+	if generator.Combinations() < float64(campaign.CodeCount)*campaignCodeSpace {
+		return domain.CouponCampaign{}, fmt.Errorf("too few possible codes for %v codes, use longer codes or a larger alphabet", campaign.CodeCount)
+	}
+
+	//each code is used once, by whoever it was given to
+	if len(campaign.Rules.UserIDs) > 0 {
+		return domain.CouponCampaign{}, fmt.Errorf("codes of a campaign cannot be limited to users")
+	}
+	singleUse := 1
+	campaign.Rules.Code = ""
+	campaign.Rules.UsageLimit = 1
+	campaign.Rules.PerUserLimit = &singleUse
+	if err := validateCoupon(&campaign.Rules); err != nil {
+		return domain.CouponCampaign{}, err
+	}
+
+	createdCampaign, err := c.campaignRepo.CreateCampaign(ctx, campaign)
+	return createdCampaign, err
+}
+
+func (c *campaignUseCase) ViewCampaigns(ctx context.Context) ([]model.CouponCampaignReport, error) {
+	campaigns, err := c.campaignRepo.ViewCampaigns(ctx)
+	return campaigns, err
+}
+
+func (c *campaignUseCase) ViewCampaignCodes(ctx context.Context, campaignID int) ([]model.CampaignCode, error) {
+	codes, err := c.campaignRepo.ViewCampaignCodes(ctx, campaignID)
+	return codes, err
+}
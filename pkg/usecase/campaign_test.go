@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/mockRepo"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/couponcode"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCreateCampaign(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	campaignRepo := mockRepo.NewMockCampaignRepository(ctrl)
+	campaignUseCase := NewCampaignUseCase(campaignRepo)
+
+	validFrom := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	validTill := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	rules := model.CreateCoupon{DiscountType: domain.CouponFlat, DiscountAmount: 500, ValidFrom: validFrom, ValidTill: validTill}
+	singleUse := 1
+
+	testData := []struct {
+		name           string
+		input          model.CreateCouponCampaign
+		buildStub      func(campaignRepo mockRepo.MockCampaignRepository)
+		expectedOutput domain.CouponCampaign
+		expectedError  error
+	}{
+		{ //test case for a campaign with the default alphabet and code length, each code is made single use
+			name:  "create campaign",
+			input: model.CreateCouponCampaign{Name: " influencer march ", Prefix: "INFLU-", CodeCount: 5000, Rules: rules},
+			buildStub: func(campaignRepo mockRepo.MockCampaignRepository) {
+				campaignRepo.EXPECT().CreateCampaign(gomock.Any(), model.CreateCouponCampaign{
+					Name:       "influencer march",
+					Prefix:     "INFLU-",
+					Alphabet:   couponcode.DefaultAlphabet,
+					CodeLength: couponcode.DefaultLength,
+					CodeCount:  5000,
+					Rules: model.CreateCoupon{DiscountType: domain.CouponFlat, DiscountAmount: 500, Scope: domain.CouponScopeCart,
+						ValidFrom: validFrom, ValidTill: validTill, UsageLimit: 1, PerUserLimit: &singleUse},
+				}).Times(1).
+					Return(domain.CouponCampaign{ID: 2, Name: "influencer march", Prefix: "INFLU-", Alphabet: couponcode.DefaultAlphabet,
+						CodeLength: couponcode.DefaultLength, CodeCount: 5000}, nil)
+			},
+			expectedOutput: domain.CouponCampaign{ID: 2, Name: "influencer march", Prefix: "INFLU-", Alphabet: couponcode.DefaultAlphabet,
+				CodeLength: couponcode.DefaultLength, CodeCount: 5000},
+			expectedError: nil,
+		},
+		{ //test case for codes that would be easy to guess, there are not many more possible codes than codes generated
+			name:           "too few possible codes",
+			input:          model.CreateCouponCampaign{Name: "email", Alphabet: "AB", CodeLength: 10, CodeCount: 100, Rules: rules},
+			buildStub:      func(campaignRepo mockRepo.MockCampaignRepository) {},
+			expectedOutput: domain.CouponCampaign{},
+			expectedError:  errors.New("too few possible codes for 100 codes, use longer codes or a larger alphabet"),
+		},
+		{
+			name:           "too many codes",
+			input:          model.CreateCouponCampaign{Name: "email", CodeCount: 20000, Rules: rules},
+			buildStub:      func(campaignRepo mockRepo.MockCampaignRepository) {},
+			expectedOutput: domain.CouponCampaign{},
+			expectedError:  errors.New("code count should be between 1 and 10000"),
+		},
+		{ //test case for codes limited to users, a code is for whoever it is given to
+			name: "limited to users",
+			input: model.CreateCouponCampaign{Name: "email", CodeCount: 10,
+				Rules: model.CreateCoupon{DiscountType: domain.CouponFlat, DiscountAmount: 500, UserIDs: []uint{3}, ValidFrom: validFrom, ValidTill: validTill}},
+			buildStub:      func(campaignRepo mockRepo.MockCampaignRepository) {},
+			expectedOutput: domain.CouponCampaign{},
+			expectedError:  errors.New("codes of a campaign cannot be limited to users"),
+		},
+		{ //test case for rules that are not valid for a coupon
+			name: "invalid rules",
+			input: model.CreateCouponCampaign{Name: "email", CodeCount: 10,
+				Rules: model.CreateCoupon{DiscountType: domain.CouponFlat, ValidFrom: validFrom, ValidTill: validTill}},
+			buildStub:      func(campaignRepo mockRepo.MockCampaignRepository) {},
+			expectedOutput: domain.CouponCampaign{},
+			expectedError:  errors.New("discount amount should be more than 0"),
+		},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStub(*campaignRepo)
+			actualCampaign, err := campaignUseCase.CreateCampaign(context.TODO(), tt.input)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, actualCampaign)
+		})
+	}
+}
@@ -8,7 +8,6 @@ import (
 	services "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/usecase/interface"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
 	"github.com/golang-jwt/jwt/v4"
-	"time"
 )
 
 type cartUseCase struct {
@@ -40,6 +39,14 @@ func (c *cartUseCase) ViewCart(ctx context.Context, userID int) (model.ViewCart,
 	return cart, err
 }
 
+func (c *cartUseCase) UpdateCartItem(ctx context.Context, userID, productItemID int, body model.UpdateCartItem) (model.ViewCart, error) {
+	if body.Quantity < 0 {
+		return model.ViewCart{}, fmt.Errorf("quantity cannot be negative")
+	}
+	cart, err := c.cartRepo.UpdateCartItem(ctx, userID, productItemID, body.Quantity)
+	return cart, err
+}
+
 func (c *cartUseCase) EmptyCart(ctx context.Context, userID int) error {
 	err := c.cartRepo.EmptyCart(ctx, userID)
 	return err
@@ -47,15 +54,6 @@ func (c *cartUseCase) EmptyCart(ctx context.Context, userID int) error {
 
 func (c *cartUseCase) AddCouponToCart(ctx context.Context, userID, couponID int) (model.ViewCart, error) {
 
-	//checking is coupon is already used
-	isUsed, err := c.productRepo.CouponUsed(ctx, userID, couponID)
-	if err != nil {
-		return model.ViewCart{}, err
-	}
-	if isUsed {
-		return model.ViewCart{}, fmt.Errorf("user already used the coupon")
-	}
-
 	//fetching coupon details
 	couponInfo, err := c.productRepo.ViewCouponByID(ctx, couponID)
 	if err != nil {
@@ -65,22 +63,36 @@ func (c *cartUseCase) AddCouponToCart(ctx context.Context, userID, couponID int)
 	if couponInfo.ID == 0 {
 		return model.ViewCart{}, fmt.Errorf("invalid coupon id")
 	}
-	//checking coupon validity
-	currentTime := time.Now()
-	if couponInfo.ValidTill.Before(currentTime) {
-		return model.ViewCart{}, fmt.Errorf("coupon expired")
-	}
+	//	add coupon to the cart, the cart is priced with the coupon to check that its rules allow it
+	cart, err := c.cartRepo.AddCouponToCart(ctx, userID, couponID)
+	return cart, err
+}
 
-	//	fetch cart total
-	cartInfo, err := c.cartRepo.ViewCart(ctx, userID)
+// ApplicableCoupons lists the coupons that apply to the cart of the user
+func (c *cartUseCase) ApplicableCoupons(ctx context.Context, userID int) (model.ApplicableCoupons, error) {
+	coupons, err := c.cartRepo.ApplicableCoupons(ctx, userID)
+	if err != nil {
+		return model.ApplicableCoupons{}, err
+	}
+	return model.ApplicableCoupons{Coupons: coupons}, nil
+}
 
-	if cartInfo.SubTotal < couponInfo.MinOrderValue {
-		return model.ViewCart{}, fmt.Errorf("cart total not enogh for applying the coupon")
+// ApplyBestCoupon adds the coupon with the largest discount to the cart of the user, along with listing the coupons
+// that apply to it
+func (c *cartUseCase) ApplyBestCoupon(ctx context.Context, userID int) (model.ApplicableCoupons, error) {
+	coupons, err := c.cartRepo.ApplicableCoupons(ctx, userID)
+	if err != nil {
+		return model.ApplicableCoupons{}, err
+	}
+	if len(coupons) == 0 {
+		return model.ApplicableCoupons{}, fmt.Errorf("no coupon applies to the cart")
 	}
 
-	//	add coupon to the cart
-	cart, err := c.cartRepo.AddCouponToCart(ctx, userID, couponID)
-	return cart, err
+	cart, err := c.cartRepo.AddCouponToCart(ctx, userID, int(coupons[0].CouponID))
+	if err != nil {
+		return model.ApplicableCoupons{}, err
+	}
+	return model.ApplicableCoupons{Coupons: coupons, Cart: &cart}, nil
 }
 
 func FindUserID(cookie string) (int, error) {
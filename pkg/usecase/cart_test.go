@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/mockRepo"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestApplicableCoupons(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cartRepo := mockRepo.NewMockCartRepository(ctrl)
+	cartUseCase := NewCartUseCase(cartRepo, nil)
+
+	//listing the coupons never changes the cart
+	cartRepo.EXPECT().ApplicableCoupons(gomock.Any(), 1).Times(1).
+		Return([]model.ApplicableCoupon{{CouponID: 3, Code: "SAVE10", Discount: 5000, CartTotal: 45000}}, nil)
+	cartRepo.EXPECT().AddCouponToCart(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	actualOutput, err := cartUseCase.ApplicableCoupons(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, model.ApplicableCoupons{Coupons: []model.ApplicableCoupon{{CouponID: 3, Code: "SAVE10", Discount: 5000, CartTotal: 45000}}}, actualOutput)
+}
+
+func TestApplyBestCoupon(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cartRepo := mockRepo.NewMockCartRepository(ctrl)
+	cartUseCase := NewCartUseCase(cartRepo, nil)
+
+	coupons := []model.ApplicableCoupon{
+		{CouponID: 3, Code: "SAVE10", Discount: 5000, CartTotal: 45000},
+		{CouponID: 2, Code: "FLAT1000", Discount: 1000, CartTotal: 49000},
+	}
+
+	testData := []struct {
+		name           string
+		buildStub      func(cartRepo mockRepo.MockCartRepository)
+		expectedOutput model.ApplicableCoupons
+		expectedError  error
+	}{
+		{
+			name: "largest discount applied",
+			buildStub: func(cartRepo mockRepo.MockCartRepository) {
+				cartRepo.EXPECT().ApplicableCoupons(gomock.Any(), 1).Times(1).Return(coupons, nil)
+				cartRepo.EXPECT().AddCouponToCart(gomock.Any(), 1, 3).Times(1).
+					Return(model.ViewCart{CouponID: 3, SubTotal: 50000, Discount: 5000, CartTotal: 45000, CanCheckout: true}, nil)
+			},
+			expectedOutput: model.ApplicableCoupons{
+				Coupons: coupons,
+				Cart:    &model.ViewCart{CouponID: 3, SubTotal: 50000, Discount: 5000, CartTotal: 45000, CanCheckout: true},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "no coupon applies",
+			buildStub: func(cartRepo mockRepo.MockCartRepository) {
+				cartRepo.EXPECT().ApplicableCoupons(gomock.Any(), 1).Times(1).Return(nil, nil)
+			},
+			expectedOutput: model.ApplicableCoupons{},
+			expectedError:  errors.New("no coupon applies to the cart"),
+		},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStub(*cartRepo)
+			actualOutput, err := cartUseCase.ApplyBestCoupon(context.TODO(), 1)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, actualOutput)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	interfaces "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/interface"
+	services "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/usecase/interface"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"regexp"
+	"strings"
+)
+
+// pincodes in India are six digits and never start with zero
+var pincodePattern = regexp.MustCompile(`^[1-9][0-9]{5}$`)
+
+type codUseCase struct {
+	codRepo interfaces.CODRepository
+}
+
+func NewCODUseCase(codRepo interfaces.CODRepository) services.CODUseCase {
+	return &codUseCase{
+		codRepo: codRepo,
+	}
+}
+
+func (c *codUseCase) ViewCODRules(ctx context.Context) (model.CODRules, error) {
+	rules, err := c.codRepo.ViewCODRules(ctx)
+	return rules, err
+}
+
+func (c *codUseCase) UpdateCODRules(ctx context.Context, rules model.UpdateCODRules) (domain.CODRules, error) {
+	if rules.MaxOrderValue < 0 || rules.MaxRefusedDeliveries < 0 {
+		return domain.CODRules{}, fmt.Errorf("limits cannot be negative")
+	}
+	updatedRules, err := c.codRepo.UpdateCODRules(ctx, rules)
+	return updatedRules, err
+}
+
+func (c *codUseCase) AddCODPincode(ctx context.Context, pincode string) (domain.CODPincode, error) {
+	pincode = strings.TrimSpace(pincode)
+	if !pincodePattern.MatchString(pincode) {
+		return domain.CODPincode{}, fmt.Errorf("invalid pincode")
+	}
+	addedPincode, err := c.codRepo.AddCODPincode(ctx, pincode)
+	return addedPincode, err
+}
+
+func (c *codUseCase) RemoveCODPincode(ctx context.Context, pincode string) error {
+	return c.codRepo.RemoveCODPincode(ctx, strings.TrimSpace(pincode))
+}
+
+// RecordCODCollection records what the courier reports after a cash on delivery attempt. The cash collected completes
+// the payment of the order, a refused delivery counts against the user for future cash on delivery orders.
+func (c *codUseCase) RecordCODCollection(ctx context.Context, collection model.CODCollection, adminID int) (domain.CODCollection, error) {
+	switch domain.CODCollectionStatus(collection.Status) {
+	case domain.CODCollected:
+		if collection.Amount <= 0 {
+			return domain.CODCollection{}, fmt.Errorf("collected amount should be positive")
+		}
+	case domain.CODRefused:
+		if collection.Amount != 0 {
+			return domain.CODCollection{}, fmt.Errorf("no amount is collected for a refused delivery")
+		}
+	default:
+		return domain.CODCollection{}, fmt.Errorf("invalid status, should be collected or refused")
+	}
+	collection.CollectedBy = strings.TrimSpace(collection.CollectedBy)
+	if collection.CollectedBy == "" {
+		return domain.CODCollection{}, fmt.Errorf("collected by is required")
+	}
+	recordedCollection, err := c.codRepo.RecordCODCollection(ctx, collection, adminID)
+	return recordedCollection, err
+}
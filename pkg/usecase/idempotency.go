@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/config"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	interfaces "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/interface"
+	services "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/usecase/interface"
+	"net/http"
+	"time"
+)
+
+const (
+	// defaultIdempotencyKeyTTL is how long keys are kept when IDEMPOTENCY_KEY_TTL is not set
+	defaultIdempotencyKeyTTL = 24 * time.Hour
+	// idempotencyKeyLease is how long a request can hold its key without finishing, after which the key is taken to be
+	// abandoned and a retry can claim it
+	idempotencyKeyLease = time.Minute
+)
+
+type idempotencyUseCase struct {
+	idempotencyRepo interfaces.IdempotencyRepository
+	ttl             time.Duration
+}
+
+func NewIdempotencyUseCase(idempotencyRepo interfaces.IdempotencyRepository, cfg config.Config) services.IdempotencyUseCase {
+	ttl := cfg.IdempotencyKeyTTL
+	if ttl <= 0 {
+		ttl = defaultIdempotencyKeyTTL
+	}
+	return &idempotencyUseCase{
+		idempotencyRepo: idempotencyRepo,
+		ttl:             ttl,
+	}
+}
+
+// BeginRequest claims an idempotency key of the user for a request, and reports whether the request should be run. A key
+// that was already used returns the stored key, whose response should be replayed instead. The request is everything that
+// identifies it, like the method, path and body, and a key sent again with a different request is rejected. A retry of a
+// request that held its key for longer than the lease claims the key again and is run.
+func (c *idempotencyUseCase) BeginRequest(ctx context.Context, userID int, key string, request []byte) (domain.IdempotencyKey, bool, error) {
+	if len(key) > 255 {
+		return domain.IdempotencyKey{}, false, fmt.Errorf("idempotency key cannot be longer than 255 characters")
+	}
+	hash := sha256.Sum256(request)
+	requestHash := hex.EncodeToString(hash[:])
+
+	idempotencyKey, claimed, err := c.idempotencyRepo.ClaimIdempotencyKey(ctx, userID, key, requestHash, time.Now().Add(c.ttl), time.Now().Add(-idempotencyKeyLease))
+	if err != nil {
+		return domain.IdempotencyKey{}, false, err
+	}
+	if claimed {
+		return idempotencyKey, true, nil
+	}
+	if idempotencyKey.RequestHash != requestHash {
+		return domain.IdempotencyKey{}, false, domain.ErrIdempotencyKeyReused
+	}
+	if !idempotencyKey.Completed {
+		return domain.IdempotencyKey{}, false, domain.ErrIdempotencyKeyInProgress
+	}
+	return idempotencyKey, false, nil
+}
+
+// CompleteRequest stores the response of a request run with an idempotency key, along with its content type. Only successful responses and client
+// errors are stored, for any other response the key is released instead so that the request can be retried.
+func (c *idempotencyUseCase) CompleteRequest(ctx context.Context, idempotencyKey domain.IdempotencyKey, statusCode int, contentType string, body []byte) error {
+	successful := statusCode >= http.StatusOK && statusCode < http.StatusMultipleChoices
+	clientError := statusCode >= http.StatusBadRequest && statusCode < http.StatusInternalServerError
+	if !successful && !clientError {
+		return c.idempotencyRepo.ReleaseIdempotencyKey(ctx, idempotencyKey.ID, idempotencyKey.CreatedAt)
+	}
+	return c.idempotencyRepo.SaveIdempotentResponse(ctx, idempotencyKey.ID, idempotencyKey.CreatedAt, statusCode, contentType, body)
+}
+
+// AbortRequest releases the idempotency key of a request that did not finish, so that the request can be retried
+func (c *idempotencyUseCase) AbortRequest(ctx context.Context, idempotencyKey domain.IdempotencyKey) error {
+	return c.idempotencyRepo.ReleaseIdempotencyKey(ctx, idempotencyKey.ID, idempotencyKey.CreatedAt)
+}
@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/config"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/mockRepo"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBeginRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	idempotencyRepo := mockRepo.NewMockIdempotencyRepository(ctrl)
+	idempotencyUseCase := NewIdempotencyUseCase(idempotencyRepo, config.Config{})
+
+	request := []byte("POST /orders\n{\"product_item_id\":3,\"quantity\":1}")
+	hash := sha256.Sum256(request)
+	requestHash := hex.EncodeToString(hash[:])
+
+	testData := []struct {
+		name          string
+		buildStub     func(idempotencyRepo mockRepo.MockIdempotencyRepository)
+		expectedKey   domain.IdempotencyKey
+		expectedRun   bool
+		expectedError error
+	}{
+		{
+			name: "first request",
+			buildStub: func(idempotencyRepo mockRepo.MockIdempotencyRepository) {
+				idempotencyRepo.EXPECT().ClaimIdempotencyKey(gomock.Any(), 1, "key_1", requestHash, gomock.Any(), gomock.Any()).Times(1).
+					Return(domain.IdempotencyKey{ID: 5, UserID: 1, Key: "key_1", RequestHash: requestHash}, true, nil)
+			},
+			expectedKey:   domain.IdempotencyKey{ID: 5, UserID: 1, Key: "key_1", RequestHash: requestHash},
+			expectedRun:   true,
+			expectedError: nil,
+		},
+		{
+			name: "retry of a completed request",
+			buildStub: func(idempotencyRepo mockRepo.MockIdempotencyRepository) {
+				idempotencyRepo.EXPECT().ClaimIdempotencyKey(gomock.Any(), 1, "key_1", requestHash, gomock.Any(), gomock.Any()).Times(1).
+					Return(domain.IdempotencyKey{ID: 5, UserID: 1, Key: "key_1", RequestHash: requestHash, Completed: true, StatusCode: 201, ResponseBody: []byte("{}")}, false, nil)
+			},
+			expectedKey:   domain.IdempotencyKey{ID: 5, UserID: 1, Key: "key_1", RequestHash: requestHash, Completed: true, StatusCode: 201, ResponseBody: []byte("{}")},
+			expectedRun:   false,
+			expectedError: nil,
+		},
+		{
+			name: "retry while the first request is running",
+			buildStub: func(idempotencyRepo mockRepo.MockIdempotencyRepository) {
+				idempotencyRepo.EXPECT().ClaimIdempotencyKey(gomock.Any(), 1, "key_1", requestHash, gomock.Any(), gomock.Any()).Times(1).
+					Return(domain.IdempotencyKey{ID: 5, UserID: 1, Key: "key_1", RequestHash: requestHash}, false, nil)
+			},
+			expectedKey:   domain.IdempotencyKey{},
+			expectedRun:   false,
+			expectedError: domain.ErrIdempotencyKeyInProgress,
+		},
+		{
+			name: "key reused for another request",
+			buildStub: func(idempotencyRepo mockRepo.MockIdempotencyRepository) {
+				idempotencyRepo.EXPECT().ClaimIdempotencyKey(gomock.Any(), 1, "key_1", requestHash, gomock.Any(), gomock.Any()).Times(1).
+					Return(domain.IdempotencyKey{ID: 5, UserID: 1, Key: "key_1", RequestHash: "another", Completed: true, StatusCode: 201}, false, nil)
+			},
+			expectedKey:   domain.IdempotencyKey{},
+			expectedRun:   false,
+			expectedError: domain.ErrIdempotencyKeyReused,
+		},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStub(*idempotencyRepo)
+			actualKey, actualRun, err := idempotencyUseCase.BeginRequest(context.TODO(), 1, "key_1", request)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedRun, actualRun)
+			assert.Equal(t, tt.expectedKey, actualKey)
+		})
+	}
+}
+
+func TestCompleteRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	idempotencyRepo := mockRepo.NewMockIdempotencyRepository(ctrl)
+	idempotencyUseCase := NewIdempotencyUseCase(idempotencyRepo, config.Config{})
+
+	claimedAt := time.Date(2023, 4, 2, 10, 0, 0, 0, time.UTC)
+	idempotencyKey := domain.IdempotencyKey{ID: 5, UserID: 1, Key: "key_1", CreatedAt: claimedAt}
+
+	testData := []struct {
+		name        string
+		statusCode  int
+		contentType string
+		buildStub   func(idempotencyRepo mockRepo.MockIdempotencyRepository)
+	}{
+		{
+			name:        "successful response",
+			statusCode:  http.StatusCreated,
+			contentType: "application/json; charset=utf-8",
+			buildStub: func(idempotencyRepo mockRepo.MockIdempotencyRepository) {
+				idempotencyRepo.EXPECT().SaveIdempotentResponse(gomock.Any(), uint(5), claimedAt, http.StatusCreated, "application/json; charset=utf-8", []byte("{}")).Times(1).Return(nil)
+			},
+		},
+		{
+			name:        "client error",
+			statusCode:  http.StatusConflict,
+			contentType: "application/json; charset=utf-8",
+			buildStub: func(idempotencyRepo mockRepo.MockIdempotencyRepository) {
+				idempotencyRepo.EXPECT().SaveIdempotentResponse(gomock.Any(), uint(5), claimedAt, http.StatusConflict, "application/json; charset=utf-8", []byte("{}")).Times(1).Return(nil)
+			},
+		},
+		{
+			name:        "html page",
+			statusCode:  http.StatusOK,
+			contentType: "text/html; charset=utf-8",
+			buildStub: func(idempotencyRepo mockRepo.MockIdempotencyRepository) {
+				idempotencyRepo.EXPECT().SaveIdempotentResponse(gomock.Any(), uint(5), claimedAt, http.StatusOK, "text/html; charset=utf-8", []byte("{}")).Times(1).Return(nil)
+			},
+		},
+		{
+			name:       "server error",
+			statusCode: http.StatusInternalServerError,
+			buildStub: func(idempotencyRepo mockRepo.MockIdempotencyRepository) {
+				idempotencyRepo.EXPECT().ReleaseIdempotencyKey(gomock.Any(), uint(5), claimedAt).Times(1).Return(nil)
+			},
+		},
+		{
+			name:       "redirect",
+			statusCode: http.StatusFound,
+			buildStub: func(idempotencyRepo mockRepo.MockIdempotencyRepository) {
+				idempotencyRepo.EXPECT().ReleaseIdempotencyKey(gomock.Any(), uint(5), claimedAt).Times(1).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStub(*idempotencyRepo)
+			err := idempotencyUseCase.CompleteRequest(context.TODO(), idempotencyKey, tt.statusCode, tt.contentType, []byte("{}"))
+			assert.NoError(t, err)
+		})
+	}
+}
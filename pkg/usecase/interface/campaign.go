@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+)
+
+type CampaignUseCase interface {
+	CreateCampaign(ctx context.Context, campaign model.CreateCouponCampaign) (domain.CouponCampaign, error)
+	ViewCampaigns(ctx context.Context) ([]model.CouponCampaignReport, error)
+	ViewCampaignCodes(ctx context.Context, campaignID int) ([]model.CampaignCode, error)
+}
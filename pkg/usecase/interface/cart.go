@@ -10,6 +10,9 @@ type CartUseCases interface {
 	AddToCart(ctx context.Context, userID, productItemID int) (domain.CartItems, error)
 	RemoveFromCart(ctx context.Context, userID, productItemID int) error
 	ViewCart(ctx context.Context, userID int) (model.ViewCart, error)
+	UpdateCartItem(ctx context.Context, userID, productItemID int, body model.UpdateCartItem) (model.ViewCart, error)
 	EmptyCart(ctx context.Context, userID int) error
 	AddCouponToCart(ctx context.Context, userID, couponID int) (model.ViewCart, error)
+	ApplicableCoupons(ctx context.Context, userID int) (model.ApplicableCoupons, error)
+	ApplyBestCoupon(ctx context.Context, userID int) (model.ApplicableCoupons, error)
 }
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+)
+
+type CODUseCase interface {
+	ViewCODRules(ctx context.Context) (model.CODRules, error)
+	UpdateCODRules(ctx context.Context, rules model.UpdateCODRules) (domain.CODRules, error)
+	AddCODPincode(ctx context.Context, pincode string) (domain.CODPincode, error)
+	RemoveCODPincode(ctx context.Context, pincode string) error
+
+	RecordCODCollection(ctx context.Context, collection model.CODCollection, adminID int) (domain.CODCollection, error)
+}
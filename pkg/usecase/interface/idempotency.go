@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+)
+
+type IdempotencyUseCase interface {
+	BeginRequest(ctx context.Context, userID int, key string, request []byte) (domain.IdempotencyKey, bool, error)
+	CompleteRequest(ctx context.Context, idempotencyKey domain.IdempotencyKey, statusCode int, contentType string, body []byte) error
+	AbortRequest(ctx context.Context, idempotencyKey domain.IdempotencyKey) error
+}
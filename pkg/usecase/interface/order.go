@@ -9,9 +9,16 @@ import (
 type OrderUseCases interface {
 	BuyProductItem(ctx context.Context, userID int, orderInfo model.PlaceOrder) (domain.Order, error)
 	BuyAll(ctx context.Context, userID int, orderInfo model.PlaceAllOrders) (domain.Order, error)
-	ViewOrderByID(ctx context.Context, orderID int, userID int) (domain.Order, error)
+	ViewOrderByID(ctx context.Context, orderID int, userID int) (model.OrderDetails, error)
 	ViewAllOrders(ctx context.Context, userID int) ([]domain.Order, error)
 	CancelOrder(ctx context.Context, orderID, userID int) (domain.Order, error)
 	UpdateOrder(ctx context.Context, orderInfo model.UpdateOrder) (domain.Order, error)
+	ViewOrderStatusHistory(ctx context.Context, orderID, userID int) ([]domain.OrderStatusHistory, error)
+	AdminViewOrderStatusHistory(ctx context.Context, orderID int) ([]domain.OrderStatusHistory, error)
 	ReturnRequest(ctx context.Context, userID int, returnRequest model.ReturnRequest) (domain.Order, error)
+	ListReturns(ctx context.Context, status string) ([]domain.Return, error)
+	ApproveReturn(ctx context.Context, returnID int, decision model.ReturnDecision) (domain.Return, error)
+	RejectReturn(ctx context.Context, returnID int, decision model.ReturnDecision) (domain.Return, error)
+	ReturnPickedUp(ctx context.Context, returnID int) (domain.Return, error)
+	ReturnReceived(ctx context.Context, returnID int) (domain.Return, error)
 }
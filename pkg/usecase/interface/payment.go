@@ -2,11 +2,13 @@ package interfaces
 
 import (
 	"context"
-	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"time"
 )
 
 type PaymentUseCases interface {
-	CreateRazorpayPayment(ctx context.Context, userID, orderID int) (domain.Order, string, error)
+	CreateRazorpayPayment(ctx context.Context, userID, orderID int) (model.PaymentCheckout, error)
 	UpdatePaymentDetails(ctx context.Context, paymentVerifier model.PaymentVerification) error
+	HandleRazorpayWebhook(ctx context.Context, payload []byte, signature, eventID string) error
+	ReconcilePayments(ctx context.Context, olderThan, expireAfter time.Duration) (model.PaymentReconciliation, error)
 }
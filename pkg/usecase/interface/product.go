@@ -31,9 +31,10 @@ type ProductUseCase interface {
 	UpdateProductItem(ctx context.Context, info domain.ProductItem) (domain.ProductItem, error)
 	DeleteProductItem(ctx context.Context, productItemID int) error
 
-	CreateCoupon(ctx context.Context, newCoupon model.CreateCoupon) (domain.Coupon, error)
-	UpdateCoupon(ctx context.Context, couponInfo model.UpdateCoupon) (domain.Coupon, error)
+	CreateCoupon(ctx context.Context, newCoupon model.CreateCoupon) (model.CouponDetails, error)
+	UpdateCoupon(ctx context.Context, couponInfo model.UpdateCoupon) (model.CouponDetails, error)
 	DeleteCoupon(ctx context.Context, couponID int) error
-	ViewCouponByID(ctx context.Context, couponID int) (domain.Coupon, error)
+	ViewCouponByID(ctx context.Context, couponID int) (model.CouponDetails, error)
 	ViewAllCoupons(ctx context.Context) ([]domain.Coupon, error)
+	CouponRedemptionReport(ctx context.Context) ([]model.CouponRedemptionReport, error)
 }
@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+)
+
+type RefundUseCase interface {
+	CreateRefund(ctx context.Context, newRefund model.CreateRefund) (domain.Refund, error)
+	ListRefunds(ctx context.Context, status string) ([]domain.Refund, error)
+	ViewOrderRefunds(ctx context.Context, orderID int) ([]domain.Refund, error)
+	RetryRefund(ctx context.Context, refundID int) (domain.Refund, error)
+	IssueRefund(ctx context.Context, refund domain.Refund) (domain.Refund, error)
+}
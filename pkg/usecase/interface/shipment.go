@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+)
+
+type ShipmentUseCase interface {
+	CreateCarrier(ctx context.Context, newCarrier model.CreateCarrier) (domain.Carrier, error)
+	ListCarriers(ctx context.Context) ([]domain.Carrier, error)
+
+	CreateShipment(ctx context.Context, newShipment model.CreateShipment) (model.ShipmentDetails, error)
+	AddShipmentEvent(ctx context.Context, event model.ShipmentEvent) (domain.ShipmentEvent, error)
+	ViewOrderShipments(ctx context.Context, orderID int) ([]model.ShipmentDetails, error)
+}
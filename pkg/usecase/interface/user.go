@@ -12,7 +12,11 @@ type UserUseCase interface {
 	LoginWithPhone(ctx context.Context, input model.UserLoginPhone) (string, model.UserDataOutput, error)
 
 	AddAddress(ctx context.Context, newAddress model.AddressInput, userID int) (domain.Address, error)
-	UpdateAddress(ctx context.Context, addressInfo model.AddressInput, userID int) (domain.Address, error)
+	UpdateAddress(ctx context.Context, addressID int, addressInfo model.AddressInput, userID int) (domain.Address, error)
+	ListAddresses(ctx context.Context, userID int) ([]domain.Address, error)
+	FindAddressByID(ctx context.Context, addressID, userID int) (domain.Address, error)
+	SetDefaultAddress(ctx context.Context, addressID, userID int) (domain.Address, error)
+	DeleteAddress(ctx context.Context, addressID, userID int) error
 
 	ListAllUsers(ctx context.Context, viewUserInfo model.QueryParams) ([]domain.Users, error)
 	FindUserByID(ctx context.Context, userID int) (domain.Users, error)
@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+)
+
+type WalletUseCase interface {
+	ViewWallet(ctx context.Context, userID int) (model.Wallet, error)
+	CreditWallet(ctx context.Context, adjustment model.WalletAdjustment, adminID int) (domain.WalletEntry, error)
+	DebitWallet(ctx context.Context, adjustment model.WalletAdjustment, adminID int) (domain.WalletEntry, error)
+}
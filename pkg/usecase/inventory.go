@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	interfaces "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/interface"
+	services "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/usecase/interface"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+)
+
+type inventoryUseCase struct {
+	inventoryRepo interfaces.InventoryRepository
+}
+
+func NewInventoryUseCase(inventoryRepo interfaces.InventoryRepository) services.InventoryUseCase {
+	return &inventoryUseCase{
+		inventoryRepo: inventoryRepo,
+	}
+}
+
+// AdjustStock posts a manual stock change by an admin. Sales, cancellations and returns are recorded by the order
+// flows themselves, so admins can only post restocks and adjustments.
+func (c *inventoryUseCase) AdjustStock(ctx context.Context, adjustment model.StockAdjustment, adminID int) (domain.StockMovement, error) {
+	if adjustment.Quantity == 0 {
+		return domain.StockMovement{}, fmt.Errorf("quantity cannot be zero")
+	}
+	if adjustment.Reason == "" {
+		adjustment.Reason = string(domain.StockAdjustment)
+	}
+	switch domain.StockMovementReason(adjustment.Reason) {
+	case domain.StockRestock:
+		if adjustment.Quantity < 0 {
+			return domain.StockMovement{}, fmt.Errorf("restock quantity should be positive")
+		}
+	case domain.StockAdjustment:
+		if adjustment.Note == "" {
+			return domain.StockMovement{}, fmt.Errorf("note is required for manual adjustment")
+		}
+	default:
+		return domain.StockMovement{}, fmt.Errorf("invalid reason, should be restock or adjustment")
+	}
+	movement, err := c.inventoryRepo.AdjustStock(ctx, adjustment, adminID)
+	return movement, err
+}
+
+func (c *inventoryUseCase) ViewStockMovements(ctx context.Context, productItemID int) ([]domain.StockMovement, error) {
+	movements, err := c.inventoryRepo.ViewStockMovements(ctx, productItemID)
+	return movements, err
+}
+
+func (c *inventoryUseCase) ReconcileStock(ctx context.Context) ([]model.StockReconciliation, error) {
+	mismatches, err := c.inventoryRepo.ReconcileStock(ctx)
+	return mismatches, err
+}
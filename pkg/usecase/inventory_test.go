@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/mockRepo"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAdjustStock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	inventoryRepo := mockRepo.NewMockInventoryRepository(ctrl)
+
+	inventoryUseCase := NewInventoryUseCase(inventoryRepo)
+
+	testData := []struct {
+		name           string
+		input          model.StockAdjustment
+		buildStub      func(inventoryRepo mockRepo.MockInventoryRepository)
+		expectedOutput domain.StockMovement
+		expectedError  error
+	}{
+		{
+			name:  "restock",
+			input: model.StockAdjustment{ProductItemID: 3, Quantity: 10, Reason: "restock"},
+			buildStub: func(inventoryRepo mockRepo.MockInventoryRepository) {
+				inventoryRepo.EXPECT().AdjustStock(gomock.Any(), model.StockAdjustment{ProductItemID: 3, Quantity: 10, Reason: "restock"}, 1).Times(1).
+					Return(domain.StockMovement{ID: 1, ProductItemID: 3, Quantity: 10, Reason: domain.StockRestock, AdminID: 1}, nil)
+			},
+			expectedOutput: domain.StockMovement{ID: 1, ProductItemID: 3, Quantity: 10, Reason: domain.StockRestock, AdminID: 1},
+			expectedError:  nil,
+		},
+		{
+			name:  "adjustment is the default reason",
+			input: model.StockAdjustment{ProductItemID: 3, Quantity: -2, Note: "damaged in warehouse"},
+			buildStub: func(inventoryRepo mockRepo.MockInventoryRepository) {
+				inventoryRepo.EXPECT().AdjustStock(gomock.Any(), model.StockAdjustment{ProductItemID: 3, Quantity: -2, Reason: "adjustment", Note: "damaged in warehouse"}, 1).Times(1).
+					Return(domain.StockMovement{ID: 2, ProductItemID: 3, Quantity: -2, Reason: domain.StockAdjustment, AdminID: 1, Note: "damaged in warehouse"}, nil)
+			},
+			expectedOutput: domain.StockMovement{ID: 2, ProductItemID: 3, Quantity: -2, Reason: domain.StockAdjustment, AdminID: 1, Note: "damaged in warehouse"},
+			expectedError:  nil,
+		},
+		{
+			name:           "adjustment without note",
+			input:          model.StockAdjustment{ProductItemID: 3, Quantity: -2, Reason: "adjustment"},
+			buildStub:      func(inventoryRepo mockRepo.MockInventoryRepository) {},
+			expectedOutput: domain.StockMovement{},
+			expectedError:  errors.New("note is required for manual adjustment"),
+		},
+		{
+			name:           "negative restock",
+			input:          model.StockAdjustment{ProductItemID: 3, Quantity: -5, Reason: "restock"},
+			buildStub:      func(inventoryRepo mockRepo.MockInventoryRepository) {},
+			expectedOutput: domain.StockMovement{},
+			expectedError:  errors.New("restock quantity should be positive"),
+		},
+		{
+			name:           "sale cannot be posted by admin",
+			input:          model.StockAdjustment{ProductItemID: 3, Quantity: -1, Reason: "sale"},
+			buildStub:      func(inventoryRepo mockRepo.MockInventoryRepository) {},
+			expectedOutput: domain.StockMovement{},
+			expectedError:  errors.New("invalid reason, should be restock or adjustment"),
+		},
+		{
+			name:           "zero quantity",
+			input:          model.StockAdjustment{ProductItemID: 3, Reason: "restock"},
+			buildStub:      func(inventoryRepo mockRepo.MockInventoryRepository) {},
+			expectedOutput: domain.StockMovement{},
+			expectedError:  errors.New("quantity cannot be zero"),
+		},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStub(*inventoryRepo)
+			actualMovement, err := inventoryUseCase.AdjustStock(context.TODO(), tt.input, 1)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, actualMovement)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/usecase/interface (interfaces: AdminUseCase)
+
+// Package mockUsecase is a generated GoMock package.
+package mockUsecase
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	model "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAdminUseCase is a mock of AdminUseCase interface.
+type MockAdminUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdminUseCaseMockRecorder
+}
+
+// MockAdminUseCaseMockRecorder is the mock recorder for MockAdminUseCase.
+type MockAdminUseCaseMockRecorder struct {
+	mock *MockAdminUseCase
+}
+
+// NewMockAdminUseCase creates a new mock instance.
+func NewMockAdminUseCase(ctrl *gomock.Controller) *MockAdminUseCase {
+	mock := &MockAdminUseCase{ctrl: ctrl}
+	mock.recorder = &MockAdminUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdminUseCase) EXPECT() *MockAdminUseCaseMockRecorder {
+	return m.recorder
+}
+
+// AdminDashboard mocks base method.
+func (m *MockAdminUseCase) AdminDashboard(arg0 context.Context) (model.AdminDashboard, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminDashboard", arg0)
+	ret0, _ := ret[0].(model.AdminDashboard)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminDashboard indicates an expected call of AdminDashboard.
+func (mr *MockAdminUseCaseMockRecorder) AdminDashboard(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminDashboard", reflect.TypeOf((*MockAdminUseCase)(nil).AdminDashboard), arg0)
+}
+
+// AdminLogin mocks base method.
+func (m *MockAdminUseCase) AdminLogin(arg0 context.Context, arg1 model.AdminLogin) (string, model.AdminDataOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminLogin", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(model.AdminDataOutput)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AdminLogin indicates an expected call of AdminLogin.
+func (mr *MockAdminUseCaseMockRecorder) AdminLogin(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminLogin", reflect.TypeOf((*MockAdminUseCase)(nil).AdminLogin), arg0, arg1)
+}
+
+// BlockAdmin mocks base method.
+func (m *MockAdminUseCase) BlockAdmin(arg0 context.Context, arg1, arg2 int) (domain.Admin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockAdmin", arg0, arg1, arg2)
+	ret0, _ := ret[0].(domain.Admin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockAdmin indicates an expected call of BlockAdmin.
+func (mr *MockAdminUseCaseMockRecorder) BlockAdmin(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockAdmin", reflect.TypeOf((*MockAdminUseCase)(nil).BlockAdmin), arg0, arg1, arg2)
+}
+
+// CreateAdmin mocks base method.
+func (m *MockAdminUseCase) CreateAdmin(arg0 context.Context, arg1 model.NewAdminInfo, arg2 int) (domain.Admin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAdmin", arg0, arg1, arg2)
+	ret0, _ := ret[0].(domain.Admin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAdmin indicates an expected call of CreateAdmin.
+func (mr *MockAdminUseCaseMockRecorder) CreateAdmin(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAdmin", reflect.TypeOf((*MockAdminUseCase)(nil).CreateAdmin), arg0, arg1, arg2)
+}
+
+// FindAdminID mocks base method.
+func (m *MockAdminUseCase) FindAdminID(arg0 context.Context, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAdminID", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAdminID indicates an expected call of FindAdminID.
+func (mr *MockAdminUseCaseMockRecorder) FindAdminID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAdminID", reflect.TypeOf((*MockAdminUseCase)(nil).FindAdminID), arg0, arg1)
+}
+
+// SalesReport mocks base method.
+func (m *MockAdminUseCase) SalesReport(arg0 context.Context) ([]model.SalesReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SalesReport", arg0)
+	ret0, _ := ret[0].([]model.SalesReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SalesReport indicates an expected call of SalesReport.
+func (mr *MockAdminUseCaseMockRecorder) SalesReport(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SalesReport", reflect.TypeOf((*MockAdminUseCase)(nil).SalesReport), arg0)
+}
+
+// UnblockAdmin mocks base method.
+func (m *MockAdminUseCase) UnblockAdmin(arg0 context.Context, arg1, arg2 int) (domain.Admin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnblockAdmin", arg0, arg1, arg2)
+	ret0, _ := ret[0].(domain.Admin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnblockAdmin indicates an expected call of UnblockAdmin.
+func (mr *MockAdminUseCaseMockRecorder) UnblockAdmin(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnblockAdmin", reflect.TypeOf((*MockAdminUseCase)(nil).UnblockAdmin), arg0, arg1, arg2)
+}
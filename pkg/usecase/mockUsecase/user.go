@@ -81,6 +81,35 @@ func (mr *MockUserUseCaseMockRecorder) CreateUser(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserUseCase)(nil).CreateUser), arg0, arg1)
 }
 
+// DeleteAddress mocks base method.
+func (m *MockUserUseCase) DeleteAddress(arg0 context.Context, arg1, arg2 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAddress", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAddress indicates an expected call of DeleteAddress.
+func (mr *MockUserUseCaseMockRecorder) DeleteAddress(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAddress", reflect.TypeOf((*MockUserUseCase)(nil).DeleteAddress), arg0, arg1, arg2)
+}
+
+// FindAddressByID mocks base method.
+func (m *MockUserUseCase) FindAddressByID(arg0 context.Context, arg1, arg2 int) (domain.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAddressByID", arg0, arg1, arg2)
+	ret0, _ := ret[0].(domain.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAddressByID indicates an expected call of FindAddressByID.
+func (mr *MockUserUseCaseMockRecorder) FindAddressByID(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAddressByID", reflect.TypeOf((*MockUserUseCase)(nil).FindAddressByID), arg0, arg1, arg2)
+}
+
 // FindUserByID mocks base method.
 func (m *MockUserUseCase) FindUserByID(arg0 context.Context, arg1 int) (domain.Users, error) {
 	m.ctrl.T.Helper()
@@ -96,6 +125,21 @@ func (mr *MockUserUseCaseMockRecorder) FindUserByID(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUserByID", reflect.TypeOf((*MockUserUseCase)(nil).FindUserByID), arg0, arg1)
 }
 
+// ListAddresses mocks base method.
+func (m *MockUserUseCase) ListAddresses(arg0 context.Context, arg1 int) ([]domain.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAddresses", arg0, arg1)
+	ret0, _ := ret[0].([]domain.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAddresses indicates an expected call of ListAddresses.
+func (mr *MockUserUseCaseMockRecorder) ListAddresses(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAddresses", reflect.TypeOf((*MockUserUseCase)(nil).ListAddresses), arg0, arg1)
+}
+
 // ListAllUsers mocks base method.
 func (m *MockUserUseCase) ListAllUsers(arg0 context.Context, arg1 model.QueryParams) ([]domain.Users, error) {
 	m.ctrl.T.Helper()
@@ -143,6 +187,21 @@ func (mr *MockUserUseCaseMockRecorder) LoginWithPhone(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginWithPhone", reflect.TypeOf((*MockUserUseCase)(nil).LoginWithPhone), arg0, arg1)
 }
 
+// SetDefaultAddress mocks base method.
+func (m *MockUserUseCase) SetDefaultAddress(arg0 context.Context, arg1, arg2 int) (domain.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDefaultAddress", arg0, arg1, arg2)
+	ret0, _ := ret[0].(domain.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetDefaultAddress indicates an expected call of SetDefaultAddress.
+func (mr *MockUserUseCaseMockRecorder) SetDefaultAddress(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDefaultAddress", reflect.TypeOf((*MockUserUseCase)(nil).SetDefaultAddress), arg0, arg1, arg2)
+}
+
 // UnblockUser mocks base method.
 func (m *MockUserUseCase) UnblockUser(arg0 context.Context, arg1 int) (domain.UserInfo, error) {
 	m.ctrl.T.Helper()
@@ -159,18 +218,18 @@ func (mr *MockUserUseCaseMockRecorder) UnblockUser(arg0, arg1 interface{}) *gomo
 }
 
 // UpdateAddress mocks base method.
-func (m *MockUserUseCase) UpdateAddress(arg0 context.Context, arg1 model.AddressInput, arg2 int) (domain.Address, error) {
+func (m *MockUserUseCase) UpdateAddress(arg0 context.Context, arg1 int, arg2 model.AddressInput, arg3 int) (domain.Address, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateAddress", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "UpdateAddress", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(domain.Address)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateAddress indicates an expected call of UpdateAddress.
-func (mr *MockUserUseCaseMockRecorder) UpdateAddress(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockUserUseCaseMockRecorder) UpdateAddress(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAddress", reflect.TypeOf((*MockUserUseCase)(nil).UpdateAddress), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAddress", reflect.TypeOf((*MockUserUseCase)(nil).UpdateAddress), arg0, arg1, arg2, arg3)
 }
 
 // UserProfile mocks base method.
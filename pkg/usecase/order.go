@@ -11,92 +11,105 @@ import (
 )
 
 type orderUseCase struct {
-	orderRepo   interfaces.OrderRepository
-	userRepo    interfaces.UserRepository
-	productRepo interfaces.ProductRepository
+	orderRepo    interfaces.OrderRepository
+	userRepo     interfaces.UserRepository
+	productRepo  interfaces.ProductRepository
+	shipmentRepo interfaces.ShipmentRepository
+	refunds      services.RefundUseCase
 }
 
-func NewOrderUseCase(orderRepo interfaces.OrderRepository, userRepo interfaces.UserRepository, productRepo interfaces.ProductRepository) services.OrderUseCases {
+func NewOrderUseCase(orderRepo interfaces.OrderRepository, userRepo interfaces.UserRepository, productRepo interfaces.ProductRepository, shipmentRepo interfaces.ShipmentRepository, refunds services.RefundUseCase) services.OrderUseCases {
 	return &orderUseCase{
-		orderRepo:   orderRepo,
-		userRepo:    userRepo,
-		productRepo: productRepo,
+		orderRepo:    orderRepo,
+		userRepo:     userRepo,
+		productRepo:  productRepo,
+		shipmentRepo: shipmentRepo,
+		refunds:      refunds,
 	}
 }
 
 func (c *orderUseCase) BuyProductItem(ctx context.Context, userID int, orderInfo model.PlaceOrder) (domain.Order, error) {
 
-	//check if user has added address. If not, return error
-	address, err := c.userRepo.ViewAddress(ctx, userID)
+	//validate the shipping address, falling back to the default address of the user
+	addressID, err := c.resolveShippingAddress(ctx, userID, orderInfo.ShippingAddressID)
 	if err != nil {
 		return domain.Order{}, err
 	}
-	if address.ID == 0 {
-		return domain.Order{}, fmt.Errorf("cannot place order without adding address")
-	}
-
-	//check if coupon is valid, applicable
-	var appliedCoupon domain.Coupon
-
-	//if user applied a coupon
-	if orderInfo.CouponID != 0 {
-
-		//check if coupon is already used
-		isUsed, err := c.productRepo.CouponUsed(ctx, userID, orderInfo.CouponID)
-		if err != nil {
-			return domain.Order{}, err
-		}
-		if isUsed {
-			return domain.Order{}, fmt.Errorf("coupon already used")
-		}
-
-		appliedCoupon, err = c.productRepo.ViewCouponByID(ctx, orderInfo.CouponID)
-		if err != nil {
-			return domain.Order{}, fmt.Errorf("failed to fetch coupon details")
-		}
-		currentTime := time.Now()
-		if appliedCoupon.ValidTill.Before(currentTime) {
-			return domain.Order{}, fmt.Errorf("expired coupon")
-		}
-
-		productInfo, err := c.productRepo.FindProductItemByID(ctx, orderInfo.ProductItemID)
-		if err != nil {
-			return domain.Order{}, err
-		}
-		if productInfo.ID == 0 {
-			return domain.Order{}, fmt.Errorf("failed to fetch product item details")
-		}
+	orderInfo.ShippingAddressID = addressID
 
-		//	check is product is eligible for coupon discount
-		if productInfo.Price < appliedCoupon.MinOrderValue {
-			return domain.Order{}, fmt.Errorf("cannot apply coupon as order total is less than required")
-		}
+	//a single item is ordered if no quantity is given
+	if orderInfo.Quantity == 0 {
+		orderInfo.Quantity = 1
+	}
+	if orderInfo.Quantity < 0 {
+		return domain.Order{}, fmt.Errorf("invalid quantity")
 	}
 
+	//the coupon is checked against its rules when the order is priced
 	order, err := c.orderRepo.BuyProductItem(ctx, userID, orderInfo)
 	return order, err
 }
 
 func (c *orderUseCase) BuyAll(ctx context.Context, userID int, orderInfo model.PlaceAllOrders) (domain.Order, error) {
 
-	//check if user has added address. If not, return error
-	address, err := c.userRepo.ViewAddress(ctx, userID)
+	//validate the shipping address, falling back to the default address of the user
+	addressID, err := c.resolveShippingAddress(ctx, userID, orderInfo.ShippingAddressID)
 	if err != nil {
 		return domain.Order{}, err
 	}
-	if address.ID == 0 {
-		return domain.Order{}, fmt.Errorf("cannot place order without adding address")
-	}
+	orderInfo.ShippingAddressID = addressID
 
 	orders, err := c.orderRepo.BuyAll(ctx, userID, orderInfo)
 
 	return orders, err
 }
 
-func (c *orderUseCase) ViewOrderByID(ctx context.Context, orderID int, userID int) (domain.Order, error) {
+// resolveShippingAddress returns the id of the address an order should be shipped to. If the user did not choose
+// an address, the default address is used. A chosen address must belong to the user placing the order.
+func (c *orderUseCase) resolveShippingAddress(ctx context.Context, userID, addressID int) (int, error) {
+	if addressID == 0 {
+		address, err := c.userRepo.FindDefaultAddress(ctx, userID)
+		if err != nil {
+			return 0, err
+		}
+		if address.ID == 0 {
+			return 0, fmt.Errorf("cannot place order without adding address")
+		}
+		return int(address.ID), nil
+	}
+
+	address, err := c.userRepo.FindAddressByID(ctx, userID, addressID)
+	if err != nil {
+		return 0, fmt.Errorf("invalid shipping address")
+	}
+	return int(address.ID), nil
+}
+
+func (c *orderUseCase) ViewOrderByID(ctx context.Context, orderID int, userID int) (model.OrderDetails, error) {
 	order, err := c.orderRepo.ViewOrderById(ctx, userID, orderID)
-	return order, err
+	if err != nil {
+		return model.OrderDetails{}, err
+	}
+	orderDetails := model.OrderDetails{Order: order}
+
+	orderDetails.Shipments, err = c.shipmentRepo.ViewOrderShipments(ctx, orderID)
+	if err != nil {
+		return model.OrderDetails{}, err
+	}
 
+	returnDetails, err := c.orderRepo.FindReturnByOrderID(ctx, orderID)
+	if err != nil {
+		return model.OrderDetails{}, err
+	}
+	if returnDetails.ID != 0 {
+		orderDetails.Return = &returnDetails
+	}
+
+	orderDetails.Refunds, err = c.refunds.ViewOrderRefunds(ctx, orderID)
+	if err != nil {
+		return model.OrderDetails{}, err
+	}
+	return orderDetails, nil
 }
 
 func (c *orderUseCase) ViewAllOrders(ctx context.Context, userID int) ([]domain.Order, error) {
@@ -105,18 +118,95 @@ func (c *orderUseCase) ViewAllOrders(ctx context.Context, userID int) ([]domain.
 }
 
 func (c *orderUseCase) CancelOrder(ctx context.Context, orderID, userID int) (domain.Order, error) {
-	order, err := c.orderRepo.CancelOrder(ctx, userID, orderID)
-	return order, err
+	order, err := c.orderRepo.ViewOrderById(ctx, userID, orderID)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if order.OrderStatusID == domain.OrderCancelledByUser {
+		return domain.Order{}, &domain.OrderStatusError{Reason: "order already cancelled"}
+	}
+	err = domain.CheckOrderTransition(order.OrderStatusID, order.DeliveryStatusID, domain.OrderCancelledByUser, order.DeliveryStatusID)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	cancelledOrder, refund, err := c.orderRepo.CancelOrder(ctx, userID, orderID)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if err := c.issueRefund(ctx, refund); err != nil {
+		return domain.Order{}, err
+	}
+	return cancelledOrder, nil
 }
 
 func (c *orderUseCase) UpdateOrder(ctx context.Context, orderInfo model.UpdateOrder) (domain.Order, error) {
-	order, err := c.orderRepo.UpdateOrder(ctx, orderInfo)
-	return order, err
+	order, err := c.orderRepo.FindOrderByID(ctx, orderInfo.OrderID)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if order.ID == 0 {
+		return domain.Order{}, fmt.Errorf("no order found")
+	}
+
+	//a status that is not sent is kept as it is
+	if orderInfo.OrderStatusID == 0 {
+		orderInfo.OrderStatusID = order.OrderStatusID
+	}
+	if orderInfo.DeliveryStatusID == 0 {
+		orderInfo.DeliveryStatusID = order.DeliveryStatusID
+	}
+
+	//cancellation by user and return requests are placed by the user, admin cannot move an order into them
+	toStatus := orderInfo.OrderStatusID
+	if toStatus != order.OrderStatusID && (toStatus == domain.OrderCancelledByUser || toStatus == domain.OrderReturnRequested) {
+		return domain.Order{}, &domain.OrderStatusError{Reason: fmt.Sprintf("order status %v can only be set by the user", toStatus.Name())}
+	}
+	err = domain.CheckOrderTransition(order.OrderStatusID, order.DeliveryStatusID, toStatus, orderInfo.DeliveryStatusID)
+	if err != nil {
+		return domain.Order{}, err
+	}
+
+	updatedOrder, refund, err := c.orderRepo.UpdateOrder(ctx, order, orderInfo)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if err := c.issueRefund(ctx, refund); err != nil {
+		return domain.Order{}, err
+	}
+	return updatedOrder, nil
+}
+
+func (c *orderUseCase) ViewOrderStatusHistory(ctx context.Context, orderID, userID int) ([]domain.OrderStatusHistory, error) {
+	//users can only view the history of their own orders
+	if _, err := c.orderRepo.ViewOrderById(ctx, userID, orderID); err != nil {
+		return nil, err
+	}
+	history, err := c.orderRepo.ViewOrderStatusHistory(ctx, orderID)
+	return withStatusNames(history), err
+}
+
+func (c *orderUseCase) AdminViewOrderStatusHistory(ctx context.Context, orderID int) ([]domain.OrderStatusHistory, error) {
+	history, err := c.orderRepo.ViewOrderStatusHistory(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return nil, fmt.Errorf("no order found")
+	}
+	return withStatusNames(history), nil
+}
+
+func withStatusNames(history []domain.OrderStatusHistory) []domain.OrderStatusHistory {
+	for i := range history {
+		history[i].OrderStatus = history[i].OrderStatusID.Name()
+		history[i].DeliveryStatus = history[i].DeliveryStatusID.Name()
+	}
+	return history
 }
 
 func (c *orderUseCase) ReturnRequest(ctx context.Context, userID int, returnRequest model.ReturnRequest) (domain.Order, error) {
 	//	check if order is eligible to be returned
-	//	users can request for return only if order status is completed(4), delivery status is delivered(5) and is within 15 days of order delivery
+	//	users can request for return only if order is completed, delivered and is within 15 days of order delivery
 
 	orderDetails, err := c.orderRepo.ViewOrderById(ctx, userID, returnRequest.OrderID)
 	fmt.Println(orderDetails)
@@ -126,11 +216,11 @@ func (c *orderUseCase) ReturnRequest(ctx context.Context, userID int, returnRequ
 	if orderDetails.ID == 0 {
 		return domain.Order{}, fmt.Errorf("no such order found")
 	}
-	if orderDetails.DeliveryUpdatedAt.Sub(time.Now()) > time.Hour*24*15 {
+	if time.Since(orderDetails.DeliveryUpdatedAt) > time.Hour*24*15 {
 		return domain.Order{}, fmt.Errorf("failed to place return request as it is more than 15 days")
 	}
-	if orderDetails.OrderStatusID != 4 || orderDetails.DeliveryStatusID != 5 {
-		return domain.Order{}, fmt.Errorf("cannot return as order status is %v and delivery status is %v", orderDetails.OrderStatusID, orderDetails.DeliveryStatusID)
+	if orderDetails.OrderStatusID != domain.OrderCompleted || orderDetails.DeliveryStatusID != domain.DeliveryDelivered {
+		return domain.Order{}, fmt.Errorf("cannot return as order status is %v and delivery status is %v", orderDetails.OrderStatusID.Name(), orderDetails.DeliveryStatusID.Name())
 	}
 	order, err := c.orderRepo.ReturnRequest(ctx, returnRequest)
 	if err != nil {
@@ -138,3 +228,56 @@ func (c *orderUseCase) ReturnRequest(ctx context.Context, userID int, returnRequ
 	}
 	return order, nil
 }
+
+func (c *orderUseCase) ListReturns(ctx context.Context, status string) ([]domain.Return, error) {
+	//pending return requests are listed by default
+	if status == "" {
+		status = string(domain.ReturnRequested)
+	}
+	switch domain.ReturnStatus(status) {
+	case domain.ReturnRequested, domain.ReturnApproved, domain.ReturnRejected, domain.ReturnPickedUp, domain.ReturnReceived:
+	default:
+		return nil, fmt.Errorf("invalid return status %v", status)
+	}
+	returns, err := c.orderRepo.ListReturns(ctx, status)
+	return returns, err
+}
+
+func (c *orderUseCase) ApproveReturn(ctx context.Context, returnID int, decision model.ReturnDecision) (domain.Return, error) {
+	returnDetails, refund, err := c.orderRepo.ApproveReturn(ctx, returnID, decision.Note)
+	if err != nil {
+		return domain.Return{}, err
+	}
+	if err := c.issueRefund(ctx, refund); err != nil {
+		return domain.Return{}, err
+	}
+	return returnDetails, nil
+}
+
+// issueRefund sends a refund initiated by a cancellation or a return to the payment gateway
+func (c *orderUseCase) issueRefund(ctx context.Context, refund domain.Refund) error {
+	if refund.ID == 0 {
+		return nil
+	}
+	_, err := c.refunds.IssueRefund(ctx, refund)
+	return err
+}
+
+func (c *orderUseCase) RejectReturn(ctx context.Context, returnID int, decision model.ReturnDecision) (domain.Return, error) {
+	//user should know why the return was rejected
+	if decision.Note == "" {
+		return domain.Return{}, fmt.Errorf("note is required to reject a return")
+	}
+	returnDetails, err := c.orderRepo.RejectReturn(ctx, returnID, decision.Note)
+	return returnDetails, err
+}
+
+func (c *orderUseCase) ReturnPickedUp(ctx context.Context, returnID int) (domain.Return, error) {
+	returnDetails, err := c.orderRepo.ReturnPickedUp(ctx, returnID)
+	return returnDetails, err
+}
+
+func (c *orderUseCase) ReturnReceived(ctx context.Context, returnID int) (domain.Return, error) {
+	returnDetails, err := c.orderRepo.ReturnReceived(ctx, returnID)
+	return returnDetails, err
+}
@@ -0,0 +1,491 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/mockRepo"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestBuyAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	userRepo := mockRepo.NewMockUserRepository(ctrl)
+	orderRepo := mockRepo.NewMockOrderRepository(ctrl)
+
+	orderUseCase := NewOrderUseCase(orderRepo, userRepo, nil, nil, nil)
+
+	testData := []struct {
+		name           string
+		input          model.PlaceAllOrders
+		buildStub      func(userRepo mockRepo.MockUserRepository, orderRepo mockRepo.MockOrderRepository)
+		expectedOutput domain.Order
+		expectedError  error
+	}{
+		{
+			name:  "default address is used when no address is chosen",
+			input: model.PlaceAllOrders{PaymentMethodID: 1},
+			buildStub: func(userRepo mockRepo.MockUserRepository, orderRepo mockRepo.MockOrderRepository) {
+				userRepo.EXPECT().FindDefaultAddress(gomock.Any(), 1).Times(1).
+					Return(domain.Address{ID: 7, UserID: 1, IsDefault: true}, nil)
+				orderRepo.EXPECT().BuyAll(gomock.Any(), 1, model.PlaceAllOrders{PaymentMethodID: 1, ShippingAddressID: 7}).Times(1).
+					Return(domain.Order{ID: 1, ShippingAddressID: 7}, nil)
+			},
+			expectedOutput: domain.Order{ID: 1, ShippingAddressID: 7},
+			expectedError:  nil,
+		},
+		{
+			name:  "user without any address",
+			input: model.PlaceAllOrders{PaymentMethodID: 1},
+			buildStub: func(userRepo mockRepo.MockUserRepository, orderRepo mockRepo.MockOrderRepository) {
+				userRepo.EXPECT().FindDefaultAddress(gomock.Any(), 1).Times(1).
+					Return(domain.Address{}, nil)
+			},
+			expectedOutput: domain.Order{},
+			expectedError:  errors.New("cannot place order without adding address"),
+		},
+		{
+			name:  "address belonging to another user",
+			input: model.PlaceAllOrders{PaymentMethodID: 1, ShippingAddressID: 9},
+			buildStub: func(userRepo mockRepo.MockUserRepository, orderRepo mockRepo.MockOrderRepository) {
+				userRepo.EXPECT().FindAddressByID(gomock.Any(), 1, 9).Times(1).
+					Return(domain.Address{}, errors.New("no address found"))
+			},
+			expectedOutput: domain.Order{},
+			expectedError:  errors.New("invalid shipping address"),
+		},
+		{
+			name:  "chosen address of the user",
+			input: model.PlaceAllOrders{PaymentMethodID: 2, ShippingAddressID: 3},
+			buildStub: func(userRepo mockRepo.MockUserRepository, orderRepo mockRepo.MockOrderRepository) {
+				userRepo.EXPECT().FindAddressByID(gomock.Any(), 1, 3).Times(1).
+					Return(domain.Address{ID: 3, UserID: 1}, nil)
+				orderRepo.EXPECT().BuyAll(gomock.Any(), 1, model.PlaceAllOrders{PaymentMethodID: 2, ShippingAddressID: 3}).Times(1).
+					Return(domain.Order{ID: 2, ShippingAddressID: 3}, nil)
+			},
+			expectedOutput: domain.Order{ID: 2, ShippingAddressID: 3},
+			expectedError:  nil,
+		},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStub(*userRepo, *orderRepo)
+			actualOrder, err := orderUseCase.BuyAll(context.TODO(), 1, tt.input)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, actualOrder)
+		})
+	}
+}
+
+func TestBuyProductItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	userRepo := mockRepo.NewMockUserRepository(ctrl)
+	orderRepo := mockRepo.NewMockOrderRepository(ctrl)
+
+	orderUseCase := NewOrderUseCase(orderRepo, userRepo, nil, nil, nil)
+
+	testData := []struct {
+		name           string
+		input          model.PlaceOrder
+		buildStub      func(userRepo mockRepo.MockUserRepository, orderRepo mockRepo.MockOrderRepository)
+		expectedOutput domain.Order
+		expectedError  error
+	}{
+		{
+			name:  "single item is ordered when no quantity is given",
+			input: model.PlaceOrder{ProductItemID: 5, PaymentMethodID: 1, ShippingAddressID: 3},
+			buildStub: func(userRepo mockRepo.MockUserRepository, orderRepo mockRepo.MockOrderRepository) {
+				userRepo.EXPECT().FindAddressByID(gomock.Any(), 1, 3).Times(1).
+					Return(domain.Address{ID: 3, UserID: 1}, nil)
+				orderRepo.EXPECT().BuyProductItem(gomock.Any(), 1, model.PlaceOrder{ProductItemID: 5, Quantity: 1, PaymentMethodID: 1, ShippingAddressID: 3}).Times(1).
+					Return(domain.Order{ID: 1, OrderTotal: 50000}, nil)
+			},
+			expectedOutput: domain.Order{ID: 1, OrderTotal: 50000},
+			expectedError:  nil,
+		},
+		{
+			name:  "bulk order",
+			input: model.PlaceOrder{ProductItemID: 5, Quantity: 10, PaymentMethodID: 1, ShippingAddressID: 3},
+			buildStub: func(userRepo mockRepo.MockUserRepository, orderRepo mockRepo.MockOrderRepository) {
+				userRepo.EXPECT().FindAddressByID(gomock.Any(), 1, 3).Times(1).
+					Return(domain.Address{ID: 3, UserID: 1}, nil)
+				orderRepo.EXPECT().BuyProductItem(gomock.Any(), 1, model.PlaceOrder{ProductItemID: 5, Quantity: 10, PaymentMethodID: 1, ShippingAddressID: 3}).Times(1).
+					Return(domain.Order{ID: 2, OrderTotal: 500000}, nil)
+			},
+			expectedOutput: domain.Order{ID: 2, OrderTotal: 500000},
+			expectedError:  nil,
+		},
+		{
+			name:  "shipped to the default address when no address is chosen",
+			input: model.PlaceOrder{ProductItemID: 5, Quantity: 2, PaymentMethodID: 1},
+			buildStub: func(userRepo mockRepo.MockUserRepository, orderRepo mockRepo.MockOrderRepository) {
+				userRepo.EXPECT().FindDefaultAddress(gomock.Any(), 1).Times(1).
+					Return(domain.Address{ID: 7, UserID: 1, IsDefault: true}, nil)
+				orderRepo.EXPECT().BuyProductItem(gomock.Any(), 1, model.PlaceOrder{ProductItemID: 5, Quantity: 2, PaymentMethodID: 1, ShippingAddressID: 7}).Times(1).
+					Return(domain.Order{ID: 3, ShippingAddressID: 7, OrderTotal: 100000}, nil)
+			},
+			expectedOutput: domain.Order{ID: 3, ShippingAddressID: 7, OrderTotal: 100000},
+			expectedError:  nil,
+		},
+		{
+			name:  "no address chosen and no default address",
+			input: model.PlaceOrder{ProductItemID: 5, Quantity: 2, PaymentMethodID: 1},
+			buildStub: func(userRepo mockRepo.MockUserRepository, orderRepo mockRepo.MockOrderRepository) {
+				userRepo.EXPECT().FindDefaultAddress(gomock.Any(), 1).Times(1).
+					Return(domain.Address{}, nil)
+			},
+			expectedOutput: domain.Order{},
+			expectedError:  errors.New("cannot place order without adding address"),
+		},
+		{
+			name:  "negative quantity",
+			input: model.PlaceOrder{ProductItemID: 5, Quantity: -2, PaymentMethodID: 1, ShippingAddressID: 3},
+			buildStub: func(userRepo mockRepo.MockUserRepository, orderRepo mockRepo.MockOrderRepository) {
+				userRepo.EXPECT().FindAddressByID(gomock.Any(), 1, 3).Times(1).
+					Return(domain.Address{ID: 3, UserID: 1}, nil)
+			},
+			expectedOutput: domain.Order{},
+			expectedError:  errors.New("invalid quantity"),
+		},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStub(*userRepo, *orderRepo)
+			actualOrder, err := orderUseCase.BuyProductItem(context.TODO(), 1, tt.input)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, actualOrder)
+		})
+	}
+}
+
+func TestViewOrderByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	orderRepo := mockRepo.NewMockOrderRepository(ctrl)
+	shipmentRepo := mockRepo.NewMockShipmentRepository(ctrl)
+	refundRepo := mockRepo.NewMockRefundRepository(ctrl)
+
+	orderUseCase := NewOrderUseCase(orderRepo, nil, nil, shipmentRepo, NewRefundUseCase(refundRepo, nil, nil))
+
+	testData := []struct {
+		name           string
+		orderID        int
+		buildStub      func(orderRepo mockRepo.MockOrderRepository, shipmentRepo mockRepo.MockShipmentRepository, refundRepo mockRepo.MockRefundRepository)
+		expectedOutput model.OrderDetails
+		expectedError  error
+	}{
+		{
+			name:    "order without return",
+			orderID: 4,
+			buildStub: func(orderRepo mockRepo.MockOrderRepository, shipmentRepo mockRepo.MockShipmentRepository, refundRepo mockRepo.MockRefundRepository) {
+				orderRepo.EXPECT().ViewOrderById(gomock.Any(), 1, 4).Times(1).
+					Return(domain.Order{ID: 4, UserID: 1}, nil)
+				shipmentRepo.EXPECT().ViewOrderShipments(gomock.Any(), 4).Times(1).
+					Return(nil, nil)
+				orderRepo.EXPECT().FindReturnByOrderID(gomock.Any(), 4).Times(1).
+					Return(domain.Return{}, nil)
+				refundRepo.EXPECT().ViewOrderRefunds(gomock.Any(), 4).Times(1).
+					Return(nil, nil)
+			},
+			expectedOutput: model.OrderDetails{Order: domain.Order{ID: 4, UserID: 1}},
+			expectedError:  nil,
+		},
+		{
+			name:    "order with return picked up",
+			orderID: 5,
+			buildStub: func(orderRepo mockRepo.MockOrderRepository, shipmentRepo mockRepo.MockShipmentRepository, refundRepo mockRepo.MockRefundRepository) {
+				orderRepo.EXPECT().ViewOrderById(gomock.Any(), 1, 5).Times(1).
+					Return(domain.Order{ID: 5, UserID: 1, OrderStatusID: 5}, nil)
+				shipmentRepo.EXPECT().ViewOrderShipments(gomock.Any(), 5).Times(1).
+					Return([]model.ShipmentDetails{{Shipment: domain.Shipment{ID: 3, OrderID: 5, Status: domain.ShipmentDelivered}, CarrierName: "Delhivery"}}, nil)
+				orderRepo.EXPECT().FindReturnByOrderID(gomock.Any(), 5).Times(1).
+					Return(domain.Return{ID: 2, OrderID: 5, Status: domain.ReturnPickedUp}, nil)
+				refundRepo.EXPECT().ViewOrderRefunds(gomock.Any(), 5).Times(1).
+					Return([]domain.Refund{{ID: 7, OrderID: 5, ReturnID: 2, Amount: 54000, Status: domain.RefundInitiated}}, nil)
+			},
+			expectedOutput: model.OrderDetails{
+				Order:     domain.Order{ID: 5, UserID: 1, OrderStatusID: 5},
+				Shipments: []model.ShipmentDetails{{Shipment: domain.Shipment{ID: 3, OrderID: 5, Status: domain.ShipmentDelivered}, CarrierName: "Delhivery"}},
+				Return:    &domain.Return{ID: 2, OrderID: 5, Status: domain.ReturnPickedUp},
+				Refunds:   []domain.Refund{{ID: 7, OrderID: 5, ReturnID: 2, Amount: 54000, Status: domain.RefundInitiated}},
+			},
+			expectedError: nil,
+		},
+		{
+			name:    "order of another user",
+			orderID: 6,
+			buildStub: func(orderRepo mockRepo.MockOrderRepository, shipmentRepo mockRepo.MockShipmentRepository, refundRepo mockRepo.MockRefundRepository) {
+				orderRepo.EXPECT().ViewOrderById(gomock.Any(), 1, 6).Times(1).
+					Return(domain.Order{}, errors.New("no order found"))
+			},
+			expectedOutput: model.OrderDetails{},
+			expectedError:  errors.New("no order found"),
+		},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStub(*orderRepo, *shipmentRepo, *refundRepo)
+			actualOrder, err := orderUseCase.ViewOrderByID(context.TODO(), tt.orderID, 1)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, actualOrder)
+		})
+	}
+}
+
+func TestRejectReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	orderRepo := mockRepo.NewMockOrderRepository(ctrl)
+
+	orderUseCase := NewOrderUseCase(orderRepo, nil, nil, nil, nil)
+
+	testData := []struct {
+		name           string
+		input          model.ReturnDecision
+		buildStub      func(orderRepo mockRepo.MockOrderRepository)
+		expectedOutput domain.Return
+		expectedError  error
+	}{
+		{
+			name:  "rejected with note",
+			input: model.ReturnDecision{Note: "product is physically damaged"},
+			buildStub: func(orderRepo mockRepo.MockOrderRepository) {
+				orderRepo.EXPECT().RejectReturn(gomock.Any(), 2, "product is physically damaged").Times(1).
+					Return(domain.Return{ID: 2, Status: domain.ReturnRejected, AdminNote: "product is physically damaged"}, nil)
+			},
+			expectedOutput: domain.Return{ID: 2, Status: domain.ReturnRejected, AdminNote: "product is physically damaged"},
+			expectedError:  nil,
+		},
+		{
+			name:           "rejected without note",
+			input:          model.ReturnDecision{},
+			buildStub:      func(orderRepo mockRepo.MockOrderRepository) {},
+			expectedOutput: domain.Return{},
+			expectedError:  errors.New("note is required to reject a return"),
+		},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStub(*orderRepo)
+			actualReturn, err := orderUseCase.RejectReturn(context.TODO(), 2, tt.input)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, actualReturn)
+		})
+	}
+}
+
+func TestUpdateOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	orderRepo := mockRepo.NewMockOrderRepository(ctrl)
+
+	orderUseCase := NewOrderUseCase(orderRepo, nil, nil, nil, nil)
+
+	testData := []struct {
+		name           string
+		input          model.UpdateOrder
+		buildStub      func(orderRepo mockRepo.MockOrderRepository)
+		expectedOutput domain.Order
+		expectedError  error
+	}{
+		{
+			name:  "pending order delivered and completed",
+			input: model.UpdateOrder{OrderID: 4, OrderStatusID: 4, DeliveryStatusID: 1},
+			buildStub: func(orderRepo mockRepo.MockOrderRepository) {
+				orderRepo.EXPECT().FindOrderByID(gomock.Any(), 4).Times(1).
+					Return(domain.Order{ID: 4, OrderStatusID: 1, DeliveryStatusID: 2}, nil)
+				orderRepo.EXPECT().UpdateOrder(gomock.Any(), domain.Order{ID: 4, OrderStatusID: 1, DeliveryStatusID: 2}, model.UpdateOrder{OrderID: 4, OrderStatusID: 4, DeliveryStatusID: 1}).Times(1).
+					Return(domain.Order{ID: 4, OrderStatusID: 4, DeliveryStatusID: 1}, domain.Refund{}, nil)
+			},
+			expectedOutput: domain.Order{ID: 4, OrderStatusID: 4, DeliveryStatusID: 1},
+			expectedError:  nil,
+		},
+		{
+			name:  "delivery status kept when not sent",
+			input: model.UpdateOrder{OrderID: 4, OrderStatusID: 3},
+			buildStub: func(orderRepo mockRepo.MockOrderRepository) {
+				orderRepo.EXPECT().FindOrderByID(gomock.Any(), 4).Times(1).
+					Return(domain.Order{ID: 4, OrderStatusID: 1, DeliveryStatusID: 2}, nil)
+				orderRepo.EXPECT().UpdateOrder(gomock.Any(), domain.Order{ID: 4, OrderStatusID: 1, DeliveryStatusID: 2}, model.UpdateOrder{OrderID: 4, OrderStatusID: 3, DeliveryStatusID: 2}).Times(1).
+					Return(domain.Order{ID: 4, OrderStatusID: 3, DeliveryStatusID: 2}, domain.Refund{}, nil)
+			},
+			expectedOutput: domain.Order{ID: 4, OrderStatusID: 3, DeliveryStatusID: 2},
+			expectedError:  nil,
+		},
+		{
+			name:  "cancelled order moved back to completed",
+			input: model.UpdateOrder{OrderID: 5, OrderStatusID: 4, DeliveryStatusID: 1},
+			buildStub: func(orderRepo mockRepo.MockOrderRepository) {
+				orderRepo.EXPECT().FindOrderByID(gomock.Any(), 5).Times(1).
+					Return(domain.Order{ID: 5, OrderStatusID: 3, DeliveryStatusID: 2}, nil)
+			},
+			expectedOutput: domain.Order{},
+			expectedError:  &domain.OrderStatusError{Reason: "cannot change order status from cancelled by admin to completed"},
+		},
+		{
+			name:  "order completed before delivery",
+			input: model.UpdateOrder{OrderID: 4, OrderStatusID: 4},
+			buildStub: func(orderRepo mockRepo.MockOrderRepository) {
+				orderRepo.EXPECT().FindOrderByID(gomock.Any(), 4).Times(1).
+					Return(domain.Order{ID: 4, OrderStatusID: 1, DeliveryStatusID: 2}, nil)
+			},
+			expectedOutput: domain.Order{},
+			expectedError:  &domain.OrderStatusError{Reason: "order cannot be completed before it is delivered"},
+		},
+		{
+			name:  "admin requesting return",
+			input: model.UpdateOrder{OrderID: 6, OrderStatusID: 5},
+			buildStub: func(orderRepo mockRepo.MockOrderRepository) {
+				orderRepo.EXPECT().FindOrderByID(gomock.Any(), 6).Times(1).
+					Return(domain.Order{ID: 6, OrderStatusID: 4, DeliveryStatusID: 1}, nil)
+			},
+			expectedOutput: domain.Order{},
+			expectedError:  &domain.OrderStatusError{Reason: "order status return requested can only be set by the user"},
+		},
+		{
+			name:  "return request moved back to completed",
+			input: model.UpdateOrder{OrderID: 6, OrderStatusID: 4},
+			buildStub: func(orderRepo mockRepo.MockOrderRepository) {
+				orderRepo.EXPECT().FindOrderByID(gomock.Any(), 6).Times(1).
+					Return(domain.Order{ID: 6, OrderStatusID: 5, DeliveryStatusID: 1}, nil)
+			},
+			expectedOutput: domain.Order{},
+			expectedError:  &domain.OrderStatusError{Reason: "cannot change order status from return requested to completed"},
+		},
+		{
+			name:  "no order found",
+			input: model.UpdateOrder{OrderID: 9, OrderStatusID: 3},
+			buildStub: func(orderRepo mockRepo.MockOrderRepository) {
+				orderRepo.EXPECT().FindOrderByID(gomock.Any(), 9).Times(1).
+					Return(domain.Order{}, nil)
+			},
+			expectedOutput: domain.Order{},
+			expectedError:  errors.New("no order found"),
+		},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStub(*orderRepo)
+			actualOrder, err := orderUseCase.UpdateOrder(context.TODO(), tt.input)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, actualOrder)
+		})
+	}
+}
+
+func TestCancelOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	orderRepo := mockRepo.NewMockOrderRepository(ctrl)
+
+	orderUseCase := NewOrderUseCase(orderRepo, nil, nil, nil, nil)
+
+	testData := []struct {
+		name           string
+		orderID        int
+		buildStub      func(orderRepo mockRepo.MockOrderRepository)
+		expectedOutput domain.Order
+		expectedError  error
+	}{
+		{
+			name:    "pending order",
+			orderID: 4,
+			buildStub: func(orderRepo mockRepo.MockOrderRepository) {
+				orderRepo.EXPECT().ViewOrderById(gomock.Any(), 1, 4).Times(1).
+					Return(domain.Order{ID: 4, OrderStatusID: 1, DeliveryStatusID: 2}, nil)
+				orderRepo.EXPECT().CancelOrder(gomock.Any(), 1, 4).Times(1).
+					Return(domain.Order{ID: 4, OrderStatusID: 2, DeliveryStatusID: 2}, domain.Refund{}, nil)
+			},
+			expectedOutput: domain.Order{ID: 4, OrderStatusID: 2, DeliveryStatusID: 2},
+			expectedError:  nil,
+		},
+		{
+			name:    "already cancelled order",
+			orderID: 5,
+			buildStub: func(orderRepo mockRepo.MockOrderRepository) {
+				orderRepo.EXPECT().ViewOrderById(gomock.Any(), 1, 5).Times(1).
+					Return(domain.Order{ID: 5, OrderStatusID: 2, DeliveryStatusID: 2}, nil)
+			},
+			expectedOutput: domain.Order{},
+			expectedError:  &domain.OrderStatusError{Reason: "order already cancelled"},
+		},
+		{
+			name:    "completed order",
+			orderID: 6,
+			buildStub: func(orderRepo mockRepo.MockOrderRepository) {
+				orderRepo.EXPECT().ViewOrderById(gomock.Any(), 1, 6).Times(1).
+					Return(domain.Order{ID: 6, OrderStatusID: 4, DeliveryStatusID: 1}, nil)
+			},
+			expectedOutput: domain.Order{},
+			expectedError:  &domain.OrderStatusError{Reason: "cannot change order status from completed to cancelled by user"},
+		},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStub(*orderRepo)
+			actualOrder, err := orderUseCase.CancelOrder(context.TODO(), tt.orderID, 1)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, actualOrder)
+		})
+	}
+}
+
+func TestReturnRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	orderRepo := mockRepo.NewMockOrderRepository(ctrl)
+
+	orderUseCase := NewOrderUseCase(orderRepo, nil, nil, nil, nil)
+	deliveredAt := time.Now().Add(-time.Hour * 24 * 3)
+
+	testData := []struct {
+		name           string
+		buildStub      func(orderRepo mockRepo.MockOrderRepository)
+		expectedOutput domain.Order
+		expectedError  error
+	}{
+		{
+			name: "delivered order",
+			buildStub: func(orderRepo mockRepo.MockOrderRepository) {
+				orderRepo.EXPECT().ViewOrderById(gomock.Any(), 1, 4).Times(1).
+					Return(domain.Order{ID: 4, OrderStatusID: domain.OrderCompleted, DeliveryStatusID: domain.DeliveryDelivered, DeliveryUpdatedAt: deliveredAt}, nil)
+				orderRepo.EXPECT().ReturnRequest(gomock.Any(), model.ReturnRequest{OrderID: 4, Reason: "screen flickers"}).Times(1).
+					Return(domain.Order{ID: 4, OrderStatusID: domain.OrderReturnRequested, DeliveryStatusID: domain.DeliveryDelivered}, nil)
+			},
+			expectedOutput: domain.Order{ID: 4, OrderStatusID: domain.OrderReturnRequested, DeliveryStatusID: domain.DeliveryDelivered},
+			expectedError:  nil,
+		},
+		{
+			name: "delivered more than 15 days ago",
+			buildStub: func(orderRepo mockRepo.MockOrderRepository) {
+				orderRepo.EXPECT().ViewOrderById(gomock.Any(), 1, 4).Times(1).
+					Return(domain.Order{ID: 4, OrderStatusID: domain.OrderCompleted, DeliveryStatusID: domain.DeliveryDelivered, DeliveryUpdatedAt: time.Now().Add(-time.Hour * 24 * 20)}, nil)
+			},
+			expectedOutput: domain.Order{},
+			expectedError:  errors.New("failed to place return request as it is more than 15 days"),
+		},
+		{
+			name: "order not delivered yet",
+			buildStub: func(orderRepo mockRepo.MockOrderRepository) {
+				orderRepo.EXPECT().ViewOrderById(gomock.Any(), 1, 4).Times(1).
+					Return(domain.Order{ID: 4, OrderStatusID: domain.OrderPending, DeliveryStatusID: domain.DeliveryPending, DeliveryUpdatedAt: deliveredAt}, nil)
+			},
+			expectedOutput: domain.Order{},
+			expectedError:  errors.New("cannot return as order status is pending and delivery status is pending"),
+		},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStub(*orderRepo)
+			actualOrder, err := orderUseCase.ReturnRequest(context.TODO(), 1, model.ReturnRequest{OrderID: 4, Reason: "screen flickers"})
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, actualOrder)
+		})
+	}
+}
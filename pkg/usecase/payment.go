@@ -2,77 +2,127 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/gateway"
 	interfaces "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/interface"
 	services "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/usecase/interface"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
-	"github.com/razorpay/razorpay-go"
-)
-
-const (
-	razorpayID     = "rzp_test_W8zjL7kK4HLV61"
-	razorpaySecret = "D5LlGN9gnynqZRacjS6qNB86"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/webhook"
+	"math"
+	"strconv"
+	"time"
 )
 
 type paymentUseCase struct {
-	paymentRepo interfaces.PaymentRepository
-	orderRepo   interfaces.OrderRepository
+	paymentRepo    interfaces.PaymentRepository
+	orderRepo      interfaces.OrderRepository
+	paymentGateway gateway.PaymentGateway
+	refunds        services.RefundUseCase
 }
 
-func NewPaymentUseCase(orderRepo interfaces.OrderRepository, paymentRepo interfaces.PaymentRepository) services.PaymentUseCases {
+func NewPaymentUseCase(orderRepo interfaces.OrderRepository, paymentRepo interfaces.PaymentRepository, paymentGateway gateway.PaymentGateway, refunds services.RefundUseCase) services.PaymentUseCases {
 	return &paymentUseCase{
-		paymentRepo: paymentRepo,
-		orderRepo:   orderRepo,
+		paymentRepo:    paymentRepo,
+		orderRepo:      orderRepo,
+		paymentGateway: paymentGateway,
+		refunds:        refunds,
 	}
 }
 
-func (cr *paymentUseCase) CreateRazorpayPayment(ctx context.Context, userID, orderID int) (domain.Order, string, error) {
-	//check payment status. if already paid, no need to proceed with payment. If not paid yet, proceed with transaction.
-	paymentDetails, err := cr.paymentRepo.ViewPaymentDetails(ctx, orderID)
-	if paymentDetails.PaymentStatusID == 2 {
-		return domain.Order{}, "", fmt.Errorf("payment already completed")
-	}
-	//fetch order details from the db
+// CreateRazorpayPayment creates the gateway order the user pays for an order with. The id of the gateway order is stored,
+// so that the checkout can be verified against it, and checking out the same order again pays the same gateway order.
+func (cr *paymentUseCase) CreateRazorpayPayment(ctx context.Context, userID, orderID int) (model.PaymentCheckout, error) {
+	//fetch the order first, so that only the user who placed it can pay for it
 	order, err := cr.orderRepo.ViewOrderById(ctx, userID, orderID)
-	if order.ID == 0 {
-		return domain.Order{}, "", fmt.Errorf("no such order found")
+	if err != nil {
+		return model.PaymentCheckout{}, err
 	}
-	client := razorpay.NewClient(razorpayID, razorpaySecret)
-
-	data := map[string]interface{}{
-		"amount":   order.OrderTotal * 100,
-		"currency": "INR",
-		"receipt":  "test_receipt_id",
+	if order.ID == 0 {
+		return model.PaymentCheckout{}, fmt.Errorf("no such order found")
 	}
 
-	body, err := client.Order.Create(data, nil)
+	//only a pending order whose payment is pending or failed can be paid for
+	paymentDetails, err := cr.paymentRepo.ViewPaymentDetails(ctx, orderID)
 	if err != nil {
-		return domain.Order{}, "", err
+		return model.PaymentCheckout{}, err
+	}
+	if paymentDetails.PaymentStatusID == domain.PaymentCompleted {
+		return model.PaymentCheckout{}, fmt.Errorf("payment already completed")
+	}
+	if order.OrderStatusID != domain.OrderPending {
+		return model.PaymentCheckout{}, fmt.Errorf("order is %v, only a pending order can be paid for", order.OrderStatusID.Name())
+	}
+	if paymentDetails.PaymentStatusID != domain.PaymentPending && paymentDetails.PaymentStatusID != domain.PaymentFailed {
+		return model.PaymentCheckout{}, fmt.Errorf("payment is %v, it can no longer be paid", paymentDetails.PaymentStatusID.Name())
 	}
 
-	value := body["id"]
-	razorpayID := value.(string)
-	return order, razorpayID, err
+	gatewayOrderID := paymentDetails.GatewayOrderID
+	if gatewayOrderID == "" {
+		//webhooks carry the notes of the gateway order, which is how they are matched to the order
+		notes := map[string]string{"order_id": strconv.Itoa(int(order.ID))}
+		gatewayOrderID, err = cr.paymentGateway.CreateOrder(ctx, fmt.Sprintf("order_%d", order.ID), paymentDetails.AmountDue(), notes)
+		if err != nil {
+			return model.PaymentCheckout{}, err
+		}
+		gatewayOrderID, err = cr.paymentRepo.SetGatewayOrderID(ctx, orderID, gatewayOrderID)
+		if err != nil {
+			return model.PaymentCheckout{}, err
+		}
+	}
+	return model.PaymentCheckout{Order: order, Amount: paymentDetails.AmountDue(), GatewayOrderID: gatewayOrderID, KeyID: cr.paymentGateway.KeyID()}, nil
 }
 
+// UpdatePaymentDetails completes the payment of an order once the checkout returns. The checkout is trusted only if it
+// is for the gateway order created for the order and is signed by the gateway. The payment is then checked at the gateway,
+// and captured if it is only authorized. A payment for an order cancelled before it was paid for is refunded.
 func (cr *paymentUseCase) UpdatePaymentDetails(ctx context.Context, paymentVerifier model.PaymentVerification) error {
-	//	fetch payment details
-
-	paymentDetails, err := cr.paymentRepo.ViewPaymentDetails(ctx, paymentVerifier.OrderID)
+	order, err := cr.orderRepo.ViewOrderById(ctx, paymentVerifier.UserID, paymentVerifier.OrderID)
 	if err != nil {
 		return err
 	}
 
+	paymentDetails, err := cr.paymentRepo.ViewPaymentDetails(ctx, int(order.ID))
+	if err != nil {
+		return err
+	}
 	if paymentDetails.ID == 0 {
 		return fmt.Errorf("no order found")
 	}
 
-	if paymentDetails.OrderTotal != paymentVerifier.Total {
+	if paymentDetails.GatewayOrderID == "" || paymentDetails.GatewayOrderID != paymentVerifier.RazorpayOrderID {
+		return fmt.Errorf("payment is not for this order")
+	}
+	if !cr.paymentGateway.VerifyPayment(paymentVerifier.RazorpayOrderID, paymentVerifier.RazorpayPaymentID, paymentVerifier.RazorpaySignature) {
+		return domain.ErrInvalidSignature
+	}
+	if paymentDetails.PaymentStatusID == domain.PaymentCompleted && paymentDetails.PaymentRef == paymentVerifier.RazorpayPaymentID {
+		//already completed by the webhook
+		return nil
+	}
+
+	payment, err := cr.paymentGateway.FetchPayment(ctx, paymentVerifier.RazorpayPaymentID)
+	if err != nil {
+		return err
+	}
+	if payment.OrderID != paymentDetails.GatewayOrderID {
+		return fmt.Errorf("payment is not for this order")
+	}
+	if math.Round(payment.Amount*100) != math.Round(paymentDetails.AmountDue()*100) {
 		return fmt.Errorf("payment amount and order amount does not match")
 	}
+	switch payment.Status {
+	case gateway.PaymentAuthorized:
+		if err := cr.paymentGateway.CapturePayment(ctx, payment.ID, paymentDetails.AmountDue()); err != nil {
+			return err
+		}
+	case gateway.PaymentCaptured:
+	default:
+		return fmt.Errorf("payment is %v", payment.Status)
+	}
 
-	updatedPayment, err := cr.paymentRepo.UpdatePaymentDetails(ctx, paymentVerifier.OrderID, paymentVerifier.PaymentRef)
+	updatedPayment, refund, err := cr.paymentRepo.UpdatePaymentDetails(ctx, paymentVerifier.OrderID, paymentVerifier.RazorpayPaymentID)
 	if err != nil {
 		return err
 	}
@@ -80,5 +130,186 @@ func (cr *paymentUseCase) UpdatePaymentDetails(ctx context.Context, paymentVerif
 	if updatedPayment.ID == 0 {
 		return fmt.Errorf("failed to update payment details")
 	}
+	if refund.ID != 0 {
+		//the order was cancelled before the payment reached the store
+		if _, err := cr.refunds.IssueRefund(ctx, refund); err != nil {
+			return err
+		}
+		return fmt.Errorf("order was cancelled before it was paid for, the payment will be refunded")
+	}
 	return nil
 }
+
+// HandleRazorpayWebhook applies a webhook event sent by Razorpay, after checking that it is signed by the gateway.
+// Events other than captured and failed payments and processed and failed refunds are ignored.
+func (cr *paymentUseCase) HandleRazorpayWebhook(ctx context.Context, payload []byte, signature, eventID string) error {
+	if !cr.paymentGateway.VerifyWebhook(payload, signature) {
+		return domain.ErrInvalidSignature
+	}
+	if eventID == "" {
+		return fmt.Errorf("missing event id")
+	}
+	var event webhook.RazorpayEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return err
+	}
+
+	switch event.Event {
+	case webhook.PaymentCaptured:
+		payment := event.Payload.Payment.Entity
+		orderID, err := strconv.Atoi(payment.Notes["order_id"])
+		if err != nil {
+			return fmt.Errorf("payment %v is not for an order of the store", payment.ID)
+		}
+		paymentDetails, err := cr.paymentRepo.ViewPaymentDetails(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if paymentDetails.ID == 0 {
+			return fmt.Errorf("no order found")
+		}
+		//the notes only name the order, the payment has to be for the gateway order created for it
+		if paymentDetails.GatewayOrderID == "" || payment.OrderID != paymentDetails.GatewayOrderID {
+			return fmt.Errorf("payment %v is not for this order", payment.ID)
+		}
+		if int64(math.Round(paymentDetails.AmountDue()*100)) != payment.Amount {
+			return fmt.Errorf("payment amount and order amount does not match")
+		}
+		refund, err := cr.paymentRepo.CapturePayment(ctx, eventID, orderID, payment.ID)
+		if err != nil {
+			return err
+		}
+		//a payment captured after the order was cancelled is refunded
+		_, err = cr.refunds.IssueRefund(ctx, refund)
+		return err
+
+	case webhook.PaymentFailed:
+		payment := event.Payload.Payment.Entity
+		orderID, err := strconv.Atoi(payment.Notes["order_id"])
+		if err != nil {
+			return fmt.Errorf("payment %v is not for an order of the store", payment.ID)
+		}
+		return cr.paymentRepo.FailPayment(ctx, eventID, orderID, payment.ID)
+
+	case webhook.RefundProcessed:
+		refund := event.Payload.Refund.Entity
+		return cr.paymentRepo.ProcessRefund(ctx, eventID, refund.PaymentID, refund.ID, float64(refund.Amount)/100)
+
+	case webhook.RefundFailed:
+		refund := event.Payload.Refund.Entity
+		return cr.paymentRepo.FailRefund(ctx, eventID, refund.PaymentID, refund.ID)
+	}
+	return nil
+}
+
+// ReconcilePayments settles online payments that are still pending for orders placed more than olderThan ago, in case the
+// checkout callback and the webhooks never reached the store. The gateway is asked for the attempts made to pay every such
+// order. An order paid at the gateway is marked paid, capturing the payment first if it is only authorized. An order placed
+// more than expireAfter ago that was never paid for is expired, which cancels it and releases its stock. Otherwise an order
+// whose attempts all failed is marked failed, and the user can still pay for it.
+// A payment that cannot be reconciled is reported with its error, and the rest are still reconciled.
+func (cr *paymentUseCase) ReconcilePayments(ctx context.Context, olderThan, expireAfter time.Duration) (model.PaymentReconciliation, error) {
+	if olderThan <= 0 || expireAfter < olderThan {
+		return model.PaymentReconciliation{}, fmt.Errorf("payments should expire after they are reconciled")
+	}
+	report := model.PaymentReconciliation{StartedAt: time.Now()}
+	stalePayments, err := cr.paymentRepo.FindStalePayments(ctx, report.StartedAt.Add(-olderThan))
+	if err != nil {
+		return model.PaymentReconciliation{}, err
+	}
+
+	for _, paymentDetails := range stalePayments {
+		report.Checked++
+		reconciled := model.ReconciledPayment{
+			OrderID:        paymentDetails.OrderID,
+			GatewayOrderID: paymentDetails.GatewayOrderID,
+			From:           paymentDetails.PaymentStatusID.Name(),
+		}
+		status, err := cr.reconcilePayment(ctx, paymentDetails, report.StartedAt.Add(-expireAfter), &reconciled)
+		if err != nil {
+			report.Errors++
+			reconciled.To = reconciled.From
+			reconciled.Error = err.Error()
+			report.Payments = append(report.Payments, reconciled)
+			continue
+		}
+		if status == paymentDetails.PaymentStatusID {
+			continue
+		}
+		switch status {
+		case domain.PaymentCompleted:
+			report.Paid++
+		case domain.PaymentFailed:
+			report.Failed++
+		case domain.PaymentExpired:
+			report.Expired++
+		}
+		reconciled.To = status.Name()
+		report.Payments = append(report.Payments, reconciled)
+	}
+	return report, nil
+}
+
+// reconcilePayment settles a stale payment as found at the gateway, and returns the status it is left in
+func (cr *paymentUseCase) reconcilePayment(ctx context.Context, paymentDetails domain.PaymentDetails, expireBefore time.Time, reconciled *model.ReconciledPayment) (domain.PaymentStatusID, error) {
+	var attempts []gateway.Payment
+	if paymentDetails.GatewayOrderID != "" {
+		var err error
+		attempts, err = cr.paymentGateway.FetchOrderPayments(ctx, paymentDetails.GatewayOrderID)
+		if err != nil {
+			return paymentDetails.PaymentStatusID, err
+		}
+	}
+
+	attemptFailed := false
+	for _, payment := range attempts {
+		switch payment.Status {
+		case gateway.PaymentAuthorized, gateway.PaymentCaptured:
+			reconciled.PaymentRef = payment.ID
+			if math.Round(payment.Amount*100) != math.Round(paymentDetails.AmountDue()*100) {
+				return paymentDetails.PaymentStatusID, fmt.Errorf("payment amount and order amount does not match")
+			}
+			if payment.Status == gateway.PaymentAuthorized {
+				if err := cr.paymentGateway.CapturePayment(ctx, payment.ID, paymentDetails.AmountDue()); err != nil {
+					return paymentDetails.PaymentStatusID, err
+				}
+			}
+			_, refund, err := cr.paymentRepo.UpdatePaymentDetails(ctx, int(paymentDetails.OrderID), payment.ID)
+			if err != nil {
+				return paymentDetails.PaymentStatusID, err
+			}
+			//the order was cancelled meanwhile, the payment is refunded
+			if _, err := cr.refunds.IssueRefund(ctx, refund); err != nil {
+				return paymentDetails.PaymentStatusID, err
+			}
+			return domain.PaymentCompleted, nil
+		case gateway.PaymentFailed:
+			attemptFailed = true
+		}
+	}
+
+	order, err := cr.orderRepo.FindOrderByID(ctx, int(paymentDetails.OrderID))
+	if err != nil {
+		return paymentDetails.PaymentStatusID, err
+	}
+	if order.OrderDate.Before(expireBefore) {
+		_, refund, err := cr.paymentRepo.ExpirePayment(ctx, int(paymentDetails.OrderID))
+		if err != nil {
+			return paymentDetails.PaymentStatusID, err
+		}
+		reconciled.WalletRefund = refund.Amount
+		return domain.PaymentExpired, nil
+	}
+	if attemptFailed && paymentDetails.PaymentStatusID == domain.PaymentPending {
+		failedPayment, err := cr.paymentRepo.MarkPaymentFailed(ctx, int(paymentDetails.OrderID))
+		if err != nil {
+			return paymentDetails.PaymentStatusID, err
+		}
+		if failedPayment.ID == 0 {
+			//paid or failed meanwhile, it is reconciled again on the next run
+			return paymentDetails.PaymentStatusID, nil
+		}
+		return domain.PaymentFailed, nil
+	}
+	return paymentDetails.PaymentStatusID, nil
+}
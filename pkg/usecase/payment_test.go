@@ -0,0 +1,374 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/gateway"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/mockRepo"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestHandleRazorpayWebhook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	paymentRepo := mockRepo.NewMockPaymentRepository(ctrl)
+	refundRepo := mockRepo.NewMockRefundRepository(ctrl)
+
+	fakeGateway := gateway.NewFakeGateway("webhook_secret")
+	paymentUseCase := NewPaymentUseCase(nil, paymentRepo, fakeGateway, NewRefundUseCase(refundRepo, paymentRepo, fakeGateway))
+
+	captured := `{"event":"payment.captured","payload":{"payment":{"entity":{"id":"pay_1","order_id":"order_A","amount":5400050,"status":"captured","notes":{"order_id":"4"}}}}}`
+	//a payment of another gateway order, with notes naming order 4
+	otherOrderCapture := `{"event":"payment.captured","payload":{"payment":{"entity":{"id":"pay_2","order_id":"order_B","amount":5400050,"status":"captured","notes":{"order_id":"4"}}}}}`
+
+	//order 5 is paid at the gateway after its payment expired and it was cancelled
+	lateOrderID, _ := fakeGateway.CreateOrder(context.TODO(), "order_5", 50000, nil)
+	latePaymentID, _, _ := fakeGateway.Pay(lateOrderID)
+	_ = fakeGateway.CapturePayment(context.TODO(), latePaymentID, 50000)
+	lateCapture := fmt.Sprintf(`{"event":"payment.captured","payload":{"payment":{"entity":{"id":"%v","order_id":"%v","amount":5000000,"status":"captured","notes":{"order_id":"5"}}}}}`,
+		latePaymentID, lateOrderID)
+
+	testData := []struct {
+		name          string
+		payload       string
+		signature     string
+		eventID       string
+		buildStub     func(paymentRepo mockRepo.MockPaymentRepository)
+		expectedError error
+	}{
+		{
+			name:      "payment captured",
+			payload:   captured,
+			signature: fakeGateway.SignWebhook([]byte(captured)),
+			eventID:   "evt_1",
+			buildStub: func(paymentRepo mockRepo.MockPaymentRepository) {
+				paymentRepo.EXPECT().ViewPaymentDetails(gomock.Any(), 4).Times(1).
+					Return(domain.PaymentDetails{ID: 2, OrderID: 4, OrderTotal: 54000.5, GatewayOrderID: "order_A"}, nil)
+				paymentRepo.EXPECT().CapturePayment(gomock.Any(), "evt_1", 4, "pay_1").Times(1).
+					Return(domain.Refund{}, nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:      "payment captured after the order was cancelled",
+			payload:   lateCapture,
+			signature: fakeGateway.SignWebhook([]byte(lateCapture)),
+			eventID:   "evt_5",
+			buildStub: func(paymentRepo mockRepo.MockPaymentRepository) {
+				paymentRepo.EXPECT().ViewPaymentDetails(gomock.Any(), 5).Times(1).
+					Return(domain.PaymentDetails{ID: 3, OrderID: 5, OrderTotal: 54000, WalletAmount: 4000, PaymentStatusID: domain.PaymentExpired,
+						GatewayOrderID: lateOrderID}, nil)
+				//the capture is recorded and what was paid through the gateway is refunded
+				paymentRepo.EXPECT().CapturePayment(gomock.Any(), "evt_5", 5, latePaymentID).Times(1).
+					Return(domain.Refund{ID: 12, OrderID: 5, PaymentDetailsID: 3, Amount: 50000, Destination: domain.RefundToGateway, Status: domain.RefundInitiated}, nil)
+				paymentRepo.EXPECT().ViewPaymentDetails(gomock.Any(), 5).Times(1).
+					Return(domain.PaymentDetails{ID: 3, OrderID: 5, OrderTotal: 54000, WalletAmount: 4000, PaymentMethodID: domain.PaymentOnline,
+						PaymentStatusID: domain.PaymentCompleted, PaymentRef: latePaymentID}, nil)
+				refundRepo.EXPECT().SetRefundRef(gomock.Any(), uint(12), gomock.Any()).Times(1).
+					Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:          "signed with another secret",
+			payload:       captured,
+			signature:     gateway.NewFakeGateway("another_secret").SignWebhook([]byte(captured)),
+			eventID:       "evt_1",
+			buildStub:     func(paymentRepo mockRepo.MockPaymentRepository) {},
+			expectedError: domain.ErrInvalidSignature,
+		},
+		{
+			name:          "payload changed after signing",
+			payload:       `{"event":"payment.captured","payload":{"payment":{"entity":{"id":"pay_1","amount":100,"notes":{"order_id":"4"}}}}}`,
+			signature:     fakeGateway.SignWebhook([]byte(captured)),
+			eventID:       "evt_1",
+			buildStub:     func(paymentRepo mockRepo.MockPaymentRepository) {},
+			expectedError: domain.ErrInvalidSignature,
+		},
+		{
+			name:      "captured amount differs from order total",
+			payload:   captured,
+			signature: fakeGateway.SignWebhook([]byte(captured)),
+			eventID:   "evt_2",
+			buildStub: func(paymentRepo mockRepo.MockPaymentRepository) {
+				paymentRepo.EXPECT().ViewPaymentDetails(gomock.Any(), 4).Times(1).
+					Return(domain.PaymentDetails{ID: 2, OrderID: 4, OrderTotal: 60000, GatewayOrderID: "order_A"}, nil)
+			},
+			expectedError: errors.New("payment amount and order amount does not match"),
+		},
+		{
+			name:      "captured for another gateway order",
+			payload:   otherOrderCapture,
+			signature: fakeGateway.SignWebhook([]byte(otherOrderCapture)),
+			eventID:   "evt_6",
+			buildStub: func(paymentRepo mockRepo.MockPaymentRepository) {
+				paymentRepo.EXPECT().ViewPaymentDetails(gomock.Any(), 4).Times(1).
+					Return(domain.PaymentDetails{ID: 2, OrderID: 4, OrderTotal: 54000.5, GatewayOrderID: "order_A"}, nil)
+			},
+			expectedError: errors.New("payment pay_2 is not for this order"),
+		},
+		{
+			name:      "refund processed",
+			payload:   `{"event":"refund.processed","payload":{"refund":{"entity":{"id":"rfnd_1","payment_id":"pay_1","amount":5400050}}}}`,
+			signature: fakeGateway.SignWebhook([]byte(`{"event":"refund.processed","payload":{"refund":{"entity":{"id":"rfnd_1","payment_id":"pay_1","amount":5400050}}}}`)),
+			eventID:   "evt_3",
+			buildStub: func(paymentRepo mockRepo.MockPaymentRepository) {
+				paymentRepo.EXPECT().ProcessRefund(gomock.Any(), "evt_3", "pay_1", "rfnd_1", 54000.5).Times(1).
+					Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:          "event that is not handled",
+			payload:       `{"event":"order.paid"}`,
+			signature:     fakeGateway.SignWebhook([]byte(`{"event":"order.paid"}`)),
+			eventID:       "evt_4",
+			buildStub:     func(paymentRepo mockRepo.MockPaymentRepository) {},
+			expectedError: nil,
+		},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStub(*paymentRepo)
+			err := paymentUseCase.HandleRazorpayWebhook(context.TODO(), []byte(tt.payload), tt.signature, tt.eventID)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestCreateRazorpayPayment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	orderRepo := mockRepo.NewMockOrderRepository(ctrl)
+	paymentRepo := mockRepo.NewMockPaymentRepository(ctrl)
+
+	fakeGateway := gateway.NewFakeGateway("key_secret")
+	paymentUseCase := NewPaymentUseCase(orderRepo, paymentRepo, fakeGateway, nil)
+
+	testData := []struct {
+		name          string
+		buildStub     func(orderRepo mockRepo.MockOrderRepository, paymentRepo mockRepo.MockPaymentRepository)
+		expectedError error
+	}{
+		{
+			name: "order of another user",
+			buildStub: func(orderRepo mockRepo.MockOrderRepository, paymentRepo mockRepo.MockPaymentRepository) {
+				orderRepo.EXPECT().ViewOrderById(gomock.Any(), 1, 4).Times(1).
+					Return(domain.Order{}, nil)
+			},
+			expectedError: errors.New("no such order found"),
+		},
+		{
+			name: "failed to fetch order",
+			buildStub: func(orderRepo mockRepo.MockOrderRepository, paymentRepo mockRepo.MockPaymentRepository) {
+				orderRepo.EXPECT().ViewOrderById(gomock.Any(), 1, 4).Times(1).
+					Return(domain.Order{}, errors.New("connection refused"))
+			},
+			expectedError: errors.New("connection refused"),
+		},
+		{
+			name: "cancelled order",
+			buildStub: func(orderRepo mockRepo.MockOrderRepository, paymentRepo mockRepo.MockPaymentRepository) {
+				orderRepo.EXPECT().ViewOrderById(gomock.Any(), 1, 4).Times(1).
+					Return(domain.Order{ID: 4, UserID: 1, OrderStatusID: domain.OrderCancelledByUser}, nil)
+				paymentRepo.EXPECT().ViewPaymentDetails(gomock.Any(), 4).Times(1).
+					Return(domain.PaymentDetails{ID: 2, OrderID: 4, OrderTotal: 54000, PaymentStatusID: domain.PaymentPending}, nil)
+			},
+			expectedError: fmt.Errorf("order is %v, only a pending order can be paid for", domain.OrderCancelledByUser.Name()),
+		},
+		{
+			name: "expired payment",
+			buildStub: func(orderRepo mockRepo.MockOrderRepository, paymentRepo mockRepo.MockPaymentRepository) {
+				orderRepo.EXPECT().ViewOrderById(gomock.Any(), 1, 4).Times(1).
+					Return(domain.Order{ID: 4, UserID: 1, OrderStatusID: domain.OrderPending}, nil)
+				paymentRepo.EXPECT().ViewPaymentDetails(gomock.Any(), 4).Times(1).
+					Return(domain.PaymentDetails{ID: 2, OrderID: 4, OrderTotal: 54000, PaymentStatusID: domain.PaymentExpired}, nil)
+			},
+			expectedError: fmt.Errorf("payment is %v, it can no longer be paid", domain.PaymentExpired.Name()),
+		},
+		{
+			name: "payment failed before",
+			buildStub: func(orderRepo mockRepo.MockOrderRepository, paymentRepo mockRepo.MockPaymentRepository) {
+				orderRepo.EXPECT().ViewOrderById(gomock.Any(), 1, 4).Times(1).
+					Return(domain.Order{ID: 4, UserID: 1, OrderStatusID: domain.OrderPending}, nil)
+				paymentRepo.EXPECT().ViewPaymentDetails(gomock.Any(), 4).Times(1).
+					Return(domain.PaymentDetails{ID: 2, OrderID: 4, OrderTotal: 54000, PaymentStatusID: domain.PaymentFailed, GatewayOrderID: "order_1"}, nil)
+			},
+			expectedError: nil,
+		},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStub(*orderRepo, *paymentRepo)
+			_, err := paymentUseCase.CreateRazorpayPayment(context.TODO(), 1, 4)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestUpdatePaymentDetails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	orderRepo := mockRepo.NewMockOrderRepository(ctrl)
+	paymentRepo := mockRepo.NewMockPaymentRepository(ctrl)
+	refundRepo := mockRepo.NewMockRefundRepository(ctrl)
+
+	fakeGateway := gateway.NewFakeGateway("key_secret")
+	paymentUseCase := NewPaymentUseCase(orderRepo, paymentRepo, fakeGateway, NewRefundUseCase(refundRepo, paymentRepo, fakeGateway))
+
+	//the user pays for a gateway order created for order 4 through the checkout
+	gatewayOrderID, _ := fakeGateway.CreateOrder(context.TODO(), "order_4", 54000, nil)
+	paymentID, signature, _ := fakeGateway.Pay(gatewayOrderID)
+	otherOrderID, _ := fakeGateway.CreateOrder(context.TODO(), "order_5", 54000, nil)
+	otherPaymentID, _, _ := fakeGateway.Pay(otherOrderID)
+
+	testData := []struct {
+		name          string
+		input         model.PaymentVerification
+		buildStub     func(orderRepo mockRepo.MockOrderRepository, paymentRepo mockRepo.MockPaymentRepository)
+		expectedError error
+	}{
+		{
+			name:  "checkout of another gateway order",
+			input: model.PaymentVerification{UserID: 1, OrderID: 4, RazorpayOrderID: otherOrderID, RazorpayPaymentID: paymentID, RazorpaySignature: signature},
+			buildStub: func(orderRepo mockRepo.MockOrderRepository, paymentRepo mockRepo.MockPaymentRepository) {
+				orderRepo.EXPECT().ViewOrderById(gomock.Any(), 1, 4).Times(1).
+					Return(domain.Order{ID: 4, UserID: 1}, nil)
+				paymentRepo.EXPECT().ViewPaymentDetails(gomock.Any(), 4).Times(1).
+					Return(domain.PaymentDetails{ID: 2, OrderID: 4, OrderTotal: 54000, PaymentStatusID: domain.PaymentPending, GatewayOrderID: gatewayOrderID}, nil)
+			},
+			expectedError: errors.New("payment is not for this order"),
+		},
+		{
+			name:  "signature of another payment",
+			input: model.PaymentVerification{UserID: 1, OrderID: 4, RazorpayOrderID: gatewayOrderID, RazorpayPaymentID: otherPaymentID, RazorpaySignature: signature},
+			buildStub: func(orderRepo mockRepo.MockOrderRepository, paymentRepo mockRepo.MockPaymentRepository) {
+				orderRepo.EXPECT().ViewOrderById(gomock.Any(), 1, 4).Times(1).
+					Return(domain.Order{ID: 4, UserID: 1}, nil)
+				paymentRepo.EXPECT().ViewPaymentDetails(gomock.Any(), 4).Times(1).
+					Return(domain.PaymentDetails{ID: 2, OrderID: 4, OrderTotal: 54000, PaymentStatusID: domain.PaymentPending, GatewayOrderID: gatewayOrderID}, nil)
+			},
+			expectedError: domain.ErrInvalidSignature,
+		},
+		{
+			name:  "paid less than the order total",
+			input: model.PaymentVerification{UserID: 1, OrderID: 4, RazorpayOrderID: gatewayOrderID, RazorpayPaymentID: paymentID, RazorpaySignature: signature},
+			buildStub: func(orderRepo mockRepo.MockOrderRepository, paymentRepo mockRepo.MockPaymentRepository) {
+				orderRepo.EXPECT().ViewOrderById(gomock.Any(), 1, 4).Times(1).
+					Return(domain.Order{ID: 4, UserID: 1}, nil)
+				paymentRepo.EXPECT().ViewPaymentDetails(gomock.Any(), 4).Times(1).
+					Return(domain.PaymentDetails{ID: 2, OrderID: 4, OrderTotal: 60000, PaymentStatusID: domain.PaymentPending, GatewayOrderID: gatewayOrderID}, nil)
+			},
+			expectedError: errors.New("payment amount and order amount does not match"),
+		},
+		{
+			name:  "signed checkout",
+			input: model.PaymentVerification{UserID: 1, OrderID: 4, RazorpayOrderID: gatewayOrderID, RazorpayPaymentID: paymentID, RazorpaySignature: signature},
+			buildStub: func(orderRepo mockRepo.MockOrderRepository, paymentRepo mockRepo.MockPaymentRepository) {
+				orderRepo.EXPECT().ViewOrderById(gomock.Any(), 1, 4).Times(1).
+					Return(domain.Order{ID: 4, UserID: 1}, nil)
+				paymentRepo.EXPECT().ViewPaymentDetails(gomock.Any(), 4).Times(1).
+					Return(domain.PaymentDetails{ID: 2, OrderID: 4, OrderTotal: 54000, PaymentStatusID: domain.PaymentPending, GatewayOrderID: gatewayOrderID}, nil)
+				paymentRepo.EXPECT().UpdatePaymentDetails(gomock.Any(), 4, paymentID).Times(1).
+					Return(domain.PaymentDetails{ID: 2, OrderID: 4, PaymentStatusID: domain.PaymentCompleted, PaymentRef: paymentID}, domain.Refund{}, nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:  "checkout returned after the payment expired",
+			input: model.PaymentVerification{UserID: 1, OrderID: 4, RazorpayOrderID: gatewayOrderID, RazorpayPaymentID: paymentID, RazorpaySignature: signature},
+			buildStub: func(orderRepo mockRepo.MockOrderRepository, paymentRepo mockRepo.MockPaymentRepository) {
+				orderRepo.EXPECT().ViewOrderById(gomock.Any(), 1, 4).Times(1).
+					Return(domain.Order{ID: 4, UserID: 1, OrderStatusID: domain.OrderCancelledByAdmin}, nil)
+				paymentRepo.EXPECT().ViewPaymentDetails(gomock.Any(), 4).Times(1).
+					Return(domain.PaymentDetails{ID: 2, OrderID: 4, OrderTotal: 54000, PaymentStatusID: domain.PaymentExpired, GatewayOrderID: gatewayOrderID}, nil)
+				//the payment is recorded and refunded in full through the gateway
+				paymentRepo.EXPECT().UpdatePaymentDetails(gomock.Any(), 4, paymentID).Times(1).
+					Return(domain.PaymentDetails{ID: 2, OrderID: 4, PaymentStatusID: domain.PaymentCompleted, PaymentRef: paymentID},
+						domain.Refund{ID: 12, OrderID: 4, PaymentDetailsID: 2, Amount: 54000, Destination: domain.RefundToGateway, Status: domain.RefundInitiated}, nil)
+				paymentRepo.EXPECT().ViewPaymentDetails(gomock.Any(), 4).Times(1).
+					Return(domain.PaymentDetails{ID: 2, OrderID: 4, OrderTotal: 54000, PaymentMethodID: domain.PaymentOnline,
+						PaymentStatusID: domain.PaymentCompleted, PaymentRef: paymentID}, nil)
+				refundRepo.EXPECT().SetRefundRef(gomock.Any(), uint(12), gomock.Any()).Times(1).
+					Return(nil)
+			},
+			expectedError: errors.New("order was cancelled before it was paid for, the payment will be refunded"),
+		},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStub(*orderRepo, *paymentRepo)
+			err := paymentUseCase.UpdatePaymentDetails(context.TODO(), tt.input)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+
+	//the authorized payment is captured at the gateway, and refunded once it turns out the order was cancelled
+	payment, err := fakeGateway.FetchPayment(context.TODO(), paymentID)
+	assert.NoError(t, err)
+	assert.Equal(t, gateway.PaymentRefunded, payment.Status)
+}
+
+func TestReconcilePayments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	orderRepo := mockRepo.NewMockOrderRepository(ctrl)
+	paymentRepo := mockRepo.NewMockPaymentRepository(ctrl)
+
+	fakeGateway := gateway.NewFakeGateway("key_secret")
+	paymentUseCase := NewPaymentUseCase(orderRepo, paymentRepo, fakeGateway, NewRefundUseCase(nil, paymentRepo, fakeGateway))
+
+	//order 4 was paid at the gateway but the checkout never returned, every attempt to pay for order 5 failed
+	paidOrderID, _ := fakeGateway.CreateOrder(context.TODO(), "order_4", 54000, nil)
+	paymentID, _, _ := fakeGateway.Pay(paidOrderID)
+	failedOrderID, _ := fakeGateway.CreateOrder(context.TODO(), "order_5", 1200, nil)
+	fakeGateway.FailPayment(failedOrderID)
+
+	paymentRepo.EXPECT().FindStalePayments(gomock.Any(), gomock.Any()).Times(1).
+		Return([]domain.PaymentDetails{
+			{ID: 2, OrderID: 4, OrderTotal: 54000, PaymentMethodID: domain.PaymentOnline, PaymentStatusID: domain.PaymentPending, GatewayOrderID: paidOrderID},
+			{ID: 3, OrderID: 5, OrderTotal: 1200, PaymentMethodID: domain.PaymentOnline, PaymentStatusID: domain.PaymentPending, GatewayOrderID: failedOrderID},
+			{ID: 4, OrderID: 6, OrderTotal: 2500, WalletAmount: 500, PaymentMethodID: domain.PaymentOnline, PaymentStatusID: domain.PaymentPending},
+			{ID: 5, OrderID: 7, OrderTotal: 900, PaymentMethodID: domain.PaymentOnline, PaymentStatusID: domain.PaymentFailed, GatewayOrderID: "order_missing"},
+		}, nil)
+
+	//order 4 is captured and marked paid
+	paymentRepo.EXPECT().UpdatePaymentDetails(gomock.Any(), 4, paymentID).Times(1).
+		Return(domain.PaymentDetails{ID: 2, OrderID: 4, PaymentStatusID: domain.PaymentCompleted, PaymentRef: paymentID}, domain.Refund{}, nil)
+
+	//order 5 was placed recently, so it is only marked failed
+	orderRepo.EXPECT().FindOrderByID(gomock.Any(), 5).Times(1).
+		Return(domain.Order{ID: 5, OrderDate: time.Now().Add(-time.Hour)}, nil)
+	paymentRepo.EXPECT().MarkPaymentFailed(gomock.Any(), 5).Times(1).
+		Return(domain.PaymentDetails{ID: 3, OrderID: 5, PaymentStatusID: domain.PaymentFailed}, nil)
+
+	//order 6 was never paid for, it is expired and the part paid from the wallet is credited back
+	orderRepo.EXPECT().FindOrderByID(gomock.Any(), 6).Times(1).
+		Return(domain.Order{ID: 6, OrderDate: time.Now().Add(-48 * time.Hour)}, nil)
+	paymentRepo.EXPECT().ExpirePayment(gomock.Any(), 6).Times(1).
+		Return(domain.Order{ID: 6, OrderStatusID: domain.OrderCancelledByAdmin}, domain.Refund{ID: 9, OrderID: 6, Amount: 500, Destination: domain.RefundToWallet}, nil)
+
+	report, err := paymentUseCase.ReconcilePayments(context.TODO(), 30*time.Minute, 24*time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, report.Checked)
+	assert.Equal(t, 1, report.Paid)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, 1, report.Expired)
+	assert.Equal(t, 1, report.Errors)
+	assert.Equal(t, []model.ReconciledPayment{
+		{OrderID: 4, GatewayOrderID: paidOrderID, PaymentRef: paymentID, From: "pending", To: "completed"},
+		{OrderID: 5, GatewayOrderID: failedOrderID, From: "pending", To: "failed"},
+		{OrderID: 6, From: "pending", To: "expired", WalletRefund: 500},
+		{OrderID: 7, GatewayOrderID: "order_missing", From: "failed", To: "failed", Error: "no gateway order found"},
+	}, report.Payments)
+
+	//the authorized payment is captured at the gateway
+	payment, err := fakeGateway.FetchPayment(context.TODO(), paymentID)
+	assert.NoError(t, err)
+	assert.Equal(t, gateway.PaymentCaptured, payment.Status)
+}
@@ -7,6 +7,7 @@ import (
 	interfaces "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/interface"
 	services "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/usecase/interface"
 	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"time"
 )
 
 type productUseCase struct {
@@ -40,6 +41,9 @@ func (c *productUseCase) FindCategoryByID(ctx context.Context, id int) (domain.P
 }
 
 func (c *productUseCase) UpdateCategory(ctx context.Context, info domain.ProductCategory) (domain.ProductCategory, error) {
+	if info.TaxPercent < 0 || info.TaxPercent > 100 {
+		return domain.ProductCategory{}, fmt.Errorf("tax percent should be between 0 and 100")
+	}
 	updatedInfo, err := c.productRepo.UpdateCategory(ctx, info)
 	return updatedInfo, err
 }
@@ -107,6 +111,9 @@ func (c *productUseCase) DeleteProduct(ctx context.Context, productID int) error
 //Product Item Management
 
 func (c *productUseCase) CreateProductItem(ctx context.Context, newProductItem domain.ProductItem) (domain.ProductItem, error) {
+	if newProductItem.MaxPerOrder < 0 {
+		return domain.ProductItem{}, fmt.Errorf("max per order cannot be negative")
+	}
 	createdProductItem, err := c.productRepo.CreateProductItem(ctx, newProductItem)
 	return createdProductItem, err
 }
@@ -125,6 +132,9 @@ func (c *productUseCase) FindProductItemByID(ctx context.Context, id int) (domai
 }
 
 func (c *productUseCase) UpdateProductItem(ctx context.Context, info domain.ProductItem) (domain.ProductItem, error) {
+	if info.MaxPerOrder < 0 {
+		return domain.ProductItem{}, fmt.Errorf("max per order cannot be negative")
+	}
 	updatedProductItem, err := c.productRepo.UpdateProductItem(ctx, info)
 	return updatedProductItem, err
 }
@@ -136,42 +146,109 @@ func (c *productUseCase) DeleteProductItem(ctx context.Context, productItemID in
 
 // Coupon Management
 
-func (c *productUseCase) CreateCoupon(ctx context.Context, newCoupon model.CreateCoupon) (domain.Coupon, error) {
+func (c *productUseCase) CreateCoupon(ctx context.Context, newCoupon model.CreateCoupon) (model.CouponDetails, error) {
+	if newCoupon.Code == "" {
+		return model.CouponDetails{}, fmt.Errorf("coupon code is required")
+	}
+	if err := validateCoupon(&newCoupon); err != nil {
+		return model.CouponDetails{}, err
+	}
 	createdCoupon, err := c.productRepo.CreateCoupon(ctx, newCoupon)
 	if err != nil {
-		return domain.Coupon{}, err
+		return model.CouponDetails{}, err
 	}
 	if createdCoupon.ID == 0 {
-		return domain.Coupon{}, fmt.Errorf("failed to create new coupon")
+		return model.CouponDetails{}, fmt.Errorf("failed to create new coupon")
 	}
 	return createdCoupon, nil
 }
 
-func (c *productUseCase) UpdateCoupon(ctx context.Context, couponInfo model.UpdateCoupon) (domain.Coupon, error) {
+func (c *productUseCase) UpdateCoupon(ctx context.Context, couponInfo model.UpdateCoupon) (model.CouponDetails, error) {
+	if couponInfo.Code == "" {
+		return model.CouponDetails{}, fmt.Errorf("coupon code is required")
+	}
+	if err := validateCoupon(&couponInfo.CreateCoupon); err != nil {
+		return model.CouponDetails{}, err
+	}
 	updatedCoupon, err := c.productRepo.UpdateCoupon(ctx, couponInfo)
 
 	if err != nil {
-		return domain.Coupon{}, err
+		return model.CouponDetails{}, err
 	}
 	if updatedCoupon.ID == 0 {
-		return domain.Coupon{}, fmt.Errorf("failed to update the coupon")
+		return model.CouponDetails{}, fmt.Errorf("failed to update the coupon")
 	}
 
 	return updatedCoupon, nil
 }
 
+// validateCoupon checks that the rules of the coupon make sense, filling in the defaults for the ones not given
+func validateCoupon(coupon *model.CreateCoupon) error {
+	switch coupon.DiscountType {
+	case "":
+		coupon.DiscountType = domain.CouponPercent
+		fallthrough
+	case domain.CouponPercent:
+		if coupon.DiscountPercent <= 0 || coupon.DiscountPercent > 100 {
+			return fmt.Errorf("discount percent should be more than 0 and at most 100")
+		}
+		if coupon.DiscountMaxAmount <= 0 {
+			return fmt.Errorf("discount max amount should be more than 0")
+		}
+	case domain.CouponFlat:
+		if coupon.DiscountAmount <= 0 {
+			return fmt.Errorf("discount amount should be more than 0")
+		}
+	default:
+		return fmt.Errorf("discount type should be %v or %v", domain.CouponPercent, domain.CouponFlat)
+	}
+
+	switch coupon.Scope {
+	case "":
+		coupon.Scope = domain.CouponScopeCart
+		fallthrough
+	case domain.CouponScopeCart:
+		if len(coupon.TargetIDs) > 0 {
+			return fmt.Errorf("a coupon on the whole cart cannot have targets")
+		}
+	case domain.CouponScopeCategory, domain.CouponScopeBrand, domain.CouponScopeProductItem:
+		if len(coupon.TargetIDs) == 0 {
+			return fmt.Errorf("a coupon scoped to %v needs at least one target", coupon.Scope)
+		}
+	default:
+		return fmt.Errorf("invalid coupon scope %v", coupon.Scope)
+	}
+
+	//a coupon without a start is valid from when it is saved
+	if coupon.ValidFrom.IsZero() {
+		coupon.ValidFrom = time.Now()
+	}
+	if !coupon.ValidFrom.Before(coupon.ValidTill) {
+		return fmt.Errorf("coupon should be valid till a time after it is valid from")
+	}
+
+	if coupon.PerUserLimit == nil {
+		perUserLimit := 1
+		coupon.PerUserLimit = &perUserLimit
+	}
+	if coupon.MinOrderValue < 0 || coupon.UsageLimit < 0 || *coupon.PerUserLimit < 0 {
+		return fmt.Errorf("minimum order value and usage limits cannot be negative")
+	}
+	return nil
+}
+
 func (c *productUseCase) DeleteCoupon(ctx context.Context, couponID int) error {
 	err := c.productRepo.DeleteCoupon(ctx, couponID)
 	return err
 }
 
-func (c *productUseCase) ViewCouponByID(ctx context.Context, couponID int) (domain.Coupon, error) {
+func (c *productUseCase) ViewCouponByID(ctx context.Context, couponID int) (model.CouponDetails, error) {
 	coupon, err := c.productRepo.ViewCouponByID(ctx, couponID)
 	if err != nil {
-		return domain.Coupon{}, err
+		return model.CouponDetails{}, err
 	}
 	if coupon.ID == 0 {
-		return domain.Coupon{}, fmt.Errorf("failed to fetch coupon")
+		return model.CouponDetails{}, fmt.Errorf("failed to fetch coupon")
 	}
 	return coupon, nil
 
@@ -188,3 +265,8 @@ func (c *productUseCase) ViewAllCoupons(ctx context.Context) ([]domain.Coupon, e
 	}
 	return allCoupons, nil
 }
+
+func (c *productUseCase) CouponRedemptionReport(ctx context.Context) ([]model.CouponRedemptionReport, error) {
+	report, err := c.productRepo.CouponRedemptionReport(ctx)
+	return report, err
+}
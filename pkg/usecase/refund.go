@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/gateway"
+	interfaces "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/interface"
+	services "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/usecase/interface"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"strings"
+)
+
+type refundUseCase struct {
+	refundRepo     interfaces.RefundRepository
+	paymentRepo    interfaces.PaymentRepository
+	paymentGateway gateway.PaymentGateway
+}
+
+func NewRefundUseCase(refundRepo interfaces.RefundRepository, paymentRepo interfaces.PaymentRepository, paymentGateway gateway.PaymentGateway) services.RefundUseCase {
+	return &refundUseCase{
+		refundRepo:     refundRepo,
+		paymentRepo:    paymentRepo,
+		paymentGateway: paymentGateway,
+	}
+}
+
+// CreateRefund refunds a part of the amount paid for an order and sends it to the payment gateway
+func (c *refundUseCase) CreateRefund(ctx context.Context, newRefund model.CreateRefund) (domain.Refund, error) {
+	if newRefund.Amount < 0 {
+		return domain.Refund{}, fmt.Errorf("invalid refund amount")
+	}
+	newRefund.Reason = strings.TrimSpace(newRefund.Reason)
+	if newRefund.Reason == "" {
+		return domain.Refund{}, fmt.Errorf("reason is required to refund an order")
+	}
+	refund, err := c.refundRepo.CreateRefund(ctx, newRefund)
+	if err != nil {
+		return domain.Refund{}, err
+	}
+	return c.issue(ctx, refund)
+}
+
+func (c *refundUseCase) ListRefunds(ctx context.Context, status string) ([]domain.Refund, error) {
+	switch domain.RefundStatus(status) {
+	case "", domain.RefundInitiated, domain.RefundProcessed, domain.RefundFailed:
+	default:
+		return nil, fmt.Errorf("invalid refund status %v", status)
+	}
+	refunds, err := c.refundRepo.ListRefunds(ctx, status)
+	return refunds, err
+}
+
+func (c *refundUseCase) ViewOrderRefunds(ctx context.Context, orderID int) ([]domain.Refund, error) {
+	refunds, err := c.refundRepo.ViewOrderRefunds(ctx, orderID)
+	return refunds, err
+}
+
+// RetryRefund sends a failed refund to the payment gateway again
+func (c *refundUseCase) RetryRefund(ctx context.Context, refundID int) (domain.Refund, error) {
+	refund, err := c.refundRepo.RetryRefund(ctx, refundID)
+	if err != nil {
+		return domain.Refund{}, err
+	}
+	return c.issue(ctx, refund)
+}
+
+// IssueRefund sends an initiated refund to the payment gateway. A refund the gateway does not accept is marked failed
+// and returned without an error, as the change that initiated it is already made, the admin can retry it later.
+// Refunds to the wallet are credited when they are created and are returned as they are.
+func (c *refundUseCase) IssueRefund(ctx context.Context, refund domain.Refund) (domain.Refund, error) {
+	if refund.Status != domain.RefundInitiated || refund.Destination != domain.RefundToGateway {
+		return refund, nil
+	}
+	paymentDetails, err := c.paymentRepo.ViewPaymentDetails(ctx, int(refund.OrderID))
+	if err != nil {
+		return domain.Refund{}, err
+	}
+	if paymentDetails.PaymentMethodID != domain.PaymentOnline || paymentDetails.PaymentRef == "" {
+		return refund, nil
+	}
+
+	refundRef, err := c.paymentGateway.Refund(ctx, paymentDetails.PaymentRef, refund.Amount)
+	if err != nil {
+		refund.Status = domain.RefundFailed
+		refund.FailureReason = err.Error()
+		return refund, c.refundRepo.MarkRefundFailed(ctx, refund.ID, refund.FailureReason)
+	}
+	refund.RefundRef = refundRef
+	return refund, c.refundRepo.SetRefundRef(ctx, refund.ID, refundRef)
+}
+
+// issue sends a refund made by the admin to the payment gateway, reporting a refund the gateway did not accept as an error
+func (c *refundUseCase) issue(ctx context.Context, refund domain.Refund) (domain.Refund, error) {
+	refund, err := c.IssueRefund(ctx, refund)
+	if err != nil {
+		return domain.Refund{}, err
+	}
+	if refund.Status == domain.RefundFailed {
+		return refund, fmt.Errorf("refund failed: %v", refund.FailureReason)
+	}
+	return refund, nil
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/gateway"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/mockRepo"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestIssueRefund(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	refundRepo := mockRepo.NewMockRefundRepository(ctrl)
+	paymentRepo := mockRepo.NewMockPaymentRepository(ctrl)
+
+	//a captured payment of 54000 at the gateway
+	fakeGateway := gateway.NewFakeGateway("webhook_secret")
+	gatewayOrderID, _ := fakeGateway.CreateOrder(context.TODO(), "order_4", 54000, nil)
+	paymentID, _, _ := fakeGateway.Pay(gatewayOrderID)
+	if err := fakeGateway.CapturePayment(context.TODO(), paymentID, 54000); err != nil {
+		t.Fatalf("an error '%s' was not expected when capturing the payment", err)
+	}
+
+	refundUseCase := NewRefundUseCase(refundRepo, paymentRepo, fakeGateway)
+
+	testData := []struct {
+		name           string
+		input          domain.Refund
+		buildStub      func(refundRepo mockRepo.MockRefundRepository, paymentRepo mockRepo.MockPaymentRepository)
+		expectedOutput domain.Refund
+		expectedError  error
+	}{
+		{
+			name:  "online payment",
+			input: domain.Refund{ID: 5, OrderID: 4, Amount: 54000, Destination: domain.RefundToGateway, Status: domain.RefundInitiated},
+			buildStub: func(refundRepo mockRepo.MockRefundRepository, paymentRepo mockRepo.MockPaymentRepository) {
+				paymentRepo.EXPECT().ViewPaymentDetails(gomock.Any(), 4).Times(1).
+					Return(domain.PaymentDetails{ID: 9, OrderID: 4, OrderTotal: 54000, PaymentMethodID: domain.PaymentOnline, PaymentRef: paymentID}, nil)
+				refundRepo.EXPECT().SetRefundRef(gomock.Any(), uint(5), "rfnd_fake3").Times(1).
+					Return(nil)
+			},
+			expectedOutput: domain.Refund{ID: 5, OrderID: 4, Amount: 54000, Destination: domain.RefundToGateway, Status: domain.RefundInitiated, RefundRef: "rfnd_fake3"},
+			expectedError:  nil,
+		},
+		{
+			name:  "refund not accepted by the gateway",
+			input: domain.Refund{ID: 6, OrderID: 4, Amount: 100, Destination: domain.RefundToGateway, Status: domain.RefundInitiated},
+			buildStub: func(refundRepo mockRepo.MockRefundRepository, paymentRepo mockRepo.MockPaymentRepository) {
+				paymentRepo.EXPECT().ViewPaymentDetails(gomock.Any(), 4).Times(1).
+					Return(domain.PaymentDetails{ID: 9, OrderID: 4, OrderTotal: 54000, PaymentMethodID: domain.PaymentOnline, PaymentRef: paymentID}, nil)
+				refundRepo.EXPECT().MarkRefundFailed(gomock.Any(), uint(6), "refund amount is more than the amount left to refund").Times(1).
+					Return(nil)
+			},
+			expectedOutput: domain.Refund{ID: 6, OrderID: 4, Amount: 100, Destination: domain.RefundToGateway, Status: domain.RefundFailed, FailureReason: "refund amount is more than the amount left to refund"},
+			expectedError:  nil,
+		},
+		{
+			name:           "refund to the wallet",
+			input:          domain.Refund{ID: 7, OrderID: 6, Amount: 1200, Destination: domain.RefundToWallet, Status: domain.RefundProcessed},
+			buildStub:      func(refundRepo mockRepo.MockRefundRepository, paymentRepo mockRepo.MockPaymentRepository) {},
+			expectedOutput: domain.Refund{ID: 7, OrderID: 6, Amount: 1200, Destination: domain.RefundToWallet, Status: domain.RefundProcessed},
+			expectedError:  nil,
+		},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStub(*refundRepo, *paymentRepo)
+			actualRefund, err := refundUseCase.IssueRefund(context.TODO(), tt.input)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, actualRefund)
+		})
+	}
+}
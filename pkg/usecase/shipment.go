@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	interfaces "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/interface"
+	services "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/usecase/interface"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"strings"
+	"time"
+)
+
+type shipmentUseCase struct {
+	shipmentRepo interfaces.ShipmentRepository
+}
+
+func NewShipmentUseCase(shipmentRepo interfaces.ShipmentRepository) services.ShipmentUseCase {
+	return &shipmentUseCase{
+		shipmentRepo: shipmentRepo,
+	}
+}
+
+func (c *shipmentUseCase) CreateCarrier(ctx context.Context, newCarrier model.CreateCarrier) (domain.Carrier, error) {
+	newCarrier.Name = strings.TrimSpace(newCarrier.Name)
+	if newCarrier.Name == "" {
+		return domain.Carrier{}, fmt.Errorf("carrier name is required")
+	}
+	if newCarrier.TrackingURL != "" && !strings.Contains(newCarrier.TrackingURL, "{tracking_number}") {
+		return domain.Carrier{}, fmt.Errorf("tracking url should contain {tracking_number}")
+	}
+	carrier, err := c.shipmentRepo.CreateCarrier(ctx, newCarrier)
+	return carrier, err
+}
+
+func (c *shipmentUseCase) ListCarriers(ctx context.Context) ([]domain.Carrier, error) {
+	carriers, err := c.shipmentRepo.ListCarriers(ctx)
+	return carriers, err
+}
+
+func (c *shipmentUseCase) CreateShipment(ctx context.Context, newShipment model.CreateShipment) (model.ShipmentDetails, error) {
+	newShipment.TrackingNumber = strings.TrimSpace(newShipment.TrackingNumber)
+	if newShipment.TrackingNumber == "" {
+		return model.ShipmentDetails{}, fmt.Errorf("tracking number is required")
+	}
+	seen := make(map[int]bool, len(newShipment.Lines))
+	for _, line := range newShipment.Lines {
+		if line.Quantity <= 0 {
+			return model.ShipmentDetails{}, fmt.Errorf("quantity of order line %v should be positive", line.OrderLineID)
+		}
+		if seen[line.OrderLineID] {
+			return model.ShipmentDetails{}, fmt.Errorf("order line %v is listed more than once", line.OrderLineID)
+		}
+		seen[line.OrderLineID] = true
+	}
+	shipment, err := c.shipmentRepo.CreateShipment(ctx, newShipment)
+	return shipment, err
+}
+
+// AddShipmentEvent posts a tracking event reported by the carrier. Shipments are dispatched when they are created,
+// so an event cannot be dispatched, and events cannot be dated in the future.
+func (c *shipmentUseCase) AddShipmentEvent(ctx context.Context, event model.ShipmentEvent) (domain.ShipmentEvent, error) {
+	switch domain.ShipmentStatus(event.Status) {
+	case domain.ShipmentInTransit, domain.ShipmentOutForDelivery, domain.ShipmentDelivered:
+	default:
+		return domain.ShipmentEvent{}, fmt.Errorf("invalid status, should be in_transit, out_for_delivery or delivered")
+	}
+	if event.OccurredAt.After(time.Now()) {
+		return domain.ShipmentEvent{}, fmt.Errorf("event cannot be in the future")
+	}
+	recordedEvent, err := c.shipmentRepo.AddShipmentEvent(ctx, event)
+	return recordedEvent, err
+}
+
+func (c *shipmentUseCase) ViewOrderShipments(ctx context.Context, orderID int) ([]model.ShipmentDetails, error) {
+	shipments, err := c.shipmentRepo.ViewOrderShipments(ctx, orderID)
+	return shipments, err
+}
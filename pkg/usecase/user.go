@@ -125,11 +125,31 @@ func (c *userUseCase) AddAddress(ctx context.Context, newAddress model.AddressIn
 	return address, err
 }
 
-func (c *userUseCase) UpdateAddress(ctx context.Context, addressInfo model.AddressInput, userID int) (domain.Address, error) {
-	updatedAddress, err := c.userRepo.UpdateAddress(ctx, userID, addressInfo)
+func (c *userUseCase) UpdateAddress(ctx context.Context, addressID int, addressInfo model.AddressInput, userID int) (domain.Address, error) {
+	updatedAddress, err := c.userRepo.UpdateAddress(ctx, userID, addressID, addressInfo)
 	return updatedAddress, err
 }
 
+func (c *userUseCase) ListAddresses(ctx context.Context, userID int) ([]domain.Address, error) {
+	addresses, err := c.userRepo.ListAddresses(ctx, userID)
+	return addresses, err
+}
+
+func (c *userUseCase) FindAddressByID(ctx context.Context, addressID, userID int) (domain.Address, error) {
+	address, err := c.userRepo.FindAddressByID(ctx, userID, addressID)
+	return address, err
+}
+
+func (c *userUseCase) SetDefaultAddress(ctx context.Context, addressID, userID int) (domain.Address, error) {
+	address, err := c.userRepo.SetDefaultAddress(ctx, userID, addressID)
+	return address, err
+}
+
+func (c *userUseCase) DeleteAddress(ctx context.Context, addressID, userID int) error {
+	err := c.userRepo.DeleteAddress(ctx, userID, addressID)
+	return err
+}
+
 func (c *userUseCase) ListAllUsers(ctx context.Context, viewUserInfo model.QueryParams) ([]domain.Users, error) {
 	users, err := c.userRepo.ListAllUsers(ctx, viewUserInfo)
 	return users, err
@@ -166,8 +186,8 @@ func (c *userUseCase) UserProfile(ctx context.Context, userID int) (model.UserPr
 		Phone: userInfoRetrieved.Phone,
 	}
 
-	// fetch address from address table
-	address, err := c.userRepo.ViewAddress(ctx, userID)
+	// fetch addresses from address table
+	addresses, err := c.userRepo.ListAddresses(ctx, userID)
 	if err != nil {
 		return model.UserProfile{}, err
 	}
@@ -180,7 +200,7 @@ func (c *userUseCase) UserProfile(ctx context.Context, userID int) (model.UserPr
 
 	var userProfile model.UserProfile
 	userProfile.UserInfo = userInfo
-	userProfile.Address = address
+	userProfile.Addresses = addresses
 	userProfile.Orders = orders
 
 	return userProfile, nil
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	interfaces "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/interface"
+	services "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/usecase/interface"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"math"
+	"strings"
+)
+
+type walletUseCase struct {
+	walletRepo interfaces.WalletRepository
+}
+
+func NewWalletUseCase(walletRepo interfaces.WalletRepository) services.WalletUseCase {
+	return &walletUseCase{
+		walletRepo: walletRepo,
+	}
+}
+
+func (c *walletUseCase) ViewWallet(ctx context.Context, userID int) (model.Wallet, error) {
+	wallet, err := c.walletRepo.ViewWallet(ctx, userID)
+	return wallet, err
+}
+
+// CreditWallet posts a credit by an admin, like a goodwill credit or a referral reward, to the wallet of a user.
+// Refunds and purchases are posted by the order flows themselves.
+func (c *walletUseCase) CreditWallet(ctx context.Context, adjustment model.WalletAdjustment, adminID int) (domain.WalletEntry, error) {
+	if adjustment.Reason == "" {
+		adjustment.Reason = string(domain.WalletAdjustment)
+	}
+	switch domain.WalletEntryReason(adjustment.Reason) {
+	case domain.WalletGoodwill, domain.WalletReferral, domain.WalletAdjustment:
+	default:
+		return domain.WalletEntry{}, fmt.Errorf("invalid reason, should be goodwill, referral or adjustment")
+	}
+	if err := validateWalletAdjustment(&adjustment); err != nil {
+		return domain.WalletEntry{}, err
+	}
+	entry, err := c.walletRepo.AdjustWallet(ctx, adjustment, adminID)
+	return entry, err
+}
+
+// DebitWallet takes money out of the wallet of a user, for correcting a credit made by mistake
+func (c *walletUseCase) DebitWallet(ctx context.Context, adjustment model.WalletAdjustment, adminID int) (domain.WalletEntry, error) {
+	if adjustment.Reason == "" {
+		adjustment.Reason = string(domain.WalletAdjustment)
+	}
+	if domain.WalletEntryReason(adjustment.Reason) != domain.WalletAdjustment {
+		return domain.WalletEntry{}, fmt.Errorf("invalid reason, should be adjustment")
+	}
+	if err := validateWalletAdjustment(&adjustment); err != nil {
+		return domain.WalletEntry{}, err
+	}
+	adjustment.Amount = -adjustment.Amount
+	entry, err := c.walletRepo.AdjustWallet(ctx, adjustment, adminID)
+	return entry, err
+}
+
+// validateWalletAdjustment checks the amount of an adjustment and that the admin noted why it was made
+func validateWalletAdjustment(adjustment *model.WalletAdjustment) error {
+	if adjustment.Amount <= 0 {
+		return fmt.Errorf("amount should be positive")
+	}
+	if adjustment.Amount != math.Round(adjustment.Amount*100)/100 {
+		return fmt.Errorf("amount cannot have more than two decimal places")
+	}
+	adjustment.Note = strings.TrimSpace(adjustment.Note)
+	if adjustment.Note == "" {
+		return fmt.Errorf("note is required for a wallet adjustment")
+	}
+	return nil
+}
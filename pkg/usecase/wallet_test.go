@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/repository/mockRepo"
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/util/model"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDebitWallet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	walletRepo := mockRepo.NewMockWalletRepository(ctrl)
+	walletUseCase := NewWalletUseCase(walletRepo)
+
+	testData := []struct {
+		name           string
+		input          model.WalletAdjustment
+		buildStub      func(walletRepo mockRepo.MockWalletRepository)
+		expectedOutput domain.WalletEntry
+		expectedError  error
+	}{
+		{
+			name:  "debit",
+			input: model.WalletAdjustment{UserID: 3, Amount: 250.5, Note: " credited twice "},
+			buildStub: func(walletRepo mockRepo.MockWalletRepository) {
+				walletRepo.EXPECT().AdjustWallet(gomock.Any(), model.WalletAdjustment{UserID: 3, Amount: -250.5, Reason: "adjustment", Note: "credited twice"}, 1).Times(1).
+					Return(domain.WalletEntry{ID: 8, UserID: 3, Amount: -250.5, BalanceAfter: 100, Reason: domain.WalletAdjustment, Note: "credited twice"}, nil)
+			},
+			expectedOutput: domain.WalletEntry{ID: 8, UserID: 3, Amount: -250.5, BalanceAfter: 100, Reason: domain.WalletAdjustment, Note: "credited twice"},
+			expectedError:  nil,
+		},
+		{
+			name:           "without a note",
+			input:          model.WalletAdjustment{UserID: 3, Amount: 250.5, Note: " "},
+			buildStub:      func(walletRepo mockRepo.MockWalletRepository) {},
+			expectedOutput: domain.WalletEntry{},
+			expectedError:  errors.New("note is required for a wallet adjustment"),
+		},
+		{
+			name:           "more than two decimal places",
+			input:          model.WalletAdjustment{UserID: 3, Amount: 250.555, Note: "credited twice"},
+			buildStub:      func(walletRepo mockRepo.MockWalletRepository) {},
+			expectedOutput: domain.WalletEntry{},
+			expectedError:  errors.New("amount cannot have more than two decimal places"),
+		},
+		{
+			name:           "not an adjustment",
+			input:          model.WalletAdjustment{UserID: 3, Amount: 250.5, Reason: "goodwill", Note: "credited twice"},
+			buildStub:      func(walletRepo mockRepo.MockWalletRepository) {},
+			expectedOutput: domain.WalletEntry{},
+			expectedError:  errors.New("invalid reason, should be adjustment"),
+		},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStub(*walletRepo)
+			actualEntry, err := walletUseCase.DebitWallet(context.TODO(), tt.input, 1)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedOutput, actualEntry)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package couponcode
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+const (
+	// DefaultAlphabet leaves out 0, O, 1 and I, which are easily mistaken for each other when a code is typed
+	DefaultAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	DefaultLength   = 8
+)
+
+var (
+	prefixPattern   = regexp.MustCompile(`^[A-Za-z0-9_-]{0,20}$`)
+	alphabetPattern = regexp.MustCompile(`^[A-Za-z0-9]{2,64}$`)
+)
+
+// Generator makes random codes of the prefix followed by length characters picked from the alphabet
+type Generator struct {
+	Prefix   string
+	Alphabet string
+	Length   int
+}
+
+// Validate checks that the generator makes codes that can be typed in and are hard to guess
+func (g Generator) Validate() error {
+	if !prefixPattern.MatchString(g.Prefix) {
+		return fmt.Errorf("prefix should be at most 20 letters, digits, dashes or underscores")
+	}
+	if !alphabetPattern.MatchString(g.Alphabet) {
+		return fmt.Errorf("alphabet should be 2 to 64 letters or digits")
+	}
+	for i, char := range g.Alphabet {
+		if strings.ContainsRune(g.Alphabet[i+1:], char) {
+			return fmt.Errorf("alphabet has %c more than once", char)
+		}
+	}
+	if g.Length < 4 || g.Length > 32 {
+		return fmt.Errorf("code length should be between 4 and 32")
+	}
+	return nil
+}
+
+// Combinations is how many different codes the generator can make
+func (g Generator) Combinations() float64 {
+	return math.Pow(float64(len(g.Alphabet)), float64(g.Length))
+}
+
+// Generate makes a code. The characters are picked with crypto/rand, so that a code cannot be guessed from others.
+func (g Generator) Generate() (string, error) {
+	var code strings.Builder
+	code.WriteString(g.Prefix)
+	max := big.NewInt(int64(len(g.Alphabet)))
+	for i := 0; i < g.Length; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code.WriteByte(g.Alphabet[n.Int64()])
+	}
+	return code.String(), nil
+}
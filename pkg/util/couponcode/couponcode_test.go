@@ -0,0 +1,60 @@
+package couponcode
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		generator   Generator
+		expectedErr error
+	}{
+		{
+			name:        "valid",
+			generator:   Generator{Prefix: "INFLU-", Alphabet: DefaultAlphabet, Length: DefaultLength},
+			expectedErr: nil,
+		},
+		{ //test case for a prefix that cannot be typed in as a code
+			name:        "prefix with spaces",
+			generator:   Generator{Prefix: "SUMMER SALE", Alphabet: DefaultAlphabet, Length: DefaultLength},
+			expectedErr: errors.New("prefix should be at most 20 letters, digits, dashes or underscores"),
+		},
+		{
+			name:        "alphabet with symbols",
+			generator:   Generator{Alphabet: "AB%$", Length: DefaultLength},
+			expectedErr: errors.New("alphabet should be 2 to 64 letters or digits"),
+		},
+		{ //test case for an alphabet with a repeated character, the codes would not be evenly random
+			name:        "repeated character",
+			generator:   Generator{Alphabet: "ABCA", Length: DefaultLength},
+			expectedErr: errors.New("alphabet has A more than once"),
+		},
+		{
+			name:        "too short",
+			generator:   Generator{Alphabet: DefaultAlphabet, Length: 3},
+			expectedErr: errors.New("code length should be between 4 and 32"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedErr, tt.generator.Validate())
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	generator := Generator{Prefix: "EMAIL-", Alphabet: "XYZ", Length: 6}
+
+	for i := 0; i < 100; i++ {
+		code, err := generator.Generate()
+		assert.NoError(t, err)
+		assert.Len(t, code, len("EMAIL-")+6)
+		assert.True(t, strings.HasPrefix(code, "EMAIL-"))
+		assert.Empty(t, strings.Trim(strings.TrimPrefix(code, "EMAIL-"), "XYZ"))
+	}
+}
@@ -1,6 +1,9 @@
 package model
 
-import "time"
+import (
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"time"
+)
 
 type NewAdminInfo struct {
 	UserName string `json:"user_name" validate:"required"`
@@ -36,12 +39,16 @@ type AdminDashboard struct {
 }
 
 type SalesReport struct {
-	OrderID        int
-	UserID         int
-	Total          float64
-	CouponCode     string
-	PaymentMethod  string
-	OrderStatus    string
-	DeliveryStatus string
-	OrderDate      time.Time
+	OrderID         int
+	UserID          int
+	Total           float64
+	CouponCode      string
+	PaymentMethod   string
+	PaymentStatus   string
+	CODCollected    float64
+	CODCollectedBy  string
+	OrderStatus     string
+	DeliveryStatus  string
+	OrderDate       time.Time
+	ShippingAddress domain.OrderAddress `gorm:"embedded;embeddedPrefix:shipping_"`
 }
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"time"
+)
+
+type CreateCouponCampaign struct {
+	Name       string       `json:"name"`
+	Prefix     string       `json:"prefix,omitempty"`
+	Alphabet   string       `json:"alphabet,omitempty"`    // letters and digits that are not easily confused if not given
+	CodeLength int          `json:"code_length,omitempty"` // of the random part of the codes, 8 if not given
+	CodeCount  int          `json:"code_count"`
+	Rules      CreateCoupon `json:"rules"` // shared by the codes, the code and usage limits are set for each code
+}
+
+// CouponCampaignReport is a campaign with how many of its codes were redeemed and what their discounts cost
+type CouponCampaignReport struct {
+	domain.CouponCampaign
+	Redeemed     int     `json:"redeemed"`
+	DiscountCost float64 `json:"discount_cost"`
+}
+
+// CampaignCode is a code of a campaign and the order that redeemed it, if it was redeemed
+type CampaignCode struct {
+	Code       string
+	ValidTill  time.Time
+	OrderID    uint
+	RedeemedAt *time.Time
+}
@@ -9,12 +9,41 @@ type DisplayCart struct {
 	ProductItemImage string
 	Price            float64
 	Total            float64
+	PriceWhenAdded   float64
+	QntyInStock      int
+	MaxPerOrder      int
+	// Issue tells why the item cannot be checked out as it is, like being out of stock or a changed price
+	Issue string `json:",omitempty"`
 }
 
 type ViewCart struct {
 	CartItems []DisplayCart `json:"cart_items,omitempty"`
 	CouponID  int           `json:"coupon_id,omitempty"`
-	SubTotal  float64       `json:"sub_total"`
-	Discount  float64       `json:"discount"`
-	CartTotal float64       `json:"cart_total,omitempty"`
+	// CouponRemoved tells why the last coupon of the cart was removed, when it stopped applying to the cart
+	CouponRemoved string  `json:"coupon_removed,omitempty"`
+	SubTotal      float64 `json:"sub_total"`
+	Discount      float64 `json:"discount"`
+	Tax           float64 `json:"tax"`
+	CartTotal     float64 `json:"cart_total,omitempty"`
+	CanCheckout   bool    `json:"can_checkout"`
+}
+
+// UpdateCartItem sets the quantity of a product item in the cart, 0 removes the item
+type UpdateCartItem struct {
+	Quantity int `json:"quantity"`
+}
+
+// ApplicableCoupon is a coupon that applies to the cart, with the discount it gives and what the cart costs with it
+type ApplicableCoupon struct {
+	CouponID  uint    `json:"coupon_id"`
+	Code      string  `json:"code"`
+	Discount  float64 `json:"discount"`
+	CartTotal float64 `json:"cart_total"`
+}
+
+// ApplicableCoupons are the coupons that apply to the cart, the largest discount first
+type ApplicableCoupons struct {
+	Coupons []ApplicableCoupon `json:"coupons"`
+	// Cart is the cart with the best coupon applied, set only when the best coupon is applied
+	Cart *ViewCart `json:"cart,omitempty"`
 }
@@ -0,0 +1,29 @@
+package model
+
+import "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+
+// CODRules are the rules for cash on delivery along with the pincodes it is available in
+type CODRules struct {
+	domain.CODRules
+	Pincodes []string `json:"pincodes"`
+}
+
+// UpdateCODRules sets the limits for cash on delivery orders, zero removes a limit
+type UpdateCODRules struct {
+	MaxOrderValue        float64 `json:"max_order_value"`
+	MaxRefusedDeliveries int     `json:"max_refused_deliveries"`
+}
+
+type CODPincode struct {
+	Pincode string `json:"pincode" binding:"required"`
+}
+
+// CODCollection is what the courier reports after a cash on delivery attempt. Status is collected or refused, Amount is
+// the cash collected and CollectedBy is the courier who attempted the delivery.
+type CODCollection struct {
+	OrderID     int     `json:"-"`
+	Status      string  `json:"status"`
+	Amount      float64 `json:"amount"`
+	CollectedBy string  `json:"collected_by"`
+	Note        string  `json:"note"`
+}
@@ -1,20 +1,46 @@
 package model
 
-import "time"
+import (
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"time"
+)
 
 type CreateCoupon struct {
-	Code              string    `json:"code,omitempty"`
-	MinOrderValue     float64   `json:"min_order_value,omitempty"`
-	DiscountPercent   float64   `json:"discount_percent,omitempty"`
-	DiscountMaxAmount float64   `json:"discount_max_amount,omitempty"`
-	ValidTill         time.Time `json:"valid_till"`
+	Code              string                    `json:"code,omitempty"`
+	MinOrderValue     float64                   `json:"min_order_value,omitempty"`
+	DiscountType      domain.CouponDiscountType `json:"discount_type,omitempty"`
+	DiscountPercent   float64                   `json:"discount_percent,omitempty"`
+	DiscountMaxAmount float64                   `json:"discount_max_amount,omitempty"`
+	DiscountAmount    float64                   `json:"discount_amount,omitempty"`
+	Scope             domain.CouponScope        `json:"scope,omitempty"`
+	TargetIDs         []uint                    `json:"target_ids,omitempty"` // categories, brands or product items, depending on the scope
+	UserIDs           []uint                    `json:"user_ids,omitempty"`   // empty for a coupon for everyone
+	ValidFrom         time.Time                 `json:"valid_from"`
+	ValidTill         time.Time                 `json:"valid_till"`
+	UsageLimit        int                       `json:"usage_limit,omitempty"`
+	PerUserLimit      *int                      `json:"per_user_limit,omitempty"` // once per user if not given
+	FirstOrderOnly    bool                      `json:"first_order_only,omitempty"`
 }
 
+// UpdateCoupon replaces the details of the coupon with the id
 type UpdateCoupon struct {
-	ID                int       `json:"id"`
-	Code              string    `json:"code,omitempty"`
-	MinOrderValue     float64   `json:"min_order_value,omitempty"`
-	DiscountPercent   float64   `json:"discount_percent,omitempty"`
-	DiscountMaxAmount float64   `json:"discount_max_amount,omitempty"`
-	ValidTill         time.Time `json:"valid_till"`
+	ID int `json:"id"`
+	CreateCoupon
+}
+
+// CouponDetails is a coupon with the targets of its scope and the users it is for
+type CouponDetails struct {
+	domain.Coupon
+	TargetIDs []uint `json:"target_ids"`
+	UserIDs   []uint `json:"user_ids"`
+}
+
+// CouponRedemptionReport is how many times a coupon was redeemed and what the discounts it gave cost
+type CouponRedemptionReport struct {
+	CouponID     uint    `json:"coupon_id"`
+	Code         string  `json:"code"`
+	UsageLimit   int     `json:"usage_limit"`
+	Redemptions  int     `json:"redemptions"`   // by orders that were not cancelled
+	Released     int     `json:"released"`      // given back by cancelled orders
+	DiscountCost float64 `json:"discount_cost"` // of the orders that were not cancelled
 }
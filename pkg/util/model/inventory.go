@@ -0,0 +1,15 @@
+package model
+
+type StockAdjustment struct {
+	ProductItemID int    `json:"-"`
+	Quantity      int    `json:"quantity"`
+	Reason        string `json:"reason"`
+	Note          string `json:"note"`
+}
+
+type StockReconciliation struct {
+	ProductItemID  int `json:"product_item_id"`
+	QntyInStock    int `json:"qnty_in_stock"`
+	LedgerQuantity int `json:"ledger_quantity"`
+	Difference     int `json:"difference"`
+}
@@ -1,23 +1,43 @@
 package model
 
+import "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+
+// PlaceOrder is an order of a single product item. With UseWallet the order is paid from the wallet of the user as far
+// as its balance goes, the rest is paid with the payment method.
 type PlaceOrder struct {
-	ProductItemID     int `json:"product_item_id,omitempty"`
-	PaymentMethodID   int `json:"payment_method_id,omitempty"`
-	ShippingAddressID int `json:"shipping_address_id,omitempty"`
-	CouponID          int `json:"coupon_id,omitempty"`
+	ProductItemID     int                    `json:"product_item_id,omitempty"`
+	Quantity          int                    `json:"quantity,omitempty"`
+	PaymentMethodID   domain.PaymentMethodID `json:"payment_method_id,omitempty"`
+	UseWallet         bool                   `json:"use_wallet,omitempty"`
+	ShippingAddressID int                    `json:"shipping_address_id,omitempty"`
+	CouponID          int                    `json:"coupon_id,omitempty"`
 }
 type PlaceAllOrders struct {
-	PaymentMethodID   int `json:"payment_method_id,omitempty"`
-	ShippingAddressID int `json:"shipping_address_id,omitempty"`
+	PaymentMethodID   domain.PaymentMethodID `json:"payment_method_id,omitempty"`
+	UseWallet         bool                   `json:"use_wallet,omitempty"`
+	ShippingAddressID int                    `json:"shipping_address_id,omitempty"`
 }
 
 type UpdateOrder struct {
-	OrderID          int `json:"order_id"`
-	OrderStatusID    int `json:"order_status_id"`
-	DeliveryStatusID int `json:"delivery_status_id"`
+	OrderID          int                     `json:"order_id"`
+	OrderStatusID    domain.OrderStatusID    `json:"order_status_id"`
+	DeliveryStatusID domain.DeliveryStatusID `json:"delivery_status_id"`
 }
 
 type ReturnRequest struct {
 	OrderID int    `json:"order_id"`
 	Reason  string `json:"reason"`
 }
+
+type ReturnDecision struct {
+	Note string `json:"note"`
+}
+
+// OrderDetails is the order as shown to the user, along with the tracking of its shipments,
+// the progress of its return if one was requested and the refunds made for it
+type OrderDetails struct {
+	domain.Order
+	Shipments []ShipmentDetails `json:"shipments,omitempty"`
+	Return    *domain.Return    `json:"return,omitempty"`
+	Refunds   []domain.Refund   `json:"refunds,omitempty"`
+}
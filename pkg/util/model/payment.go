@@ -1,8 +1,46 @@
 package model
 
+import (
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"time"
+)
+
+// PaymentCheckout is what the checkout of the payment gateway is opened with on the browser
+// Amount is the part of the order total paid through the gateway, the rest is paid from the wallet.
+type PaymentCheckout struct {
+	Order          domain.Order
+	Amount         float64
+	GatewayOrderID string
+	KeyID          string
+}
+
+// PaymentVerification is what the checkout of the payment gateway returns to the browser once the user has paid
 type PaymentVerification struct {
-	UserID     int
-	OrderID    int
-	PaymentRef string
-	Total      float64
+	UserID            int
+	OrderID           int
+	RazorpayOrderID   string
+	RazorpayPaymentID string
+	RazorpaySignature string
+}
+
+// PaymentReconciliation reports what a run of the payment reconciler found at the gateway and changed
+type PaymentReconciliation struct {
+	StartedAt time.Time           `json:"started_at"`
+	Checked   int                 `json:"checked"`
+	Paid      int                 `json:"paid"`
+	Failed    int                 `json:"failed"`
+	Expired   int                 `json:"expired"`
+	Errors    int                 `json:"errors"`
+	Payments  []ReconciledPayment `json:"payments"`
+}
+
+// ReconciledPayment is a stale payment the reconciler changed, or could not reconcile
+type ReconciledPayment struct {
+	OrderID        uint    `json:"order_id"`
+	GatewayOrderID string  `json:"gateway_order_id,omitempty"`
+	PaymentRef     string  `json:"payment_ref,omitempty"`
+	From           string  `json:"from"`
+	To             string  `json:"to"`
+	WalletRefund   float64 `json:"wallet_refund,omitempty"`
+	Error          string  `json:"error,omitempty"`
 }
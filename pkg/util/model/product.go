@@ -16,11 +16,15 @@ type ProductItemID struct {
 	ID int `json:"id"`
 }
 
+// QueryParams are the paging, filtering and sorting of a listing. Query is searched for in the Filter field. Filters
+// are more filters, each written as field:operator:value, with the operator one of eq, in, range or ilike. The values
+// of in are separated by commas and range takes a min and a max separated by a comma, either of which can be left out.
 type QueryParams struct {
-	Page     int    `json:"page"`
-	Limit    int    `json:"limit"`
-	Query    string `json:"query"`
-	Filter   string `json:"filter"`
-	SortBy   string `json:"sort_by"`
-	SortDesc bool   `json:"sort_desc"`
+	Page     int      `json:"page"`
+	Limit    int      `json:"limit"`
+	Query    string   `json:"query"`
+	Filter   string   `json:"filter"`
+	Filters  []string `json:"filters"`
+	SortBy   string   `json:"sort_by"`
+	SortDesc bool     `json:"sort_desc"`
 }
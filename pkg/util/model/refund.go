@@ -0,0 +1,9 @@
+package model
+
+// CreateRefund is a refund of a part of the amount paid for an order, made by the admin. An amount of 0 refunds
+// everything paid for the order that is not refunded yet.
+type CreateRefund struct {
+	OrderID int     `json:"-"`
+	Amount  float64 `json:"amount"`
+	Reason  string  `json:"reason"`
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+	"time"
+)
+
+type CreateCarrier struct {
+	Name        string `json:"name"`
+	TrackingURL string `json:"tracking_url"`
+}
+
+type CreateShipment struct {
+	OrderID        int            `json:"-"`
+	CarrierID      int            `json:"carrier_id"`
+	TrackingNumber string         `json:"tracking_number"`
+	Lines          []ShipmentItem `json:"lines"`
+}
+
+// ShipmentItem is the quantity of an order line carried by a shipment. A shipment created without lines carries
+// everything of the order that is not shipped yet.
+type ShipmentItem struct {
+	OrderLineID int `json:"order_line_id"`
+	Quantity    int `json:"quantity"`
+}
+
+type ShipmentEvent struct {
+	ShipmentID int       `json:"-"`
+	Status     string    `json:"status"`
+	Location   string    `json:"location"`
+	Note       string    `json:"note"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// ShipmentDetails is a shipment along with the order lines it carries and its tracking timeline, oldest event first
+type ShipmentDetails struct {
+	domain.Shipment
+	CarrierName string                 `json:"carrier"`
+	TrackingURL string                 `json:"tracking_url,omitempty"`
+	Lines       []domain.ShipmentLine  `gorm:"-" json:"lines"`
+	Events      []domain.ShipmentEvent `gorm:"-" json:"events"`
+}
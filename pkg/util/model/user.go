@@ -46,6 +46,8 @@ type AddressInput struct {
 	District    string `json:"district"`
 	Pincode     string `json:"pincode"`
 	Landmark    string `json:"landmark"`
+	Label       string `json:"label"`
+	IsDefault   bool   `json:"is_default"`
 }
 
 type BlockUser struct {
@@ -54,7 +56,7 @@ type BlockUser struct {
 }
 
 type UserProfile struct {
-	UserInfo UserDataOutput
-	Address  domain.Address
-	Orders   []domain.Order
+	UserInfo  UserDataOutput
+	Addresses []domain.Address
+	Orders    []domain.Order
 }
@@ -0,0 +1,17 @@
+package model
+
+import "github.com/amalmadhu06/project-laptop-store-clean-arch/pkg/domain"
+
+// WalletAdjustment is a credit or debit posted to the wallet of a user by an admin. Amount is always positive.
+type WalletAdjustment struct {
+	UserID int     `json:"-"`
+	Amount float64 `json:"amount"`
+	Reason string  `json:"reason"`
+	Note   string  `json:"note"`
+}
+
+// Wallet is the balance of the wallet of a user along with its ledger, latest entry first
+type Wallet struct {
+	Balance float64              `json:"balance"`
+	Entries []domain.WalletEntry `json:"entries"`
+}
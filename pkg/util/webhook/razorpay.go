@@ -0,0 +1,59 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Razorpay webhook events handled by the store
+const (
+	PaymentCaptured = "payment.captured"
+	PaymentFailed   = "payment.failed"
+	RefundProcessed = "refund.processed"
+	RefundFailed    = "refund.failed"
+)
+
+// RazorpayEvent is the body of a webhook sent by Razorpay. Amounts are in paise.
+type RazorpayEvent struct {
+	Event   string `json:"event"`
+	Payload struct {
+		Payment struct {
+			Entity RazorpayPayment `json:"entity"`
+		} `json:"payment"`
+		Refund struct {
+			Entity RazorpayRefund `json:"entity"`
+		} `json:"refund"`
+	} `json:"payload"`
+}
+
+type RazorpayPayment struct {
+	ID               string            `json:"id"`
+	OrderID          string            `json:"order_id"`
+	Amount           int64             `json:"amount"`
+	Status           string            `json:"status"`
+	Notes            map[string]string `json:"notes"`
+	ErrorDescription string            `json:"error_description"`
+}
+
+type RazorpayRefund struct {
+	ID        string `json:"id"`
+	PaymentID string `json:"payment_id"`
+	Amount    int64  `json:"amount"`
+	Status    string `json:"status"`
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the payload, which is what Razorpay sends in the X-Razorpay-Signature header
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of a payload in constant time
+func Verify(secret string, payload []byte, signature string) bool {
+	if secret == "" || signature == "" {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(secret, payload)), []byte(signature))
+}